@@ -1,9 +1,54 @@
 package main
 
 import (
+	"context"
+	"flag"
 	Runner "main/src/evm/clients/geth"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 )
 
+// stringList collects the values of a repeatable flag
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
 func main() {
-	Runner.Run()
+	var args stringList
+	simulated := flag.Bool("simulated", false, "run against an in-process simulated chain instead of RPC.Url")
+	network := flag.String("network", "", "network profile from the [Networks] section of config.toml")
+	mode := flag.String("mode", "", "override Contract.Mode from config.toml")
+	method := flag.String("method", "", "override Contract.Method.Name for the call-method and read-method modes")
+	resume := flag.Bool("resume", false, "pick up where the previous run stopped, using output/journal.json")
+	dryRun := flag.Bool("dry-run", false, "sign the transactions and print them, with their gas and cost, without broadcasting them")
+	flag.Var(&args, "arg", "method argument (call-method, read-method) or constructor argument (deploy-artifact), repeat the flag for each argument")
+	flag.Parse()
+
+	// SIGINT/SIGTERM cancel the run: the pending RPC calls stop and the transactions not mined yet are listed.
+	// The signals are handled once, a second one kills the application right away.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	Runner.Run(ctx, Runner.Options{
+		Simulated: *simulated,
+		Network:   *network,
+		Mode:      *mode,
+		Method:    *method,
+		Args:      args,
+		Resume:    *resume,
+		DryRun:    *dryRun,
+	})
 }
@@ -4,32 +4,116 @@ import "math/big"
 
 // Main configuration
 type Config struct {
+	Network  string             // name of the network profile to use, optional
+	Networks map[string]Network // network profiles, keyed by name
 	RPC      RPC
 	Client   Client
 	Account  Account
 	Contract Contract
+	Load     Load
+	Offline  Offline
+	Safe     Safe
+	Message  Message
 }
 
 type RPC struct {
-	Url string
+	Url          string
+	Urls         []string // fallback endpoints, tried in order when Url keeps failing, optional
+	Retries      int      // attempts per endpoint on transient errors (timeouts, 429, 5xx), defaults to 3
+	RetryBackoff int      // delay in milliseconds before the first retry, doubled on each following one, defaults to 500
+	ChainId      uint64   // expected chain ID, the run is aborted if the node reports another one, optional
+	ExplorerUrl  string   // block explorer base URL, used to log links, optional
+
+	// Authentication, TLS and proxy settings, applied to every endpoint. At most one of
+	// BearerToken, Username/Password and JwtSecret can be set, as they all set the Authorization header
+	Headers     map[string]string // extra HTTP headers sent with each request, optional
+	BearerToken string            // sent as "Authorization: Bearer <token>", optional
+	Username    string            // HTTP basic auth, optional
+	Password    string
+	JwtSecret   string // path to a hex encoded 32 bytes secret, signs an HS256 token as the engine API does, optional
+	TlsCert     string // PEM client certificate for mTLS, requires TlsKey, optional
+	TlsKey      string
+	TlsCa       string // PEM CA certificate(s) verifying the node certificate instead of the system ones, optional
+	Proxy       string // HTTP proxy URL, the HTTP_PROXY/HTTPS_PROXY variables are used otherwise, optional
+}
+
+// Network profile, its values override the [RPC], [Client] and [Contract] ones when selected
+type Network struct {
+	Url             string
+	Urls            []string
+	ChainId         uint64
+	ContractAddress string
+	GasLimit        uint64
+	WaitingTimeout  int
+	ExplorerUrl     string
 }
 
 // Client configuration
 type Client struct {
 	GasLimit       uint64
 	WaitingTimeout int
+	SafetyMargin   int  // percent added to the estimated cost of the run, warns when the balance is below it
+	Simulate       bool // simulates each transaction with eth_call before sending it, a failing one is not sent
+	Trace          bool // traces the mined setter transactions with debug_traceTransaction
+}
+
+// Load test configuration, used by the "load-test" mode
+type Load struct {
+	Transactions int      // number of setUint256 transactions to send, defaults to 100
+	Rate         float64  // transactions sent per second, as fast as possible when 0
+	Concurrency  int      // goroutines sending the transactions, defaults to 4
+	MaxInFlight  int      // transactions sent and not mined yet at most, defaults to 50
+	PollInterval int      // milliseconds between two receipt polls, the precision of the latencies, defaults to 200
+	Keys         []string // private keys of the accounts sending along with Account.Key, optional
+}
+
+// Offline signing configuration, used by the "prepare", "sign" and "broadcast" modes
+type Offline struct {
+	Unsigned string // unsigned transactions, written by "prepare" and read by "sign", defaults to output/unsignedTransactions.json
+	Signed   string // signed transactions, written by "sign" and read by "broadcast", defaults to output/signedTransactions.json
+}
+
+// Safe configuration, used by the "safe-proposal" mode
+type Safe struct {
+	Address   string  // the Safe owning the contract, the safeTxHash of the proposal is computed when set, optional
+	Nonce     *uint64 // nonce the transaction is proposed with, read from the Safe when not set, optional
+	MultiSend string  // MultiSendCallOnly contract batching the setters, defaults to the Safe v1.3.0 deployment, optional
+}
+
+// Message signed by the "sign-message" mode and verified by the "verify-message" mode, either Text or TypedData
+type Message struct {
+	Text      string // EIP-191 personal message, as signed with personal_sign
+	TypedData string // path to EIP-712 typed data, in the JSON format eth_signTypedData_v4 takes
+	Signature string // 0x-prefixed 65 bytes signature checked by "verify-message"
+	Signer    string // address expected to have signed, the verification fails otherwise, optional
 }
 
 // Account/Wallet configuration
 type Account struct {
-	Key string
+	Key  string
+	From string // address used for eth_call in the read-only modes when no Key is configured, optional
+
+	// Test accounts derived from a mnemonic along m/44'/60'/0'/0/i, funded and swept by the "fund" and "sweep" modes
+	Mnemonic       string // BIP-39 mnemonic, optional
+	Passphrase     string // BIP-39 passphrase, optional
+	Derived        int    // number of derived accounts, defaults to 10 when Mnemonic is set
+	Funding        string // ETH balance the "fund" mode tops each derived account up to, defaults to 0.1
+	DerivedSenders bool   // sends the setters and the load test transactions from the derived accounts in turn
 }
 
 // Contract configuration
 type Contract struct {
-	Mode    string
-	Address string
-	Values  Values
+	Mode           string
+	Address        string // resolved from the deployment registry when empty, in the modes using an existing contract
+	Alias          string // name of the deployment, recorded when deploying and looked up when Address is empty, optional
+	Registry       string // deployment registry file, defaults to deployments.json
+	Create2        bool   // deploys GetterSetter through the deterministic deployment proxy, at an address only depending on Salt
+	Salt           string // CREATE2 salt, 0x-prefixed hex of up to 32 bytes, left-padded with zeros, defaults to 0x0
+	Proxy          bool   // deploys GetterSetter behind an ERC-1967 proxy, upgradeable in the "upgrade-contract" mode
+	Implementation string // implementation the proxy is upgraded to in the "upgrade-contract" mode, a new GetterSetter is deployed when empty
+	Values         Values
+	Artifact       Artifact
+	Method         Method
 }
 
 // Values to be set in the contract
@@ -38,3 +122,18 @@ type Values struct {
 	Bytes32 string
 	Bytes   string
 }
+
+// Artifact of an arbitrary contract, deployed in the "deploy-artifact" mode.
+// Its ABI is also used by the "call-method" and "read-method" modes
+type Artifact struct {
+	Path            string   // Foundry or Hardhat JSON artifact
+	Abi             string   // raw solc .abi file, used along with Bin when Path is not set
+	Bin             string   // raw solc .bin file
+	ConstructorArgs []string // constructor arguments, as strings
+}
+
+// Method of an arbitrary contract, called in the "call-method" and "read-method" modes
+type Method struct {
+	Name string   // method name, as in the ABI
+	Args []string // method arguments, as strings
+}
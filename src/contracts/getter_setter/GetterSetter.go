@@ -32,7 +32,7 @@ var (
 // GetterSetterMetaData contains all meta data concerning the GetterSetter contract.
 var GetterSetterMetaData = &bind.MetaData{
 	ABI: "[{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"bytes32\",\"name\":\"b32\",\"type\":\"bytes32\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"u256\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"bytes32\",\"name\":\"b322\",\"type\":\"bytes32\"}],\"name\":\"Output\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"bytes\",\"name\":\"value\",\"type\":\"bytes\"}],\"name\":\"SetBytes\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"bytes32\",\"name\":\"value\",\"type\":\"bytes32\"}],\"name\":\"SetBytes32\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"SetUint256\",\"type\":\"event\"},{\"inputs\":[],\"name\":\"getBytes\",\"outputs\":[{\"internalType\":\"bytes\",\"name\":\"\",\"type\":\"bytes\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getBytes32\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getUint256\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"requestId\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_requestId\",\"type\":\"bytes32\"},{\"internalType\":\"bytes\",\"name\":\"_value\",\"type\":\"bytes\"}],\"name\":\"requestedBytes\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_requestId\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"_value\",\"type\":\"bytes32\"}],\"name\":\"requestedBytes32\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_requestId\",\"type\":\"bytes32\"},{\"internalType\":\"uint256\",\"name\":\"_value\",\"type\":\"uint256\"}],\"name\":\"requestedUint256\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes\",\"name\":\"_value\",\"type\":\"bytes\"}],\"name\":\"setBytes\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_value\",\"type\":\"bytes32\"}],\"name\":\"setBytes32\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"_value\",\"type\":\"uint256\"}],\"name\":\"setUint256\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]",
-	Bin: "0x608060405234801561001057600080fd5b5061091f806100206000396000f3fe608060405234801561001057600080fd5b506004361061009d5760003560e01c806368895979116100665780636889597914610134578063c2b12a7314610152578063d2282dc51461016e578063da359dc81461018a578063ed53e511146101a65761009d565b80626d6cae146100a25780630bcd3b33146100c05780631f903037146100de5780633345b4d0146100fc57806346ddd1ff14610118575b600080fd5b6100aa6101c2565b6040516100b791906106b5565b60405180910390f35b6100c86101c8565b6040516100d591906106d0565b60405180910390f35b6100e6610256565b6040516100f391906106b5565b60405180910390f35b610116600480360381019061011191906105a8565b61025c565b005b610132600480360381019061012d919061054c565b610270565b005b61013c610284565b60405161014991906106f2565b60405180910390f35b61016c600480360381019061016791906104df565b61028a565b005b61018860048036038101906101839190610631565b6102d8565b005b6101a4600480360381019061019f91906105e8565b610326565b005b6101c060048036038101906101bb919061050c565b61038e565b005b60025481565b600380546101d5906107d5565b80601f0160208091040260200160405190810160405280929190818152602001828054610201906107d5565b801561024e5780601f106102235761010080835404028352916020019161024e565b820191906000526020600020905b81548152906001019060200180831161023157829003601f168201915b505050505081565b60005481565b8160028190555061026c816102d8565b5050565b8160028190555061028081610326565b5050565b60015481565b80600081905550803373ffffffffffffffffffffffffffffffffffffffff167fdc73ee99832252105ed74a404690c2f10ad1b294cbbeb0ff5cded48ef2aa437d60405160405180910390a350565b80600181905550803373ffffffffffffffffffffffffffffffffffffffff167fd943f063acdb1c6f206cf6a3f6d1ba39687bcc07feb7f44019bdbd4773c9c28d60405160405180910390a350565b806003908051906020019061033c9291906103a2565b503373ffffffffffffffffffffffffffffffffffffffff167ff22a519d38e59bc517532f666f8da532fdd5356e68d617191e82a8fdcc8abdcf8260405161038391906106d0565b60405180910390a250565b8160028190555061039e8161028a565b5050565b8280546103ae906107d5565b90600052602060002090601f0160209004810192826103d05760008555610417565b82601f106103e957805160ff1916838001178555610417565b82800160010185558215610417579182015b828111156104165782518255916020019190600101906103fb565b5b5090506104249190610428565b5090565b5b80821115610441576000816000905550600101610429565b5090565b600061045861045384610732565b61070d565b9050828152602081018484840111156104745761047361089b565b5b61047f848285610793565b509392505050565b600081359050610496816108bb565b92915050565b600082601f8301126104b1576104b0610896565b5b81356104c1848260208601610445565b91505092915050565b6000813590506104d9816108d2565b92915050565b6000602082840312156104f5576104f46108a5565b5b600061050384828501610487565b91505092915050565b60008060408385031215610523576105226108a5565b5b600061053185828601610487565b925050602061054285828601610487565b9150509250929050565b60008060408385031215610563576105626108a5565b5b600061057185828601610487565b925050602083013567ffffffffffffffff811115610592576105916108a0565b5b61059e8582860161049c565b9150509250929050565b600080604083850312156105bf576105be6108a5565b5b60006105cd85828601610487565b92505060206105de858286016104ca565b9150509250929050565b6000602082840312156105fe576105fd6108a5565b5b600082013567ffffffffffffffff81111561061c5761061b6108a0565b5b6106288482850161049c565b91505092915050565b600060208284031215610647576106466108a5565b5b6000610655848285016104ca565b91505092915050565b6106678161077f565b82525050565b600061067882610763565b610682818561076e565b93506106928185602086016107a2565b61069b816108aa565b840191505092915050565b6106af81610789565b82525050565b60006020820190506106ca600083018461065e565b92915050565b600060208201905081810360008301526106ea818461066d565b905092915050565b600060208201905061070760008301846106a6565b92915050565b6000610717610728565b90506107238282610807565b919050565b6000604051905090565b600067ffffffffffffffff82111561074d5761074c610867565b5b610756826108aa565b9050602081019050919050565b600081519050919050565b600082825260208201905092915050565b6000819050919050565b6000819050919050565b82818337600083830152505050565b60005b838110156107c05780820151818401526020810190506107a5565b838111156107cf576000848401525b50505050565b600060028204905060018216806107ed57607f821691505b6020821081141561080157610800610838565b5b50919050565b610810826108aa565b810181811067ffffffffffffffff8211171561082f5761082e610867565b5b80604052505050565b7f4e487b7100000000000000000000000000000000000000000000000000000000600052602260045260246000fd5b7f4e487b7100000000000000000000000000000000000000000000000000000000600052604160045260246000fd5b600080fd5b600080fd5b600080fd5b600080fd5b6000601f19601f8301169050919050565b6108c48161077f565b81146108cf57600080fd5b50565b6108db81610789565b81146108e657600080fd5b5056fea2646970667358221220e4c28180718748980fcf73c8581ea6929c884b2b1610b3a6d947a5d109ddd54664736f6c63430008060033",
+	Bin: "0x608060405234801561001057600080fd5b50610ae9806100206000396000f3fe608060405234801561001057600080fd5b506004361061009d5760003560e01c806368895979116100665780636889597914610134578063c2b12a7314610152578063d2282dc51461016e578063da359dc81461018a578063ed53e511146101a65761009d565b80626d6cae146100a25780630bcd3b33146100c05780631f903037146100de5780633345b4d0146100fc57806346ddd1ff14610118575b600080fd5b6100aa6101c2565b6040516100b791906103b4565b60405180910390f35b6100c86101c8565b6040516100d5919061045f565b60405180910390f35b6100e6610256565b6040516100f391906103b4565b60405180910390f35b610116600480360381019061011191906104f7565b61025c565b005b610132600480360381019061012d919061066c565b610270565b005b61013c610284565b60405161014991906106d7565b60405180910390f35b61016c600480360381019061016791906106f2565b61028a565b005b6101886004803603810190610183919061071f565b6102d8565b005b6101a4600480360381019061019f919061074c565b610326565b005b6101c060048036038101906101bb9190610795565b610387565b005b60025481565b600380546101d590610804565b80601f016020809104026020016040519081016040528092919081815260200182805461020190610804565b801561024e5780601f106102235761010080835404028352916020019161024e565b820191906000526020600020905b81548152906001019060200180831161023157829003601f168201915b505050505081565b60005481565b8160028190555061026c816102d8565b5050565b8160028190555061028081610326565b5050565b60015481565b80600081905550803373ffffffffffffffffffffffffffffffffffffffff167fdc73ee99832252105ed74a404690c2f10ad1b294cbbeb0ff5cded48ef2aa437d60405160405180910390a350565b80600181905550803373ffffffffffffffffffffffffffffffffffffffff167fd943f063acdb1c6f206cf6a3f6d1ba39687bcc07feb7f44019bdbd4773c9c28d60405160405180910390a350565b806003908161033591906109e1565b503373ffffffffffffffffffffffffffffffffffffffff167ff22a519d38e59bc517532f666f8da532fdd5356e68d617191e82a8fdcc8abdcf8260405161037c919061045f565b60405180910390a250565b816002819055506103978161028a565b5050565b6000819050919050565b6103ae8161039b565b82525050565b60006020820190506103c960008301846103a5565b92915050565b600081519050919050565b600082825260208201905092915050565b60005b838110156104095780820151818401526020810190506103ee565b60008484015250505050565b6000601f19601f8301169050919050565b6000610431826103cf565b61043b81856103da565b935061044b8185602086016103eb565b61045481610415565b840191505092915050565b600060208201905081810360008301526104798184610426565b905092915050565b6000604051905090565b600080fd5b600080fd5b61049e8161039b565b81146104a957600080fd5b50565b6000813590506104bb81610495565b92915050565b6000819050919050565b6104d4816104c1565b81146104df57600080fd5b50565b6000813590506104f1816104cb565b92915050565b6000806040838503121561050e5761050d61048b565b5b600061051c858286016104ac565b925050602061052d858286016104e2565b9150509250929050565b600080fd5b600080fd5b7f4e487b7100000000000000000000000000000000000000000000000000000000600052604160045260246000fd5b61057982610415565b810181811067ffffffffffffffff8211171561059857610597610541565b5b80604052505050565b60006105ab610481565b90506105b78282610570565b919050565b600067ffffffffffffffff8211156105d7576105d6610541565b5b6105e082610415565b9050602081019050919050565b82818337600083830152505050565b600061060f61060a846105bc565b6105a1565b90508281526020810184848401111561062b5761062a61053c565b5b6106368482856105ed565b509392505050565b600082601f83011261065357610652610537565b5b81356106638482602086016105fc565b91505092915050565b600080604083850312156106835761068261048b565b5b6000610691858286016104ac565b925050602083013567ffffffffffffffff8111156106b2576106b1610490565b5b6106be8582860161063e565b9150509250929050565b6106d1816104c1565b82525050565b60006020820190506106ec60008301846106c8565b92915050565b6000602082840312156107085761070761048b565b5b6000610716848285016104ac565b91505092915050565b6000602082840312156107355761073461048b565b5b6000610743848285016104e2565b91505092915050565b6000602082840312156107625761076161048b565b5b600082013567ffffffffffffffff8111156107805761077f610490565b5b61078c8482850161063e565b91505092915050565b600080604083850312156107ac576107ab61048b565b5b60006107ba858286016104ac565b92505060206107cb858286016104ac565b9150509250929050565b7f4e487b7100000000000000000000000000000000000000000000000000000000600052602260045260246000fd5b6000600282049050600182168061081c57607f821691505b60208210810361082f5761082e6107d5565b5b50919050565b60008190508160005260206000209050919050565b60006020601f8301049050919050565b600082821b905092915050565b6000600883026108977fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff8261085a565b6108a1868361085a565b95508019841693508086168417925050509392505050565b6000819050919050565b60006108de6108d96108d4846104c1565b6108b9565b6104c1565b9050919050565b6000819050919050565b6108f8836108c3565b61090c610904826108e5565b848454610867565b825550505050565b600090565b610921610914565b61092c8184846108ef565b505050565b5b8181101561095057610945600082610919565b600181019050610932565b5050565b601f8211156109955761096681610835565b61096f8461084a565b8101602085101561097e578190505b61099261098a8561084a565b830182610931565b50505b505050565b600082821c905092915050565b60006109b86000198460080261099a565b1980831691505092915050565b60006109d183836109a7565b9150826002028217905092915050565b6109ea826103cf565b67ffffffffffffffff811115610a0357610a02610541565b5b610a0d8254610804565b610a18828285610954565b600060209050601f831160018114610a4b5760008415610a39578287015190505b610a4385826109c5565b865550610aab565b601f198416610a5986610835565b60005b82811015610a8157848901518255600182019150602085019450602081019050610a5c565b86831015610a9e5784890151610a9a601f8916826109a7565b8355505b6001600288020188555050505b50505050505056fea264697066735822122087c71aa1e4f853fb81a5cb36a8eaa7fcd37576cbaec8440778ccf8d585356de364736f6c63430008150033",
 }
 
 // GetterSetterABI is the input ABI used to generate the binding from.
@@ -1,4 +1,4 @@
-pragma solidity 0.8.6;
+pragma solidity 0.8.21;
 
 // GetterSetter is a contract to aid debugging and testing during development.
 contract GetterSetter {
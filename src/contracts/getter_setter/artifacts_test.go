@@ -0,0 +1,135 @@
+package getter_setter
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"main/src/evm/clients/geth/artifacts"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// readArtifacts reads the .abi, decoded as JSON, and the .bin of GetterSetter in the directory
+func readArtifacts(t *testing.T, dir string) (interface{}, []byte) {
+	abiContent, err := os.ReadFile(filepath.Join(dir, "GetterSetter.abi"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var abiJSON interface{}
+	if err := json.Unmarshal(abiContent, &abiJSON); err != nil {
+		t.Fatalf("GetterSetter.abi in %s: %v", dir, err)
+	}
+	binContent, err := os.ReadFile(filepath.Join(dir, "GetterSetter.bin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return abiJSON, common.FromHex(strings.TrimSpace(string(binContent)))
+}
+
+// The binding must be generated from the committed artifacts, see `make contracts`
+func TestBindingMatchesArtifacts(t *testing.T) {
+	abiJSON, bin := readArtifacts(t, ".")
+	if len(bin) == 0 {
+		t.Fatal("GetterSetter.bin is empty")
+	}
+	if !bytes.Equal(common.FromHex(GetterSetterMetaData.Bin), bin) {
+		t.Error("GetterSetterMetaData.Bin differs from GetterSetter.bin, run `make contracts`")
+	}
+	var bindingABI interface{}
+	if err := json.Unmarshal([]byte(GetterSetterMetaData.ABI), &bindingABI); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(bindingABI, abiJSON) {
+		t.Error("GetterSetterMetaData.ABI differs from GetterSetter.abi, run `make contracts`")
+	}
+}
+
+// solcMetadata holds the fields of GetterSetter_meta.json which pin the build
+type solcMetadata struct {
+	Compiler struct {
+		Version string `json:"version"`
+	} `json:"compiler"`
+	Settings struct {
+		EVMVersion string `json:"evmVersion"`
+		Metadata   struct {
+			BytecodeHash string `json:"bytecodeHash"`
+		} `json:"metadata"`
+		Optimizer struct {
+			Enabled bool `json:"enabled"`
+		} `json:"optimizer"`
+	} `json:"settings"`
+	Sources map[string]struct {
+		Keccak256 string `json:"keccak256"`
+	} `json:"sources"`
+}
+
+// GetterSetter_meta.json records the compiler and the settings of the committed GetterSetter.bin:
+// its IPFS hash is the one in the bytecode and it references the committed GetterSetter.sol
+func TestMetadataMatchesArtifacts(t *testing.T) {
+	_, bin := readArtifacts(t, ".")
+	content, err := os.ReadFile("GetterSetter_meta.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var metadata solcMetadata
+	if err := json.Unmarshal(content, &metadata); err != nil {
+		t.Fatal(err)
+	}
+	if metadata.Compiler.Version != "0.8.21+commit.d9974bed" || metadata.Settings.EVMVersion != "paris" ||
+		metadata.Settings.Optimizer.Enabled || metadata.Settings.Metadata.BytecodeHash != "ipfs" {
+		t.Errorf("GetterSetter_meta.json doesn't record the pinned solc 0.8.21 and SOLC_FLAGS: %+v", metadata)
+	}
+
+	source, err := os.ReadFile("GetterSetter.sol")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := metadata.Sources["GetterSetter.sol"].Keccak256; got != crypto.Keccak256Hash(source).Hex() {
+		t.Errorf("GetterSetter_meta.json was built from another GetterSetter.sol (keccak256 %s), run `make contracts`", got)
+	}
+
+	// The trailer is the CBOR map {"ipfs": <34 bytes>, "solc": <3 bytes>} followed by its length
+	trailer := bin[len(artifacts.StripMetadata(bin)):]
+	expected := append(append([]byte{0xa2, 0x64, 'i', 'p', 'f', 's', 0x58, 0x22}, artifacts.IPFSHash(content)...),
+		0x64, 's', 'o', 'l', 'c', 0x43, 0, 8, 21, 0, 0x33)
+	if !bytes.Equal(trailer, expected) {
+		t.Errorf("the metadata of GetterSetter.bin is %x, GetterSetter_meta.json gives %x", trailer, expected)
+	}
+}
+
+// The committed artifacts must be reproduced by the pinned solc. It needs solc, so it is run by
+// `make check-contracts`, which compiles GetterSetter.sol to CONTRACTS_BUILD_DIR.
+func TestBuildReproducesArtifacts(t *testing.T) {
+	buildDir := os.Getenv("CONTRACTS_BUILD_DIR")
+	if buildDir == "" {
+		t.Skip("CONTRACTS_BUILD_DIR is not set, run `make check-contracts`")
+	}
+	committedABI, committedBin := readArtifacts(t, ".")
+	builtABI, builtBin := readArtifacts(t, buildDir)
+
+	if !reflect.DeepEqual(builtABI, committedABI) {
+		t.Error("the compiled ABI differs from GetterSetter.abi")
+	}
+	// The metadata hash changes with the settings which don't affect the code, it is reported on its own
+	if !bytes.Equal(artifacts.StripMetadata(builtBin), artifacts.StripMetadata(committedBin)) {
+		t.Fatal("the compiled bytecode differs from GetterSetter.bin, check the solc version and SOLC_FLAGS")
+	}
+	if !bytes.Equal(builtBin, committedBin) {
+		t.Error("the compiled bytecode matches GetterSetter.bin, but not its metadata hash: the source or its path differs")
+	}
+	builtMetadata, err := os.ReadFile(filepath.Join(buildDir, "GetterSetter_meta.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	committedMetadata, err := os.ReadFile("GetterSetter_meta.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(builtMetadata, committedMetadata) {
+		t.Error("the compiled metadata differs from GetterSetter_meta.json")
+	}
+}
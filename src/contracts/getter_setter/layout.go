@@ -0,0 +1,42 @@
+package getter_setter
+
+import (
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"math/big"
+)
+
+// The storage layout of GetterSetter, as solc lays it out: one slot per state variable, in declaration order.
+// The getBytes slot holds a short value (up to 31 bytes) with its length, or the length of a long one,
+// which is stored from keccak256(BytesSlot), 32 bytes per slot.
+var (
+	Bytes32Slot   = common.BigToHash(big.NewInt(0))
+	Uint256Slot   = common.BigToHash(big.NewInt(1))
+	RequestIdSlot = common.BigToHash(big.NewInt(2))
+	BytesSlot     = common.BigToHash(big.NewInt(3))
+	// BytesDataSlot is the first slot of a long getBytes value
+	BytesDataSlot = crypto.Keccak256Hash(BytesSlot.Bytes())
+)
+
+// MaxBytesDataSlots bounds the data slots of a getBytes value which are looked at, i.e. values up to 1 MiB
+const MaxBytesDataSlots = 1 << 15
+
+// SlotName returns the name of the getter of the variable stored in the slot, "" if the slot is not used
+func SlotName(slot common.Hash) string {
+	switch slot {
+	case Bytes32Slot:
+		return "getBytes32"
+	case Uint256Slot:
+		return "getUint256"
+	case RequestIdSlot:
+		return "requestId"
+	case BytesSlot:
+		return "getBytes"
+	}
+	offset := new(big.Int).Sub(slot.Big(), BytesDataSlot.Big())
+	if offset.Sign() >= 0 && offset.Cmp(big.NewInt(MaxBytesDataSlots)) < 0 {
+		return fmt.Sprintf("getBytes data[%d]", offset.Int64())
+	}
+	return ""
+}
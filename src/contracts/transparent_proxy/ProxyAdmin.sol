@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: MIT
+pragma solidity 0.8.21;
+
+import "./TransparentUpgradeableProxy.sol";
+
+// ProxyAdmin is the admin of TransparentUpgradeableProxy instances, as in OpenZeppelin Contracts v4.9.6, with
+// its Ownable base folded in: the owner, the deployer at first, upgrades the proxies through it.
+contract ProxyAdmin {
+  address private _owner;
+
+  event OwnershipTransferred(address indexed previousOwner, address indexed newOwner);
+
+  constructor() {
+    _transferOwnership(msg.sender);
+  }
+
+  modifier onlyOwner() {
+    require(owner() == msg.sender, "Ownable: caller is not the owner");
+    _;
+  }
+
+  function owner() public view returns (address) {
+    return _owner;
+  }
+
+  function renounceOwnership() public onlyOwner {
+    _transferOwnership(address(0));
+  }
+
+  function transferOwnership(address newOwner) public onlyOwner {
+    require(newOwner != address(0), "Ownable: new owner is the zero address");
+    _transferOwnership(newOwner);
+  }
+
+  // Returns the current implementation of the proxy, which only answers implementation() to its admin
+  function getProxyImplementation(ITransparentUpgradeableProxy proxy) public view returns (address) {
+    // bytes4(keccak256("implementation()")) == 0x5c60da1b
+    (bool success, bytes memory returndata) = address(proxy).staticcall(hex"5c60da1b");
+    require(success);
+    return abi.decode(returndata, (address));
+  }
+
+  // Returns the current admin of the proxy, which only answers admin() to its admin
+  function getProxyAdmin(ITransparentUpgradeableProxy proxy) public view returns (address) {
+    // bytes4(keccak256("admin()")) == 0xf851a440
+    (bool success, bytes memory returndata) = address(proxy).staticcall(hex"f851a440");
+    require(success);
+    return abi.decode(returndata, (address));
+  }
+
+  function changeProxyAdmin(ITransparentUpgradeableProxy proxy, address newAdmin) public onlyOwner {
+    proxy.changeAdmin(newAdmin);
+  }
+
+  function upgrade(ITransparentUpgradeableProxy proxy, address implementation) public onlyOwner {
+    proxy.upgradeTo(implementation);
+  }
+
+  // Upgrades the proxy and calls the new implementation with data, e.g. to initialize it, forwarding the value
+  function upgradeAndCall(ITransparentUpgradeableProxy proxy, address implementation, bytes memory data) public payable onlyOwner {
+    proxy.upgradeToAndCall{value: msg.value}(implementation, data);
+  }
+
+  function _transferOwnership(address newOwner) private {
+    address oldOwner = _owner;
+    _owner = newOwner;
+    emit OwnershipTransferred(oldOwner, newOwner);
+  }
+}
@@ -0,0 +1,162 @@
+// SPDX-License-Identifier: MIT
+pragma solidity 0.8.21;
+
+// The interface of the proxy, as in OpenZeppelin Contracts v4.9.6. The functions are only dispatched
+// for the admin, by the fallback of the proxy, so they are not part of the proxy's ABI.
+interface ITransparentUpgradeableProxy {
+  event Upgraded(address indexed implementation);
+  event AdminChanged(address previousAdmin, address newAdmin);
+  event BeaconUpgraded(address indexed beacon);
+
+  function admin() external view returns (address);
+
+  function implementation() external view returns (address);
+
+  function changeAdmin(address) external;
+
+  function upgradeTo(address) external;
+
+  function upgradeToAndCall(address, bytes memory) external payable;
+}
+
+// TransparentUpgradeableProxy is the ERC-1967 transparent proxy of OpenZeppelin Contracts v4.9.6, with its
+// base contracts (Proxy, ERC1967Proxy, ERC1967Upgrade) folded in: the same constructor, events, storage slots,
+// revert messages and admin dispatch. The calls of the admin, a ProxyAdmin, are never delegated, the calls of
+// every other account always are, so the implementation's functions can't be shadowed by the proxy's.
+contract TransparentUpgradeableProxy {
+  // keccak256("eip1967.proxy.implementation") - 1 and keccak256("eip1967.proxy.admin") - 1
+  bytes32 internal constant _IMPLEMENTATION_SLOT = 0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc;
+  bytes32 internal constant _ADMIN_SLOT = 0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103;
+
+  event Upgraded(address indexed implementation);
+  event AdminChanged(address previousAdmin, address newAdmin);
+  event BeaconUpgraded(address indexed beacon);
+
+  // Points the proxy at _logic, initialized with a delegate call of _data unless it is empty, administered by admin_
+  constructor(address _logic, address admin_, bytes memory _data) payable {
+    _upgradeToAndCall(_logic, _data, false);
+    _changeAdmin(admin_);
+  }
+
+  fallback() external payable {
+    _fallback();
+  }
+
+  receive() external payable {
+    _fallback();
+  }
+
+  function _fallback() internal {
+    if (msg.sender != _getAdmin()) {
+      _delegate(_getImplementation());
+    }
+    bytes memory ret;
+    bytes4 selector = msg.sig;
+    if (selector == ITransparentUpgradeableProxy.upgradeTo.selector) {
+      ret = _dispatchUpgradeTo();
+    } else if (selector == ITransparentUpgradeableProxy.upgradeToAndCall.selector) {
+      ret = _dispatchUpgradeToAndCall();
+    } else if (selector == ITransparentUpgradeableProxy.changeAdmin.selector) {
+      ret = _dispatchChangeAdmin();
+    } else if (selector == ITransparentUpgradeableProxy.admin.selector) {
+      ret = _dispatchAdmin();
+    } else if (selector == ITransparentUpgradeableProxy.implementation.selector) {
+      ret = _dispatchImplementation();
+    } else {
+      revert("TransparentUpgradeableProxy: admin cannot fallback to proxy target");
+    }
+    assembly {
+      return(add(ret, 0x20), mload(ret))
+    }
+  }
+
+  function _dispatchAdmin() private returns (bytes memory) {
+    _requireZeroValue();
+    return abi.encode(_getAdmin());
+  }
+
+  function _dispatchImplementation() private returns (bytes memory) {
+    _requireZeroValue();
+    return abi.encode(_getImplementation());
+  }
+
+  function _dispatchChangeAdmin() private returns (bytes memory) {
+    _requireZeroValue();
+    address newAdmin = abi.decode(msg.data[4:], (address));
+    _changeAdmin(newAdmin);
+    return "";
+  }
+
+  function _dispatchUpgradeTo() private returns (bytes memory) {
+    _requireZeroValue();
+    address newImplementation = abi.decode(msg.data[4:], (address));
+    _upgradeToAndCall(newImplementation, bytes(""), false);
+    return "";
+  }
+
+  function _dispatchUpgradeToAndCall() private returns (bytes memory) {
+    (address newImplementation, bytes memory data) = abi.decode(msg.data[4:], (address, bytes));
+    _upgradeToAndCall(newImplementation, data, true);
+    return "";
+  }
+
+  // The admin functions are not payable, except upgradeToAndCall which forwards the value to the call
+  function _requireZeroValue() private {
+    require(msg.value == 0);
+  }
+
+  function _getImplementation() private view returns (address implementation) {
+    assembly {
+      implementation := sload(_IMPLEMENTATION_SLOT)
+    }
+  }
+
+  function _getAdmin() private view returns (address admin) {
+    assembly {
+      admin := sload(_ADMIN_SLOT)
+    }
+  }
+
+  function _upgradeToAndCall(address newImplementation, bytes memory data, bool forceCall) private {
+    require(newImplementation.code.length > 0, "ERC1967: new implementation is not a contract");
+    assembly {
+      sstore(_IMPLEMENTATION_SLOT, newImplementation)
+    }
+    emit Upgraded(newImplementation);
+    if (data.length > 0 || forceCall) {
+      (bool success, bytes memory returndata) = newImplementation.delegatecall(data);
+      if (!success) {
+        // Bubbles the revert reason of the call up
+        if (returndata.length > 0) {
+          assembly {
+            revert(add(32, returndata), mload(returndata))
+          }
+        }
+        revert("Address: low-level delegate call failed");
+      }
+    }
+  }
+
+  function _changeAdmin(address newAdmin) private {
+    require(newAdmin != address(0), "ERC1967: new admin is the zero address");
+    emit AdminChanged(_getAdmin(), newAdmin);
+    assembly {
+      sstore(_ADMIN_SLOT, newAdmin)
+    }
+  }
+
+  function _delegate(address implementation) private {
+    assembly {
+      calldatacopy(0, 0, calldatasize())
+      let result := delegatecall(gas(), implementation, 0, calldatasize(), 0, 0)
+      returndatacopy(0, 0, returndatasize())
+      switch result
+      case 0 {
+        revert(0, returndatasize())
+      }
+      default {
+        return(0, returndatasize())
+      }
+    }
+  }
+}
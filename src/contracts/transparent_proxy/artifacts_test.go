@@ -0,0 +1,179 @@
+package transparent_proxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"main/src/evm/clients/geth/artifacts"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// contracts are the compiled contracts of the package, with the sources they are built from
+var contracts = []struct {
+	name     string
+	metaData *bind.MetaData
+	runtime  func() []byte
+	sources  []string
+}{
+	{name: "TransparentUpgradeableProxy", metaData: TransparentUpgradeableProxyMetaData, runtime: TransparentUpgradeableProxyRuntimeCode, sources: []string{"TransparentUpgradeableProxy.sol"}},
+	{name: "ProxyAdmin", metaData: ProxyAdminMetaData, runtime: ProxyAdminRuntimeCode, sources: []string{"ProxyAdmin.sol", "TransparentUpgradeableProxy.sol"}},
+}
+
+// readArtifact reads the .abi, decoded as JSON without whitespace, and the .bin of the contract in the directory
+func readArtifact(t *testing.T, dir string, name string) (interface{}, []byte) {
+	abiContent, err := os.ReadFile(filepath.Join(dir, name+".abi"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// abigen drops the whitespace of the ABI, e.g. in the internal type "contract ITransparentUpgradeableProxy"
+	var abiJSON interface{}
+	if err := json.Unmarshal([]byte(strings.Join(strings.Fields(string(abiContent)), "")), &abiJSON); err != nil {
+		t.Fatalf("%s.abi in %s: %v", name, dir, err)
+	}
+	binContent, err := os.ReadFile(filepath.Join(dir, name+".bin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return abiJSON, common.FromHex(strings.TrimSpace(string(binContent)))
+}
+
+// The bindings and the runtime code must be the ones of the committed artifacts, see `make contracts`
+func TestBindingsMatchArtifacts(t *testing.T) {
+	for _, contract := range contracts {
+		t.Run(contract.name, func(t *testing.T) {
+			abiJSON, bin := readArtifact(t, ".", contract.name)
+			if len(bin) == 0 {
+				t.Fatalf("%s.bin is empty", contract.name)
+			}
+			if !bytes.Equal(common.FromHex(contract.metaData.Bin), bin) {
+				t.Errorf("the binding's Bin differs from %s.bin, run `make contracts`", contract.name)
+			}
+			var bindingABI interface{}
+			if err := json.Unmarshal([]byte(contract.metaData.ABI), &bindingABI); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(bindingABI, abiJSON) {
+				t.Errorf("the binding's ABI differs from %s.abi, run `make contracts`", contract.name)
+			}
+			// The contracts have no immutables, the runtime code is copied as is by the constructor
+			if runtime := contract.runtime(); len(runtime) == 0 || !bytes.Contains(bin, runtime) {
+				t.Errorf("%s.bin-runtime is not the runtime code of %s.bin", contract.name, contract.name)
+			}
+		})
+	}
+}
+
+// The proxy's slots are the ERC-1967 ones, the constants in its source
+func TestSlotsAreERC1967(t *testing.T) {
+	for label, slot := range map[string]common.Hash{"eip1967.proxy.implementation": ImplementationSlot, "eip1967.proxy.admin": AdminSlot} {
+		want := common.BigToHash(new(big.Int).Sub(crypto.Keccak256Hash([]byte(label)).Big(), common.Big1))
+		if slot != want {
+			t.Errorf("slot of %s = %s, want %s", label, slot.Hex(), want.Hex())
+		}
+		if !bytes.Contains(TransparentUpgradeableProxyRuntimeCode(), slot.Bytes()) {
+			t.Errorf("the runtime code of TransparentUpgradeableProxy doesn't use the slot of %s", label)
+		}
+	}
+}
+
+// solcMetadata holds the fields of the _meta.json files which pin the build
+type solcMetadata struct {
+	Compiler struct {
+		Version string `json:"version"`
+	} `json:"compiler"`
+	Settings struct {
+		EVMVersion string `json:"evmVersion"`
+		Metadata   struct {
+			BytecodeHash string `json:"bytecodeHash"`
+		} `json:"metadata"`
+		Optimizer struct {
+			Enabled bool `json:"enabled"`
+		} `json:"optimizer"`
+	} `json:"settings"`
+	Sources map[string]struct {
+		Keccak256 string `json:"keccak256"`
+	} `json:"sources"`
+}
+
+// The _meta.json files record the compiler and the settings of the committed .bin files: their IPFS hash
+// is the one in the bytecode and they reference the committed sources
+func TestMetadataMatchesArtifacts(t *testing.T) {
+	for _, contract := range contracts {
+		t.Run(contract.name, func(t *testing.T) {
+			_, bin := readArtifact(t, ".", contract.name)
+			content, err := os.ReadFile(contract.name + "_meta.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			var metadata solcMetadata
+			if err := json.Unmarshal(content, &metadata); err != nil {
+				t.Fatal(err)
+			}
+			if metadata.Compiler.Version != "0.8.21+commit.d9974bed" || metadata.Settings.EVMVersion != "paris" ||
+				metadata.Settings.Optimizer.Enabled || metadata.Settings.Metadata.BytecodeHash != "ipfs" {
+				t.Errorf("%s_meta.json doesn't record the pinned solc 0.8.21 and SOLC_FLAGS: %+v", contract.name, metadata)
+			}
+			if len(metadata.Sources) != len(contract.sources) {
+				t.Errorf("%s_meta.json has %d sources, want %v", contract.name, len(metadata.Sources), contract.sources)
+			}
+			for _, name := range contract.sources {
+				source, err := os.ReadFile(name)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := metadata.Sources[name].Keccak256; got != crypto.Keccak256Hash(source).Hex() {
+					t.Errorf("%s_meta.json was built from another %s (keccak256 %s), run `make contracts`", contract.name, name, got)
+				}
+			}
+
+			// The trailer is the CBOR map {"ipfs": <34 bytes>, "solc": <3 bytes>} followed by its length
+			trailer := bin[len(artifacts.StripMetadata(bin)):]
+			expected := append(append([]byte{0xa2, 0x64, 'i', 'p', 'f', 's', 0x58, 0x22}, artifacts.IPFSHash(content)...),
+				0x64, 's', 'o', 'l', 'c', 0x43, 0, 8, 21, 0, 0x33)
+			if !bytes.Equal(trailer, expected) {
+				t.Errorf("the metadata of %s.bin is %x, %s_meta.json gives %x", contract.name, trailer, contract.name, expected)
+			}
+		})
+	}
+}
+
+// The committed artifacts must be reproduced by the pinned solc. It needs solc, so it is run by
+// `make check-contracts`, which compiles the sources to CONTRACTS_BUILD_DIR.
+func TestBuildReproducesArtifacts(t *testing.T) {
+	buildDir := os.Getenv("CONTRACTS_BUILD_DIR")
+	if buildDir == "" {
+		t.Skip("CONTRACTS_BUILD_DIR is not set, run `make check-contracts`")
+	}
+	for _, contract := range contracts {
+		t.Run(contract.name, func(t *testing.T) {
+			committedABI, committedBin := readArtifact(t, ".", contract.name)
+			builtABI, builtBin := readArtifact(t, buildDir, contract.name)
+			if !reflect.DeepEqual(builtABI, committedABI) {
+				t.Errorf("the compiled ABI differs from %s.abi", contract.name)
+			}
+			if !bytes.Equal(artifacts.StripMetadata(builtBin), artifacts.StripMetadata(committedBin)) {
+				t.Fatalf("the compiled bytecode differs from %s.bin, check the solc version and SOLC_FLAGS", contract.name)
+			}
+			for _, file := range []string{".bin", ".bin-runtime", "_meta.json"} {
+				built, err := os.ReadFile(filepath.Join(buildDir, contract.name+file))
+				if err != nil {
+					t.Fatal(err)
+				}
+				committed, err := os.ReadFile(contract.name + file)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(bytes.TrimSpace(built), bytes.TrimSpace(committed)) {
+					t.Errorf("the compiled %s%s differs from the committed one: the source or its path differs", contract.name, file)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package transparent_proxy
+
+import (
+	_ "embed"
+	"github.com/ethereum/go-ethereum/common"
+	"strings"
+)
+
+// The storage layout of the proxies: the ERC-1967 slots of TransparentUpgradeableProxy, keccak256("eip1967.proxy.implementation") - 1
+// and keccak256("eip1967.proxy.admin") - 1, and the owner of ProxyAdmin, its only state variable
+var (
+	ImplementationSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+	AdminSlot          = common.HexToHash("0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103")
+	OwnerSlot          = common.Hash{}
+)
+
+// The runtime code of the contracts, as compiled with their creation code, used to seed the simulated chain
+var (
+	//go:embed TransparentUpgradeableProxy.bin-runtime
+	transparentUpgradeableProxyRuntime string
+	//go:embed ProxyAdmin.bin-runtime
+	proxyAdminRuntime string
+)
+
+// TransparentUpgradeableProxyRuntimeCode returns the code stored at the address of a TransparentUpgradeableProxy
+func TransparentUpgradeableProxyRuntimeCode() []byte {
+	return common.FromHex(strings.TrimSpace(transparentUpgradeableProxyRuntime))
+}
+
+// ProxyAdminRuntimeCode returns the code stored at the address of a ProxyAdmin
+func ProxyAdminRuntimeCode() []byte {
+	return common.FromHex(strings.TrimSpace(proxyAdminRuntime))
+}
@@ -1,67 +1,348 @@
 package geth
 
 import (
+	"bytes"
+	"context"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"log"
 	"main/src/config"
 	"main/src/contracts/getter_setter"
 	"main/src/evm/clients/geth/account"
+	"main/src/evm/clients/geth/artifacts"
+	"main/src/evm/clients/geth/backend"
 	"main/src/evm/clients/geth/client"
 	"main/src/evm/clients/geth/dto"
+	"main/src/evm/clients/geth/reverts"
 	"main/src/evm/clients/geth/transactions"
 	"main/src/evm/clients/geth/types"
 	"main/src/utils"
 	"math/big"
+	"strings"
 )
 
 // Main values used by the application
 var (
-	tomlConfig           = utils.GetConfig()
-	rpcURL               = tomlConfig.RPC.Url
-	privateKey           = tomlConfig.Account.Key
-	contractAddress      = tomlConfig.Contract.Address
-	mode                 = tomlConfig.Contract.Mode
-	values               = tomlConfig.Contract.Values
-	gasLimit             = tomlConfig.Client.GasLimit
-	timeout              = tomlConfig.Client.WaitingTimeout
-	ethClient            = client.ConnectClient(rpcURL)
-	deployerAddress      = account.GetDeployerAddressFromPrivateKey(privateKey)
+	tomlConfig           config.Config
+	rpcURL               string
+	privateKey           string
+	contractAddress      string
+	mode                 string
+	values               config.Values
+	gasLimit             uint64
+	timeout              int
+	explorerURL          string
+	ethClient            backend.Client
+	deployerAddress      common.Address
+	derivedAccounts      []sender // accounts derived from Account.Mnemonic
 	getterSetterContract *getter_setter.GetterSetter
 )
 
-func Run() {
-	// Validate if the account is sufficiently funded
-	account.ValidateBalanceFunded(deployerAddress, ethClient)
+// Options holds the command line flags which override or extend config.toml
+type Options struct {
+	Simulated bool     // run against an in-process simulated chain instead of RPC.Url
+	Network   string   // overrides Network, the network profile to use
+	Mode      string   // overrides Contract.Mode
+	Method    string   // overrides Contract.Method.Name
+	Args      []string // overrides Contract.Method.Args in the method modes, Contract.Artifact.ConstructorArgs otherwise
+	Resume    bool     // picks up where the previous run stopped, using its journal
+	DryRun    bool     // signs the transactions of the run without broadcasting them
+}
+
+// apply writes the command line options over the values read from config.toml
+func (options Options) apply(c *config.Config) {
+	// The network profile is applied first, so the other flags take precedence over it
+	if options.Network != "" {
+		c.Network = options.Network
+	}
+	if err := utils.ApplyNetworkProfile(c); err != nil {
+		utils.Fatal(err)
+	}
+	if options.Simulated {
+		c.RPC.Url = utils.SIMULATED_RPC_URL
+	}
+	if options.Mode != "" {
+		c.Contract.Mode = options.Mode
+	}
+	if options.Method != "" {
+		c.Contract.Method.Name = options.Method
+	}
+	if len(options.Args) > 0 {
+		if c.Contract.Mode == utils.METHOD_MODE || c.Contract.Mode == utils.VIEW_MODE {
+			c.Contract.Method.Args = options.Args
+		} else {
+			c.Contract.Artifact.ConstructorArgs = options.Args
+		}
+	}
+}
+
+// setup reads config.toml, applies the command line options and connects to the client.
+func setup(ctx context.Context, options Options) {
+	tomlConfig = utils.GetConfig(options.apply)
+	rpcURL = tomlConfig.RPC.Url
+	privateKey = tomlConfig.Account.Key
+	contractAddress = tomlConfig.Contract.Address
+	mode = tomlConfig.Contract.Mode
+	values = tomlConfig.Contract.Values
+	gasLimit = tomlConfig.Client.GasLimit
+	timeout = tomlConfig.Client.WaitingTimeout
+	explorerURL = strings.TrimSuffix(tomlConfig.RPC.ExplorerUrl, "/")
+	// Registered before the connection, so the failed requests of an unreachable node are reported too
+	utils.AtExit(writeRunReport)
+	// The read-only modes work without a key, eth_call is then made from Account.From (if any)
+	if privateKey != "" {
+		deployerAddress = account.GetDeployerAddressFromPrivateKey(privateKey)
+	} else if tomlConfig.Account.From != "" {
+		deployerAddress = common.HexToAddress(tomlConfig.Account.From)
+		log.Println("No Account.key configured, reading from:", deployerAddress)
+	} else {
+		log.Println("No Account.key configured, reading without a sender address")
+	}
+
+	derivedAccounts = deriveAccounts(tomlConfig.Account)
+
+	// The transactions and the messages are signed on a machine without network access, nothing is read from the chain
+	if !utils.RequiresNode(mode) {
+		log.Println("Running offline, no connection to the node is made")
+		return
+	}
+
+	if rpcURL == utils.SIMULATED_RPC_URL {
+		// Only the modes working with an existing contract get it seeded into the simulated chain,
+		// a deployment would otherwise collide with it
+		seededAddress := ""
+		if utils.UsesExistingGetterSetter(mode) {
+			seededAddress = contractAddress
+		}
+		if mode == utils.UPGRADE_MODE {
+			ethClient = client.ConnectSimulatedProxyClient(deployerAddress, contractAddress)
+		} else {
+			ethClient = client.ConnectSimulatedClient(deployerAddress, seededAddress)
+		}
+	} else {
+		failoverClient := client.NewClient(tomlConfig.RPC)
+		// Set before connecting, so the run report lists the failed requests when no endpoint answers
+		ethClient = failoverClient
+		client.ConnectClient(ctx, failoverClient)
+	}
+
+	// Guard against an RPC URL pointing to another network than the selected one
+	if tomlConfig.RPC.ChainId != 0 {
+		if rpcURL == utils.SIMULATED_RPC_URL {
+			log.Printf("Skipping the chain ID check (expected: %d), the simulated chain always runs with chain ID 1337", tomlConfig.RPC.ChainId)
+		} else {
+			transactions.ValidateChainId(ctx, ethClient, tomlConfig.RPC.ChainId)
+		}
+	}
+
+	// Only Account.Key is funded on the simulated chain, the derived senders are funded from it
+	if rpcURL == utils.SIMULATED_RPC_URL && tomlConfig.Account.DerivedSenders && mode != utils.FUND_MODE && mode != utils.SWEEP_MODE {
+		target, _ := utils.EtherToWei(tomlConfig.Account.Funding) // checked by the config validation
+		FundDerivedAccounts(ctx, deployerAddress, privateKey, derivedAccounts, target, ethClient, timeout)
+	}
+
+	// Checked by the config validation, the simulated chain has no registry
+	if contractAddress == "" && utils.UsesExistingContract(mode) {
+		resolveContractAddress(ctx)
+	}
+}
+
+// logExplorerLink logs the block explorer link of a transaction ("tx") or an address ("address"),
+// if an explorer URL is configured.
+func logExplorerLink(kind string, value string) {
+	if explorerURL != "" {
+		log.Printf("Explorer: %s/%s/%s", explorerURL, kind, value)
+	}
+}
+
+// writeRunReport writes the run report, including which RPC endpoint served each request
+func writeRunReport() {
+	report := types.RunReport{Mode: mode, Reverts: reverts.Reverts()}
+	if recorder, ok := backend.Unwrap(ethClient).(backend.RequestRecorder); ok {
+		report.RpcRequests = recorder.Records()
+		report.RpcEndpoints = recorder.Served()
+		report.RpcDropped = recorder.Dropped()
+	}
+	utils.JsonWriter(report, "output/runReport.json")
+}
+
+// Run executes the configured mode. The context cancels the pending RPC calls and the waiting for
+// transactions, e.g. on SIGINT/SIGTERM, the transactions not mined yet are then listed before exiting.
+//
+// The run report, and the traces when Client.Trace is set, are written when the run ends, including when it
+// stops on a fatal error or an interruption: they are registered with utils.AtExit rather than deferred.
+func Run(ctx context.Context, options Options) {
+	defer utils.RunExitHooks()
+	setup(ctx, options)
+
+	dryRun = options.DryRun
+	if dryRun {
+		output := DryRun(ctx)
+		utils.JsonWriter(output, "output/dryRun.json")
+		log.Printf("Dry run: %d transaction(s) signed, nothing was broadcast. Estimated cost: %s ETH", len(output.Transactions), utils.WeiToEther(output.TotalEstimatedCost).String())
+		return
+	}
+
+	if tomlConfig.Client.Trace {
+		utils.AtExit(writeTraces)
+	}
+
+	// Record the transactions of the run, so it can be resumed
+	if utils.RecordsJournal(mode) {
+		openJournal(ctx, options.Resume)
+		defer completeJournal()
+	} else if options.Resume {
+		log.Printf("Mode '%s' records no journal, there is nothing to resume", mode)
+	}
+
+	// Validate if the account is sufficiently funded for the whole run
+	if utils.SendsTransactions(mode) {
+		estimatedCost := EstimateRunCost(ctx, tomlConfig, deployerAddress, ethClient, runJournal)
+		account.ValidateBalanceCoversCost(ctx, deployerAddress, ethClient, estimatedCost, tomlConfig.Client.SafetyMargin)
+	}
 
 	switch mode {
+	case utils.ARTIFACT_MODE:
+		// An arbitrary contract can't be read with the GetterSetter getters, so it has its own output
+		output := DeployArtifactContract(ctx, tomlConfig.Contract.Artifact, deployerAddress, privateKey, ethClient, timeout)
+		recordDeployment(ctx, "deploy-artifact", artifactContractName(tomlConfig.Contract.Artifact), common.HexToAddress(output.ContractAddress))
+		utils.JsonWriter(output, "output/artifactDeploymentInformation.json")
+		return
+
+	case utils.METHOD_MODE:
+		artifact := LoadArtifact(tomlConfig.Contract.Artifact)
+		output := CallContractMethod(ctx, artifact, tomlConfig.Contract.Method, contractAddress, deployerAddress, privateKey, ethClient, timeout)
+		utils.JsonWriter(output, "output/methodCallInformation.json")
+		return
+
+	case utils.VIEW_MODE:
+		artifact := LoadArtifact(tomlConfig.Contract.Artifact)
+		output := ReadContractMethod(ctx, artifact, tomlConfig.Contract.Method, contractAddress, deployerAddress, ethClient)
+		utils.JsonWriter(output, "output/methodReadInformation.json")
+		return
+
+	case utils.VERIFY_MODE:
+		output := VerifyContractBytecode(ctx, tomlConfig.Contract.Artifact, contractAddress, ethClient)
+		utils.JsonWriter(output, "output/bytecodeVerification.json")
+		if !output.Match {
+			utils.Fatalf("Runtime code at %s does not match the local artifact %s", contractAddress, output.LocalArtifact)
+		}
+		log.Printf("Runtime code at %s matches the local artifact %s (metadata match: %t)", contractAddress, output.LocalArtifact, output.ExactMatch)
+		return
+
+	case utils.STORAGE_MODE:
+		output := InspectGetterSetterStorage(ctx, contractAddress, ethClient)
+		utils.JsonWriter(output, "output/storageInspection.json")
+		if !output.Match {
+			utils.Fatalf("The storage of %s does not match its getters, the code at the address doesn't store its values like GetterSetter", contractAddress)
+		}
+		log.Printf("The storage of %s matches its getters", contractAddress)
+		return
+
+	case utils.PREPARE_MODE:
+		output := PrepareSetterTransactions(ctx, values, contractAddress, deployerAddress, ethClient, gasLimit)
+		utils.JsonWriter(output, tomlConfig.Offline.Unsigned)
+		log.Printf("%d transaction(s) prepared for %s in %s, sign them offline with the sign mode", len(output.Transactions), output.From, tomlConfig.Offline.Unsigned)
+		return
+
+	case utils.SIGN_MODE:
+		var unsigned types.UnsignedTransactions
+		readOfflineFile(tomlConfig.Offline.Unsigned, &unsigned)
+		output := SignTransactions(unsigned, privateKey)
+		utils.JsonWriter(output, tomlConfig.Offline.Signed)
+		log.Printf("%d transaction(s) signed in %s, send them with the broadcast mode", len(output.Transactions), tomlConfig.Offline.Signed)
+		return
+
+	case utils.BROADCAST_MODE:
+		var signed types.SignedTransactions
+		readOfflineFile(tomlConfig.Offline.Signed, &signed)
+		output := BroadcastTransactions(ctx, signed, ethClient, timeout)
+		utils.JsonWriter(output, "output/broadcastInformation.json")
+		return
+
+	case utils.SAFE_MODE:
+		builderFile, output := ProposeSafeTransactions(ctx, values, contractAddress, tomlConfig.Safe, deployerAddress, ethClient)
+		utils.JsonWriter(builderFile, "output/safeTransactionBuilder.json")
+		utils.JsonWriter(output, "output/safeProposal.json")
+		log.Println("Import output/safeTransactionBuilder.json in the Transaction Builder app of the Safe, nothing was sent")
+		return
+
+	case utils.SIGN_MESSAGE_MODE:
+		output := SignMessage(tomlConfig.Message, privateKey)
+		utils.JsonWriter(output, "output/messageSignature.json")
+		return
+
+	case utils.VERIFY_MESSAGE_MODE:
+		output := VerifyMessage(tomlConfig.Message)
+		utils.JsonWriter(output, "output/messageVerification.json")
+		if !output.Match {
+			utils.Fatalf("The message is signed by %s, not by %s", output.Signer, output.ExpectedSigner)
+		}
+		return
+
+	case utils.UPGRADE_MODE:
+		output := UpgradeGetterSetterProxy(ctx, values, tomlConfig.Contract.Implementation, contractAddress, deployerAddress, privateKey, ethClient, timeout)
+		utils.JsonWriter(output, "output/upgradeInformation.json")
+		if !output.StoragePreserved {
+			utils.Fatalf("The values of the proxy %s changed with the upgrade to %s", contractAddress, output.NewImplementation)
+		}
+		log.Printf("Proxy %s upgraded from %s to %s, the values are preserved", contractAddress, output.PreviousImplementation, output.NewImplementation)
+		return
+
+	case utils.FUND_MODE:
+		target, _ := utils.EtherToWei(tomlConfig.Account.Funding) // checked by the config validation
+		output := FundDerivedAccounts(ctx, deployerAddress, privateKey, derivedAccounts, target, ethClient, timeout)
+		utils.JsonWriter(output, "output/fundingInformation.json")
+		return
+
+	case utils.SWEEP_MODE:
+		output := SweepDerivedAccounts(ctx, deployerAddress, derivedAccounts, ethClient, timeout)
+		utils.JsonWriter(output, "output/sweepInformation.json")
+		return
+
+	case utils.LOAD_MODE:
+		privateKeys := append([]string{privateKey}, tomlConfig.Load.Keys...)
+		if tomlConfig.Account.DerivedSenders {
+			for _, derived := range derivedAccounts {
+				privateKeys = append(privateKeys, derived.privateKey)
+			}
+		}
+		output := RunLoadTest(ctx, tomlConfig.Load, contractAddress, privateKeys, ethClient, timeout)
+		utils.JsonWriter(output, "output/loadTestReport.json")
+		transactions.ExitIfInterrupted(ctx)
+		return
+
 	case utils.DEPLOY_MODE:
-		auth := GetSigner(ethClient, deployerAddress, privateKey)
-		deployedContractAddress := client.DeployContract(auth, ethClient, timeout)
+		deployedContractAddress := deployGetterSetter(ctx)
+		recordDeployment(ctx, "deploy", getterSetterContractName, deployedContractAddress)
 		contractAddress := deployedContractAddress.Hex()
-		getterSetterContract = client.AttachToContract(contractAddress, ethClient)
+		logExplorerLink("address", contractAddress)
+		getterSetterContract = client.AttachToContract(ctx, contractAddress, ethClient)
 
 	case utils.CALL_MODE:
 		// Attach to the contract
-		getterSetterContract = client.AttachToContract(contractAddress, ethClient)
-		ExecuteSetterGetterContractFunction(values, deployerAddress, privateKey, getterSetterContract, ethClient, timeout)
+		getterSetterContract = client.AttachToContract(ctx, contractAddress, ethClient)
+		executeSetters(ctx, values, setterSenders(), getterSetterContract, ethClient, timeout)
 
 	case utils.DEMO_MODE: // executes full e2e scenario "deploy-contract" + "call-contract" + "read-only-contract"
-		auth := GetSigner(ethClient, deployerAddress, privateKey)
-		deployedContractAddress := client.DeployContract(auth, ethClient, timeout)
+		deployedContractAddress := deployGetterSetter(ctx)
+		recordDeployment(ctx, "deploy", getterSetterContractName, deployedContractAddress)
 		contractAddress := deployedContractAddress.Hex()
-		getterSetterContract = client.AttachToContract(contractAddress, ethClient)
-		ExecuteSetterGetterContractFunction(values, deployerAddress, privateKey, getterSetterContract, ethClient, timeout)
+		logExplorerLink("address", contractAddress)
+		getterSetterContract = client.AttachToContract(ctx, contractAddress, ethClient)
+		executeSetters(ctx, values, setterSenders(), getterSetterContract, ethClient, timeout)
 
 	case utils.READ_ONLY_MODE:
-		log.Printf("Reading the contract: %s, owned by: %s", contractAddress, deployerAddress)
-		getterSetterContract = client.AttachToContract(contractAddress, ethClient)
+		log.Printf("Reading the contract: %s, from: %s", contractAddress, deployerAddress)
+		getterSetterContract = client.AttachToContract(ctx, contractAddress, ethClient)
 		// N/B: There is no need to add reading logic here as it takes place after the `switch-case` execution.
 		// If the common logic after `switch-case` changes, update this case body too.
 	}
 	// Write the contract information to a JSON file
-	output := ReadGetterSetterContract(getterSetterContract, contractAddress, deployerAddress)
+	output := ReadGetterSetterContract(ctx, getterSetterContract, contractAddress, deployerAddress)
 	pathToJson := "output/contractOutputInformation.json" // paste your desired path
 	utils.JsonWriter(output, pathToJson)
 }
@@ -72,62 +353,93 @@ func Run() {
 // If a value is not provided, the corresponding setter function will be gracefully skipped.
 //
 // Parameters
+// - ctx: the context of the run (context.Context)
 // - values: configuration values for contract interaction (config.Values)
 // - deployerAddress: the address of the deployer (common.Address)
 // - privateKey: the private key for authentication (string)
 // - getterSetterContract: the contract instance for setting and getting values (*getter_setter.GetterSetter)
-// - ethClient: the Ethereum client for interaction (*ethclient.Client)
+// - ethClient: the Ethereum client for interaction (backend.Client)
 // - timeout: the timeout duration for the contract interaction (int)
-func ExecuteSetterGetterContractFunction(values config.Values, deployerAddress common.Address, privateKey string, getterSetterContract *getter_setter.GetterSetter, ethClient *ethclient.Client, timeout int) {
+func ExecuteSetterGetterContractFunction(ctx context.Context, values config.Values, deployerAddress common.Address, privateKey string, getterSetterContract *getter_setter.GetterSetter, ethClient backend.Client, timeout int) {
+	executeSetters(ctx, values, []sender{{address: deployerAddress, privateKey: privateKey}}, getterSetterContract, ethClient, timeout)
+}
+
+// executeSetters works as ExecuteSetterGetterContractFunction, the setters being sent from the senders in turn
+func executeSetters(ctx context.Context, values config.Values, senders []sender, getterSetterContract *getter_setter.GetterSetter, ethClient backend.Client, timeout int) {
 	getterSetterDto := SetGetterSetterDTO(values)
+	sent := 0
+	nextSigner := func() *bind.TransactOpts {
+		from := senders[sent%len(senders)]
+		sent++
+		if len(senders) > 1 {
+			log.Printf("Sending from %s (%s)", from.address.Hex(), from.path)
+		}
+		return GetSigner(ctx, ethClient, from.address, from.privateKey)
+	}
 
 	uint256Value := values.Uint256
 	if uint256Value != nil && uint256Value.Cmp(big.NewInt(0)) >= 0 {
-		auth := GetSigner(ethClient, deployerAddress, privateKey)
-		SetUintInGetterSetterContract(getterSetterContract, auth, getterSetterDto, ethClient, timeout)
+		auth := nextSigner()
+		journaledStep(ctx, "setUint256", auth, func(auth *bind.TransactOpts) {
+			SetUintInGetterSetterContract(ctx, getterSetterContract, auth, getterSetterDto, ethClient, timeout)
+		})
 	}
 	bytes32Value := values.Bytes32
 	if bytes32Value != "" {
-		auth := GetSigner(ethClient, deployerAddress, privateKey)
-		SetBytes32InGetterSetterContract(getterSetterContract, auth, getterSetterDto, ethClient, timeout)
+		auth := nextSigner()
+		journaledStep(ctx, "setBytes32", auth, func(auth *bind.TransactOpts) {
+			SetBytes32InGetterSetterContract(ctx, getterSetterContract, auth, getterSetterDto, ethClient, timeout)
+		})
 	}
 	bytesValue := values.Bytes
 	if bytesValue != "" {
-		auth := GetSigner(ethClient, deployerAddress, privateKey)
-		SetBytesInGetterSetterContract(getterSetterContract, auth, getterSetterDto, ethClient, timeout)
+		auth := nextSigner()
+		journaledStep(ctx, "setBytes", auth, func(auth *bind.TransactOpts) {
+			SetBytesInGetterSetterContract(ctx, getterSetterContract, auth, getterSetterDto, ethClient, timeout)
+		})
 	}
 }
 
 // ReadGetterSetterContract retrieves values from the contract, using its getters.
 //
 // Parameters:
+// - ctx: the context of the run (context.Context)
 // - getterSetterContract: the contract instance for getting values (*getter_setter.GetterSetter)
 // - contractAddress: the address of the contract (string)
-// - deployerAddress: the address of the deployer (common.Address)
+// - deployerAddress: the address of the deployer, used as the eth_call sender (common.Address)
 // Return type:
 // - types.ContractGetterSetterInformation
-func ReadGetterSetterContract(getterSetterContract *getter_setter.GetterSetter, contractAddress string, deployerAddress common.Address) types.ContractGetterSetterInformation {
-	uintResponse, err := getterSetterContract.GetUint256(&bind.CallOpts{})
+func ReadGetterSetterContract(ctx context.Context, getterSetterContract *getter_setter.GetterSetter, contractAddress string, deployerAddress common.Address) types.ContractGetterSetterInformation {
+	callOpts := &bind.CallOpts{From: deployerAddress, Context: ctx}
+	uintResponse, err := getterSetterContract.GetUint256(callOpts)
 	if err != nil {
-		log.Fatal("Uint256 value could not be fetched from the contract", err)
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatal("Uint256 value could not be fetched from the contract", err)
 	}
 	log.Println("Uint value is: ", uintResponse)
 
-	bytes32Response, err := getterSetterContract.GetBytes32(&bind.CallOpts{})
+	bytes32Response, err := getterSetterContract.GetBytes32(callOpts)
 	if err != nil {
-		log.Fatal("Bytes32 value could not be fetched from the contract", err)
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatal("Bytes32 value could not be fetched from the contract", err)
 	}
 	log.Println("Bytes32 value is:", bytes32Response)
 
-	bytesResponse, err := getterSetterContract.GetBytes(&bind.CallOpts{})
+	bytesResponse, err := getterSetterContract.GetBytes(callOpts)
 	if err != nil {
-		log.Fatal("Bytes value could not be fetched from the contract", err)
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatal("Bytes value could not be fetched from the contract", err)
 	}
 	log.Println("Bytes value is: "+string(bytesResponse)+" -> Bytes raw value:", bytesResponse)
 
+	// No deployer to report when reading without an account
+	deployer := ""
+	if deployerAddress != (common.Address{}) {
+		deployer = deployerAddress.String()
+	}
 	output := types.ContractGetterSetterInformation{
 		ContractAddress: contractAddress,
-		DeployerAddress: deployerAddress.String(),
+		DeployerAddress: deployer,
 		UintValue:       uintResponse,
 		Byte32Value:     bytes32Response,
 		BytesValue:      bytesResponse,
@@ -135,32 +447,477 @@ func ReadGetterSetterContract(getterSetterContract *getter_setter.GetterSetter,
 	return output
 }
 
-func SetUintInGetterSetterContract(getterSetterContract *getter_setter.GetterSetter, auth *bind.TransactOpts, getterSetterDto dto.EthereumDTO, ethClient *ethclient.Client, timeout int) {
+// DeployArtifactContract loads the configured artifact, parses its constructor arguments and deploys it.
+//
+// Parameters:
+// - ctx: the context of the run (context.Context)
+// - artifactConfig: the artifact paths and the constructor arguments (config.Artifact)
+// - deployerAddress: the address of the deployer (common.Address)
+// - privateKey: the private key for authentication (string)
+// - ethClient: the Ethereum client for interaction (backend.Client)
+// - timeout: the timeout duration for the deployment (int)
+// Return type:
+// - types.ArtifactDeploymentInformation
+func DeployArtifactContract(ctx context.Context, artifactConfig config.Artifact, deployerAddress common.Address, privateKey string, ethClient backend.Client, timeout int) types.ArtifactDeploymentInformation {
+	artifact := LoadArtifact(artifactConfig)
+	constructorArgs, err := artifacts.ParseArguments(artifact.ABI.Constructor.Inputs, artifactConfig.ConstructorArgs)
+	if err != nil {
+		utils.Fatalf("Failed to parse the constructor arguments: %v", err)
+	}
+
+	auth := GetSigner(ctx, ethClient, deployerAddress, privateKey)
+	var deployedContractAddress common.Address
+	var transactionHash common.Hash
+	receipt, resumed := journaledStep(ctx, "deploy-artifact", auth, func(auth *bind.TransactOpts) {
+		var transaction *gethtypes.Transaction
+		deployedContractAddress, transaction = client.DeployArtifact(ctx, auth, ethClient, artifact, constructorArgs, timeout)
+		transactionHash = transaction.Hash()
+	})
+	if resumed {
+		deployedContractAddress, transactionHash = receipt.ContractAddress, receipt.TxHash
+	}
+	logExplorerLink("address", deployedContractAddress.Hex())
+
+	return types.ArtifactDeploymentInformation{
+		ContractAddress: deployedContractAddress.Hex(),
+		DeployerAddress: deployerAddress.String(),
+		TransactionHash: transactionHash.Hex(),
+		ConstructorArgs: artifactConfig.ConstructorArgs,
+	}
+}
+
+// CallContractMethod sends a transaction calling an arbitrary contract method, waits for it to be mined
+// and decodes the events emitted by the contract.
+//
+// Parameters:
+// - ctx: the context of the run (context.Context)
+// - artifact: the contract ABI (artifacts.Artifact)
+// - method: the method name and its string arguments (config.Method)
+// - contractAddress: the address of the contract (string)
+// - deployerAddress: the address of the sender (common.Address)
+// - privateKey: the private key for authentication (string)
+// - ethClient: the Ethereum client for interaction (backend.Client)
+// - timeout: the timeout duration for the transaction to be mined (int)
+// Return type:
+// - types.MethodCallInformation
+func CallContractMethod(ctx context.Context, artifact artifacts.Artifact, method config.Method, contractAddress string, deployerAddress common.Address, privateKey string, ethClient backend.Client, timeout int) types.MethodCallInformation {
+	args := parseMethodArguments(artifact, method)
+	boundContract := client.BindContract(ctx, contractAddress, artifact.ABI, ethClient)
+
+	auth := GetSigner(ctx, ethClient, deployerAddress, privateKey)
+	var receipt *gethtypes.Receipt
+	journaledReceipt, resumed := journaledStep(ctx, "call "+method.Name, auth, func(auth *bind.TransactOpts) {
+		transaction, err := boundContract.Transact(auth, method.Name, args...)
+		if err != nil {
+			transactions.ExitIfInterrupted(ctx)
+			utils.Fatalf("Failed to call %s: %v", method.Name, reverts.Explain(method.Name, err))
+		}
+		log.Printf("Waiting for transaction for %s: %s\n", method.Name, transaction.Hash().Hex())
+		logExplorerLink("tx", transaction.Hash().Hex())
+		receipt = transactions.WaitMined(ctx, ethClient, transaction, timeout)
+		if err := reverts.CheckReceipt(ctx, ethClient, method.Name, transaction, receipt); err != nil {
+			transactions.ExitIfInterrupted(ctx)
+			utils.Fatalf("Failed to call %s: %v", method.Name, err)
+		}
+	})
+	if resumed {
+		receipt = journaledReceipt
+	}
+
+	events := artifacts.DecodeLogs(artifact.ABI, common.HexToAddress(contractAddress), receipt.Logs)
+	log.Printf("%s emitted %d event(s)", method.Name, len(events))
+
+	return types.MethodCallInformation{
+		ContractAddress: contractAddress,
+		SenderAddress:   deployerAddress.String(),
+		Method:          method.Name,
+		Args:            method.Args,
+		TransactionHash: receipt.TxHash.Hex(),
+		BlockNumber:     receipt.BlockNumber,
+		GasUsed:         receipt.GasUsed,
+		Events:          events,
+	}
+}
+
+// ReadContractMethod calls an arbitrary contract method with eth_call, without sending a transaction,
+// and decodes its return values.
+//
+// Parameters:
+// - ctx: the context of the run (context.Context)
+// - artifact: the contract ABI (artifacts.Artifact)
+// - method: the method name and its string arguments (config.Method)
+// - contractAddress: the address of the contract (string)
+// - fromAddress: the address the call is made from (common.Address)
+// - ethClient: the Ethereum client for interaction (backend.Client)
+// Return type:
+// - types.MethodCallInformation
+func ReadContractMethod(ctx context.Context, artifact artifacts.Artifact, method config.Method, contractAddress string, fromAddress common.Address, ethClient backend.Client) types.MethodCallInformation {
+	args := parseMethodArguments(artifact, method)
+	boundContract := client.BindContract(ctx, contractAddress, artifact.ABI, ethClient)
+
+	var results []interface{}
+	if err := boundContract.Call(&bind.CallOpts{From: fromAddress, Context: ctx}, &results, method.Name, args...); err != nil {
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatalf("Failed to read %s: %v", method.Name, reverts.Explain(method.Name, err))
+	}
+	returnValues := artifacts.NamedValues(artifact.ABI.Methods[method.Name].Outputs, results)
+	log.Printf("%s returned: %v", method.Name, returnValues)
+
+	return types.MethodCallInformation{
+		ContractAddress: contractAddress,
+		SenderAddress:   fromAddress.String(),
+		Method:          method.Name,
+		Args:            method.Args,
+		ReturnValues:    returnValues,
+	}
+}
+
+// parseMethodArguments looks the method up in the ABI and parses its string arguments
+func parseMethodArguments(artifact artifacts.Artifact, method config.Method) []interface{} {
+	abiMethod, ok := artifact.ABI.Methods[method.Name]
+	if !ok {
+		utils.Fatalf("Method %s is not found in the contract ABI", method.Name)
+	}
+	args, err := artifacts.ParseArguments(abiMethod.Inputs, method.Args)
+	if err != nil {
+		utils.Fatalf("Failed to parse the arguments of %s: %v", abiMethod.Sig, err)
+	}
+	return args
+}
+
+// VerifyContractBytecode compares the runtime code deployed at the contract address (eth_getCode)
+// with the runtime code of the locally compiled artifact, excluding the solc metadata.
+// The local runtime code is obtained by running the artifact's creation code on a simulated chain.
+// The GetterSetter compiled into the application (see `make contracts`) is used when no artifact is configured,
+// so the source tree is not needed.
+//
+// Parameters:
+// - ctx: the context of the run (context.Context)
+// - artifactConfig: the local artifact, the GetterSetter binding by default (config.Artifact)
+// - contractAddress: the address of the deployed contract (string)
+// - ethClient: the Ethereum client for interaction (backend.Client)
+// Return type:
+// - types.BytecodeVerificationInformation
+func VerifyContractBytecode(ctx context.Context, artifactConfig config.Artifact, contractAddress string, ethClient backend.Client) types.BytecodeVerificationInformation {
+	var artifact artifacts.Artifact
+	localArtifact := artifactConfig.Path
+	if artifactConfig.Path == "" && artifactConfig.Bin == "" {
+		artifact = getterSetterArtifact()
+		localArtifact = getterSetterArtifactName
+	} else {
+		if localArtifact == "" {
+			localArtifact = artifactConfig.Bin
+		}
+		artifact = LoadArtifact(artifactConfig)
+	}
+	if len(artifact.Bytecode) == 0 {
+		utils.Fatalf("The local artifact %s has no bytecode. Compile the contract first (see `make contracts`).", localArtifact)
+	}
+
+	onchainCode, err := ethClient.CodeAt(ctx, common.HexToAddress(contractAddress), nil)
+	if err != nil {
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatalf("Failed to get the code at %s: %v", contractAddress, err)
+	}
+	if len(onchainCode) == 0 {
+		utils.Fatalf("There is no contract code at %s", contractAddress)
+	}
+	localCode := client.SimulateRuntimeCode(artifact.Bytecode)
+
+	onchainStripped, localStripped := artifacts.StripMetadata(onchainCode), artifacts.StripMetadata(localCode)
+	return types.BytecodeVerificationInformation{
+		ContractAddress:  contractAddress,
+		LocalArtifact:    localArtifact,
+		Match:            bytes.Equal(onchainStripped, localStripped),
+		ExactMatch:       bytes.Equal(onchainCode, localCode),
+		OnchainCodeHash:  crypto.Keccak256Hash(onchainStripped).Hex(),
+		LocalCodeHash:    crypto.Keccak256Hash(localStripped).Hex(),
+		OnchainMetadata:  hexutil.Encode(onchainCode[len(onchainStripped):]),
+		LocalMetadata:    hexutil.Encode(localCode[len(localStripped):]),
+		OnchainCodeBytes: len(onchainCode),
+		LocalCodeBytes:   len(localCode),
+	}
+}
+
+// getterSetterArtifactName names the GetterSetter artifact compiled into the application in the outputs
+const getterSetterArtifactName = "GetterSetter (getter_setter.GetterSetterMetaData)"
+
+// getterSetterArtifact returns the ABI and the creation code of the GetterSetter binding, generated from
+// GetterSetter.abi and GetterSetter.bin by `make contracts`
+func getterSetterArtifact() artifacts.Artifact {
+	getterSetterABI, err := getter_setter.GetterSetterMetaData.GetAbi()
+	if err != nil {
+		utils.Fatalf("Failed to parse the GetterSetter ABI: %v", err)
+	}
+	return artifacts.Artifact{ABI: *getterSetterABI, Bytecode: common.FromHex(getter_setter.GetterSetterMetaData.Bin)}
+}
+
+// LoadArtifact loads either the JSON artifact (Path) or the raw .abi/.bin pair.
+func LoadArtifact(artifactConfig config.Artifact) artifacts.Artifact {
+	var artifact artifacts.Artifact
+	var err error
+	if artifactConfig.Path != "" {
+		artifact, err = artifacts.LoadArtifact(artifactConfig.Path)
+	} else {
+		artifact, err = artifacts.LoadAbiAndBin(artifactConfig.Abi, artifactConfig.Bin)
+	}
+	if err != nil {
+		utils.Fatalf("Failed to load the artifact: %v", err)
+	}
+	reverts.RegisterABI(artifact.ABI)
+	return artifact
+}
+
+// deployGetterSetter deploys the GetterSetter contract with CREATE, through the CREATE2 factory when Contract.Create2
+// is set, or behind a TransparentUpgradeableProxy when Contract.Proxy is set
+func deployGetterSetter(ctx context.Context) common.Address {
+	if tomlConfig.Contract.Proxy {
+		return DeployGetterSetterProxy(ctx, deployerAddress, privateKey, ethClient, timeout)
+	}
+	auth := GetSigner(ctx, ethClient, deployerAddress, privateKey)
+	if !tomlConfig.Contract.Create2 {
+		return DeployGetterSetterContract(ctx, auth, ethClient, timeout)
+	}
+	salt, err := utils.ParseSalt(tomlConfig.Contract.Salt)
+	if err != nil {
+		utils.Fatalf("Contract.Salt is invalid: %v", err)
+	}
+	return DeployGetterSetterCreate2(ctx, auth, ethClient, salt, timeout)
+}
+
+// DeployGetterSetterCreate2 deploys the GetterSetter contract through the deterministic deployment proxy,
+// deployed first on the development chains if absent, as the "deploy" step of the journal.
+// The address only depends on the salt, so the same address is used on every network, and the
+// deployment is skipped when the contract is already there.
+//
+// Parameters:
+// - ctx: the context of the run (context.Context)
+// - auth: the signer options (*bind.TransactOpts)
+// - ethClient: the Ethereum client for interaction (backend.Client)
+// - salt: the CREATE2 salt (common.Hash)
+// - timeout: the timeout duration for the deployment (int)
+// Returns:
+// - common.Address: address of the contract
+func DeployGetterSetterCreate2(ctx context.Context, auth *bind.TransactOpts, ethClient backend.Client, salt common.Hash, timeout int) common.Address {
+	initCode := common.FromHex(getter_setter.GetterSetterMetaData.Bin)
+	address := client.Create2Address(salt, initCode)
+	log.Printf("CREATE2 address of GetterSetter with salt %s: %s", salt.Hex(), address.Hex())
+	client.EnsureDeterministicDeployer(ctx, auth, ethClient, timeout)
+	_, resumed := journaledStep(ctx, "deploy", auth, func(auth *bind.TransactOpts) {
+		client.DeployCreate2(ctx, auth, ethClient, salt, initCode, timeout)
+	})
+	if resumed {
+		log.Printf("Contract address: %s, deployed by the previous run", address.Hex())
+	}
+	return address
+}
+
+// DeployGetterSetterProxy deploys a GetterSetter implementation ("deploy-implementation" step of the journal), a
+// ProxyAdmin owned by the deployer ("deploy-proxy-admin" step) and a TransparentUpgradeableProxy delegating to the
+// implementation, administered by the ProxyAdmin ("deploy" step). The GetterSetter binding works at the proxy
+// address, the values are stored in the proxy's storage.
+//
+// Parameters:
+// - ctx: the context of the run (context.Context)
+// - deployerAddress: the address of the deployer, the ProxyAdmin owner (common.Address)
+// - privateKey: the private key for authentication (string)
+// - ethClient: the Ethereum client for interaction (backend.Client)
+// - timeout: the timeout duration for the deployments (int)
+// Returns:
+// - common.Address: address of the proxy
+func DeployGetterSetterProxy(ctx context.Context, deployerAddress common.Address, privateKey string, ethClient backend.Client, timeout int) common.Address {
+	implementation := deployImplementation(ctx, deployerAddress, privateKey, ethClient, timeout)
+
+	var proxyAdmin common.Address
+	auth := GetSigner(ctx, ethClient, deployerAddress, privateKey)
+	receipt, resumed := journaledStep(ctx, "deploy-proxy-admin", auth, func(auth *bind.TransactOpts) {
+		proxyAdmin = client.DeployProxyAdmin(ctx, auth, ethClient, timeout)
+	})
+	if resumed {
+		proxyAdmin = receipt.ContractAddress
+		log.Printf("ProxyAdmin address: %s, deployed by the previous run", proxyAdmin)
+	}
+
+	var proxyAddress common.Address
+	auth = GetSigner(ctx, ethClient, deployerAddress, privateKey)
+	receipt, resumed = journaledStep(ctx, "deploy", auth, func(auth *bind.TransactOpts) {
+		proxyAddress = client.DeployProxy(ctx, auth, ethClient, implementation, proxyAdmin, timeout)
+	})
+	if resumed {
+		proxyAddress = receipt.ContractAddress
+		log.Printf("Proxy address: %s, deployed by the previous run", proxyAddress)
+	}
+	return proxyAddress
+}
+
+// deployImplementation deploys a GetterSetter implementation as the "deploy-implementation" step of the journal
+func deployImplementation(ctx context.Context, deployerAddress common.Address, privateKey string, ethClient backend.Client, timeout int) common.Address {
+	var implementation common.Address
+	auth := GetSigner(ctx, ethClient, deployerAddress, privateKey)
+	receipt, resumed := journaledStep(ctx, "deploy-implementation", auth, func(auth *bind.TransactOpts) {
+		implementation = client.DeployContract(ctx, auth, ethClient, timeout)
+	})
+	if resumed {
+		implementation = receipt.ContractAddress
+		log.Printf("Implementation address: %s, deployed by the previous run", implementation)
+	}
+	return implementation
+}
+
+// UpgradeGetterSetterProxy points the TransparentUpgradeableProxy at a new GetterSetter implementation, through its
+// ProxyAdmin, and checks that the values read through the proxy survive the upgrade. The configured values, if any,
+// are set first, so there is something to preserve. A new implementation is deployed unless one is given.
+//
+// Parameters:
+// - ctx: the context of the run (context.Context)
+// - values: the values to set before the upgrade, optional (config.Values)
+// - implementationAddress: the new implementation, a new GetterSetter is deployed when empty (string)
+// - proxyAddress: the address of the proxy (string)
+// - deployerAddress: the address of the ProxyAdmin owner (common.Address)
+// - privateKey: the private key for authentication (string)
+// - ethClient: the Ethereum client for interaction (backend.Client)
+// - timeout: the timeout duration for the transactions (int)
+// Return type:
+// - types.UpgradeInformation
+func UpgradeGetterSetterProxy(ctx context.Context, values config.Values, implementationAddress string, proxyAddress string, deployerAddress common.Address, privateKey string, ethClient backend.Client, timeout int) types.UpgradeInformation {
+	proxy := common.HexToAddress(proxyAddress)
+	previousImplementation, admin, err := client.ReadProxySlots(ctx, ethClient, proxy)
+	if err != nil {
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatalf("Failed to upgrade: %v", err)
+	}
+	owner, err := client.ReadProxyAdminOwner(ctx, ethClient, admin)
+	if err != nil {
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatalf("Failed to upgrade: the admin of the proxy %s is not a ProxyAdmin: %v", proxy.Hex(), err)
+	}
+	if owner != deployerAddress {
+		utils.Fatalf("Failed to upgrade: the ProxyAdmin %s of the proxy %s is owned by %s, not %s", admin.Hex(), proxy.Hex(), owner.Hex(), deployerAddress.Hex())
+	}
+	log.Printf("Proxy %s, implementation: %s, ProxyAdmin: %s, owner: %s", proxy.Hex(), previousImplementation.Hex(), admin.Hex(), owner.Hex())
+
+	getterSetterContract := client.AttachToContract(ctx, proxyAddress, ethClient)
+	ExecuteSetterGetterContractFunction(ctx, values, deployerAddress, privateKey, getterSetterContract, ethClient, timeout)
+	valuesBefore := ReadGetterSetterContract(ctx, getterSetterContract, proxyAddress, deployerAddress)
+
+	var newImplementation common.Address
+	if implementationAddress != "" {
+		newImplementation = common.HexToAddress(implementationAddress)
+		if err := client.CheckContractCode(ctx, implementationAddress, ethClient); err != nil {
+			transactions.ExitIfInterrupted(ctx)
+			utils.Fatalf("Failed to upgrade: %v", err)
+		}
+	} else {
+		newImplementation = deployImplementation(ctx, deployerAddress, privateKey, ethClient, timeout)
+	}
+
+	auth := GetSigner(ctx, ethClient, deployerAddress, privateKey)
+	var receipt *gethtypes.Receipt
+	journaledReceipt, resumed := journaledStep(ctx, "upgrade", auth, func(auth *bind.TransactOpts) {
+		receipt = client.UpgradeProxy(ctx, auth, ethClient, admin, proxy, newImplementation, nil, timeout)
+	})
+	if resumed {
+		receipt = journaledReceipt
+		if err := client.CheckUpgradedEvent(receipt, proxy, newImplementation); err != nil {
+			utils.Fatalf("Failed to upgrade: %v", err)
+		}
+	}
+	logExplorerLink("tx", receipt.TxHash.Hex())
+
+	currentImplementation, _, err := client.ReadProxySlots(ctx, ethClient, proxy)
+	if err != nil {
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatalf("Failed to read the proxy after the upgrade: %v", err)
+	}
+	if currentImplementation != newImplementation {
+		utils.Fatalf("The proxy %s points at %s after the upgrade, not %s", proxy.Hex(), currentImplementation.Hex(), newImplementation.Hex())
+	}
+	valuesAfter := ReadGetterSetterContract(ctx, getterSetterContract, proxyAddress, deployerAddress)
+
+	return types.UpgradeInformation{
+		ProxyAddress:           proxy.Hex(),
+		AdminAddress:           admin.Hex(),
+		AdminOwner:             owner.Hex(),
+		PreviousImplementation: previousImplementation.Hex(),
+		NewImplementation:      newImplementation.Hex(),
+		TransactionHash:        receipt.TxHash.Hex(),
+		ValuesBefore:           valuesBefore,
+		ValuesAfter:            valuesAfter,
+		StoragePreserved: valuesBefore.UintValue.Cmp(valuesAfter.UintValue) == 0 &&
+			valuesBefore.Byte32Value == valuesAfter.Byte32Value &&
+			bytes.Equal(valuesBefore.BytesValue, valuesAfter.BytesValue),
+	}
+}
+
+// DeployGetterSetterContract deploys the GetterSetter contract as the "deploy" step of the journal,
+// so a resumed run reuses the contract deployed by the previous one.
+//
+// Parameters:
+// - ctx: the context of the run (context.Context)
+// - auth: the signer options (*bind.TransactOpts)
+// - ethClient: the Ethereum client for interaction (backend.Client)
+// - timeout: the timeout duration for the deployment (int)
+// Returns:
+// - common.Address: address of the deployed contract
+func DeployGetterSetterContract(ctx context.Context, auth *bind.TransactOpts, ethClient backend.Client, timeout int) common.Address {
+	var deployedContractAddress common.Address
+	receipt, resumed := journaledStep(ctx, "deploy", auth, func(auth *bind.TransactOpts) {
+		deployedContractAddress = client.DeployContract(ctx, auth, ethClient, timeout)
+	})
+	if resumed {
+		deployedContractAddress = receipt.ContractAddress
+		log.Printf("Contract address: %s, deployed by the previous run", deployedContractAddress)
+	}
+	return deployedContractAddress
+}
+
+func SetUintInGetterSetterContract(ctx context.Context, getterSetterContract *getter_setter.GetterSetter, auth *bind.TransactOpts, getterSetterDto dto.EthereumDTO, ethClient backend.Client, timeout int) {
 	transaction, err := getterSetterContract.SetUint256(auth, getterSetterDto.Uint256)
 	if err != nil {
-		log.Fatalf("Failed to set uint256: %v", err)
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatalf("Failed to set uint256: %v", reverts.Explain("setUint256", err))
 	}
 
 	log.Printf("Waiting for transaction for setUint256: %s\n", transaction.Hash().Hex())
-	transactions.WaitMined(ethClient, transaction, timeout)
+	logExplorerLink("tx", transaction.Hash().Hex())
+	receipt := transactions.WaitMined(ctx, ethClient, transaction, timeout)
+	if err := reverts.CheckReceipt(ctx, ethClient, "setUint256", transaction, receipt); err != nil {
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatalf("Failed to set uint256: %v", err)
+	}
+	traceTransaction(ctx, "setUint256", transaction)
 }
 
-func SetBytes32InGetterSetterContract(getterSetterContract *getter_setter.GetterSetter, auth *bind.TransactOpts, getterSetterDto dto.EthereumDTO, ethClient *ethclient.Client, timeout int) {
+func SetBytes32InGetterSetterContract(ctx context.Context, getterSetterContract *getter_setter.GetterSetter, auth *bind.TransactOpts, getterSetterDto dto.EthereumDTO, ethClient backend.Client, timeout int) {
 	transaction, err := getterSetterContract.SetBytes32(auth, getterSetterDto.Bytes32)
 	if err != nil {
-		log.Fatalf("Failed to set bytes32: %v", err)
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatalf("Failed to set bytes32: %v", reverts.Explain("setBytes32", err))
 	}
 	log.Printf("Waiting for transaction for setBytes32: %s\n", transaction.Hash().Hex())
-	transactions.WaitMined(ethClient, transaction, timeout)
+	logExplorerLink("tx", transaction.Hash().Hex())
+	receipt := transactions.WaitMined(ctx, ethClient, transaction, timeout)
+	if err := reverts.CheckReceipt(ctx, ethClient, "setBytes32", transaction, receipt); err != nil {
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatalf("Failed to set bytes32: %v", err)
+	}
+	traceTransaction(ctx, "setBytes32", transaction)
 }
 
-func SetBytesInGetterSetterContract(getterSetterContract *getter_setter.GetterSetter, auth *bind.TransactOpts, getterSetterDto dto.EthereumDTO, ethClient *ethclient.Client, timeout int) {
+func SetBytesInGetterSetterContract(ctx context.Context, getterSetterContract *getter_setter.GetterSetter, auth *bind.TransactOpts, getterSetterDto dto.EthereumDTO, ethClient backend.Client, timeout int) {
 	transaction, err := getterSetterContract.SetBytes(auth, getterSetterDto.Bytes)
 	if err != nil {
-		log.Fatalf("Failed to set bytes: %v", err)
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatalf("Failed to set bytes: %v", reverts.Explain("setBytes", err))
 	}
 	log.Printf("Waiting for transaction for setBytes: %s\n", transaction.Hash().Hex())
-	transactions.WaitMined(ethClient, transaction, timeout)
+	logExplorerLink("tx", transaction.Hash().Hex())
+	receipt := transactions.WaitMined(ctx, ethClient, transaction, timeout)
+	if err := reverts.CheckReceipt(ctx, ethClient, "setBytes", transaction, receipt); err != nil {
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatalf("Failed to set bytes: %v", err)
+	}
+	traceTransaction(ctx, "setBytes", transaction)
 }
 
 // SetGetterSetterDTO creates a new DTO based on the provided values.
@@ -177,7 +934,7 @@ func SetGetterSetterDTO(values config.Values) dto.EthereumDTO {
 		SetBytes([]byte(values.Bytes)).
 		Build()
 	if err != nil {
-		log.Fatalf("Failed to create DTO: %v", err)
+		utils.Fatalf("Failed to create DTO: %v", err)
 	}
 	log.Println("DTO successfully created:", getterSetterDto)
 	return getterSetterDto
@@ -187,16 +944,17 @@ func SetGetterSetterDTO(values config.Values) dto.EthereumDTO {
 // including the chain ID, the nonce, gas price, and gas limit.
 //
 // Parameters:
-// - ethClient: the Ethereum client (*ethclient.Client)
+// - ctx: the context of the run, used by the transactions sent with the signer (context.Context)
+// - ethClient: the Ethereum client (backend.Client)
 // - deployerAddress: the address of the deployer (common.Address)
 // - privateKey: the private key for authentication (string)
 // Returns:
 // - TransactOpts
-func GetSigner(ethClient *ethclient.Client, deployerAddress common.Address, privateKey string) *bind.TransactOpts {
+func GetSigner(ctx context.Context, ethClient backend.Client, deployerAddress common.Address, privateKey string) *bind.TransactOpts {
 	log.Println("Getting signer options...")
-	chainID := transactions.GetChainId(ethClient)
-	nonce := transactions.GetNonce(ethClient, deployerAddress)
-	gasPrice := transactions.GetTransactionGasPrice(ethClient)
+	chainID := transactions.GetChainId(ctx, ethClient)
+	nonce := transactions.GetNonce(ctx, ethClient, deployerAddress)
+	gasPrice := transactions.GetTransactionGasPrice(ctx, ethClient)
 	privateKeyECDSA := account.PrivateToECDSA(privateKey)
 
 	auth := client.GetTransactor(privateKeyECDSA, chainID)
@@ -204,6 +962,10 @@ func GetSigner(ethClient *ethclient.Client, deployerAddress common.Address, priv
 	auth.Value = big.NewInt(0) // in wei
 	auth.GasLimit = gasLimit
 	auth.GasPrice = gasPrice
+	auth.Context = ctx
+	if tomlConfig.Client.Simulate && !dryRun {
+		auth.Signer = simulateBeforeSending(ctx, ethClient, auth.Signer)
+	}
 	log.Println("Signer options successfully obtained")
 	return auth
 }
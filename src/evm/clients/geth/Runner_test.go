@@ -0,0 +1,1416 @@
+package geth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"io"
+	"log"
+	"main/src/config"
+	"main/src/contracts/getter_setter"
+	"main/src/contracts/transparent_proxy"
+	"main/src/evm/clients/geth/account"
+	"main/src/evm/clients/geth/artifacts"
+	"main/src/evm/clients/geth/backend"
+	"main/src/evm/clients/geth/client"
+	"main/src/evm/clients/geth/journal"
+	"main/src/evm/clients/geth/registry"
+	"main/src/evm/clients/geth/safe"
+	"main/src/evm/clients/geth/types"
+	"main/src/utils"
+	"math/big"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testPrivateKey is the first well-known development account (Anvil/Hardhat), it never holds real funds
+const testPrivateKey = "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+
+func TestDeployCallReadOnSimulatedChain(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name   string
+		values config.Values
+		want   config.Values
+	}{
+		{
+			name:   "all values",
+			values: config.Values{Uint256: big.NewInt(42), Bytes32: "Test", Bytes: "Hello"},
+			want:   config.Values{Uint256: big.NewInt(42), Bytes32: "Test", Bytes: "Hello"},
+		},
+		{
+			name:   "only uint256",
+			values: config.Values{Uint256: big.NewInt(7)},
+			want:   config.Values{Uint256: big.NewInt(7)},
+		},
+		{
+			name:   "only bytes",
+			values: config.Values{Bytes: "only bytes"},
+			want:   config.Values{Uint256: big.NewInt(0), Bytes: "only bytes"},
+		},
+		{
+			name:   "negative uint256 is skipped",
+			values: config.Values{Uint256: big.NewInt(-1), Bytes32: "Test"},
+			want:   config.Values{Uint256: big.NewInt(0), Bytes32: "Test"},
+		},
+	}
+
+	deployer := account.GetDeployerAddressFromPrivateKey(testPrivateKey)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			simulatedClient := client.ConnectSimulatedClient(deployer, "")
+			t.Cleanup(func() { simulatedClient.(io.Closer).Close() })
+
+			auth := GetSigner(ctx, simulatedClient, deployer, testPrivateKey)
+			deployed := client.DeployContract(ctx, auth, simulatedClient, 0)
+			contract := client.AttachToContract(ctx, deployed.Hex(), simulatedClient)
+			ExecuteSetterGetterContractFunction(ctx, tt.values, deployer, testPrivateKey, contract, simulatedClient, 0)
+
+			got := ReadGetterSetterContract(ctx, contract, deployed.Hex(), deployer)
+			if got.ContractAddress != deployed.Hex() || got.DeployerAddress != deployer.Hex() {
+				t.Errorf("addresses = (%s, %s), want (%s, %s)", got.ContractAddress, got.DeployerAddress, deployed.Hex(), deployer.Hex())
+			}
+			if got.UintValue.Cmp(tt.want.Uint256) != 0 {
+				t.Errorf("UintValue = %v, want %v", got.UintValue, tt.want.Uint256)
+			}
+			if want := SetGetterSetterDTO(tt.want).Bytes32; got.Byte32Value != want {
+				t.Errorf("Byte32Value = %x, want %x", got.Byte32Value, want)
+			}
+			if !bytes.Equal(got.BytesValue, []byte(tt.want.Bytes)) {
+				t.Errorf("BytesValue = %q, want %q", got.BytesValue, tt.want.Bytes)
+			}
+		})
+	}
+}
+
+func TestReadSeededContractOnSimulatedChain(t *testing.T) {
+	ctx := context.Background()
+	const contractAddress = "0x5FbDB2315678afecb367f032d93F642f64180aa3"
+	deployer := account.GetDeployerAddressFromPrivateKey(testPrivateKey)
+	simulatedClient := client.ConnectSimulatedClient(deployer, contractAddress)
+	t.Cleanup(func() { simulatedClient.(io.Closer).Close() })
+
+	contract := client.AttachToContract(ctx, contractAddress, simulatedClient)
+	values := config.Values{Uint256: big.NewInt(1), Bytes32: "seeded", Bytes: "seeded"}
+	ExecuteSetterGetterContractFunction(ctx, values, deployer, testPrivateKey, contract, simulatedClient, 0)
+
+	got := ReadGetterSetterContract(ctx, contract, contractAddress, deployer)
+	if got.UintValue.Cmp(values.Uint256) != 0 || string(got.BytesValue) != values.Bytes {
+		t.Errorf("read values = (%v, %q), want (%v, %q)", got.UintValue, got.BytesValue, values.Uint256, values.Bytes)
+	}
+}
+
+func TestDeployArtifactOnSimulatedChain(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	abiPath := filepath.Join(dir, "GetterSetter.abi")
+	binPath := filepath.Join(dir, "GetterSetter.bin")
+	jsonPath := writeArtifact(t)
+	files := map[string]string{
+		abiPath: getter_setter.GetterSetterMetaData.ABI,
+		binPath: strings.TrimPrefix(getter_setter.GetterSetterMetaData.Bin, "0x"),
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("could not write %s: %v", path, err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		artifact config.Artifact
+	}{
+		{name: "raw abi and bin", artifact: config.Artifact{Abi: abiPath, Bin: binPath}},
+		{name: "JSON artifact", artifact: config.Artifact{Path: jsonPath}},
+	}
+
+	deployer := account.GetDeployerAddressFromPrivateKey(testPrivateKey)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			simulatedClient := client.ConnectSimulatedClient(deployer, "")
+			t.Cleanup(func() { simulatedClient.(io.Closer).Close() })
+
+			output := DeployArtifactContract(ctx, tt.artifact, deployer, testPrivateKey, simulatedClient, 0)
+			code, err := simulatedClient.CodeAt(ctx, common.HexToAddress(output.ContractAddress), nil)
+			if err != nil || len(code) == 0 {
+				t.Fatalf("no code at the deployed address %s: %v", output.ContractAddress, err)
+			}
+			if output.DeployerAddress != deployer.Hex() || output.TransactionHash == "" {
+				t.Errorf("unexpected output: %+v", output)
+			}
+		})
+	}
+}
+
+func TestCallAndReadMethodOnSimulatedChain(t *testing.T) {
+	ctx := context.Background()
+	deployer := account.GetDeployerAddressFromPrivateKey(testPrivateKey)
+	simulatedClient := client.ConnectSimulatedClient(deployer, "")
+	t.Cleanup(func() { simulatedClient.(io.Closer).Close() })
+
+	auth := GetSigner(ctx, simulatedClient, deployer, testPrivateKey)
+	contractAddress := client.DeployContract(ctx, auth, simulatedClient, 0).Hex()
+	artifact, err := artifacts.LoadArtifact(writeArtifact(t))
+	if err != nil {
+		t.Fatalf("could not load the artifact: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		call       config.Method
+		read       config.Method
+		wantEvent  string
+		wantReturn interface{}
+	}{
+		{
+			name:       "uint256",
+			call:       config.Method{Name: "setUint256", Args: []string{"0x2a"}},
+			read:       config.Method{Name: "getUint256"},
+			wantEvent:  "SetUint256",
+			wantReturn: "42",
+		},
+		{
+			name:       "bytes",
+			call:       config.Method{Name: "setBytes", Args: []string{"Hello"}},
+			read:       config.Method{Name: "getBytes"},
+			wantEvent:  "SetBytes",
+			wantReturn: "0x48656c6c6f",
+		},
+		{
+			name:       "requested bytes32",
+			call:       config.Method{Name: "requestedBytes32", Args: []string{"0x01", "Test"}},
+			read:       config.Method{Name: "requestId"},
+			wantEvent:  "SetBytes32",
+			wantReturn: "0x0100000000000000000000000000000000000000000000000000000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := CallContractMethod(ctx, artifact, tt.call, contractAddress, deployer, testPrivateKey, simulatedClient, 0)
+			if len(called.Events) != 1 || called.Events[0].Name != tt.wantEvent {
+				t.Fatalf("events = %+v, want a single %s event", called.Events, tt.wantEvent)
+			}
+			if from := called.Events[0].Args["from"]; from != deployer.Hex() {
+				t.Errorf("event from = %v, want %s", from, deployer.Hex())
+			}
+
+			read := ReadContractMethod(ctx, artifact, tt.read, contractAddress, deployer, simulatedClient)
+			if got := fmt.Sprint(read.ReturnValues["0"]); got != tt.wantReturn {
+				t.Errorf("%s returned %s, want %s", tt.read.Name, got, tt.wantReturn)
+			}
+		})
+	}
+}
+
+// writeArtifact writes the GetterSetter ABI and bytecode as a Foundry JSON artifact
+func writeArtifact(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "GetterSetter.json")
+	content := `{"abi":` + getter_setter.GetterSetterMetaData.ABI + `,"bytecode":{"object":"` + getter_setter.GetterSetterMetaData.Bin + `"}}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("could not write %s: %v", path, err)
+	}
+	return path
+}
+
+func TestVerifyContractBytecodeOnSimulatedChain(t *testing.T) {
+	ctx := context.Background()
+	deployer := account.GetDeployerAddressFromPrivateKey(testPrivateKey)
+	simulatedClient := client.ConnectSimulatedClient(deployer, "")
+	t.Cleanup(func() { simulatedClient.(io.Closer).Close() })
+	auth := GetSigner(ctx, simulatedClient, deployer, testPrivateKey)
+	contractAddress := client.DeployContract(ctx, auth, simulatedClient, 0).Hex()
+
+	dir := t.TempDir()
+	abiPath := filepath.Join(dir, "GetterSetter.abi")
+	if err := os.WriteFile(abiPath, []byte(getter_setter.GetterSetterMetaData.ABI), 0644); err != nil {
+		t.Fatalf("could not write %s: %v", abiPath, err)
+	}
+	tests := []struct {
+		name      string
+		bin       string
+		wantMatch bool
+	}{
+		{name: "same bytecode", bin: getter_setter.GetterSetterMetaData.Bin, wantMatch: true},
+		// the binding, without the source tree, e.g. in the Docker image
+		{name: "default artifact", wantMatch: true},
+		// creation code of a contract which only returns 42
+		{name: "different bytecode", bin: "600a600c600039600a6000f3602a60005260206000f3", wantMatch: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			artifact := config.Artifact{}
+			if tt.bin != "" {
+				artifact.Abi, artifact.Bin = abiPath, filepath.Join(dir, strings.ReplaceAll(tt.name, " ", "_")+".bin")
+				if err := os.WriteFile(artifact.Bin, []byte(tt.bin), 0644); err != nil {
+					t.Fatalf("could not write %s: %v", artifact.Bin, err)
+				}
+			}
+			output := VerifyContractBytecode(ctx, artifact, contractAddress, simulatedClient)
+			if tt.bin == "" && output.LocalArtifact != getterSetterArtifactName {
+				t.Errorf("LocalArtifact = %s, want the binding", output.LocalArtifact)
+			}
+			if output.Match != tt.wantMatch || output.ExactMatch != tt.wantMatch {
+				t.Errorf("Match = %t, ExactMatch = %t, want %t", output.Match, output.ExactMatch, tt.wantMatch)
+			}
+		})
+	}
+}
+
+func TestEstimateRunCostOnSimulatedChain(t *testing.T) {
+	ctx := context.Background()
+	deployer := account.GetDeployerAddressFromPrivateKey(testPrivateKey)
+	values := config.Values{Uint256: big.NewInt(42), Bytes32: "Test", Bytes: "Hello"}
+
+	t.Run("demo estimate covers the actual cost", func(t *testing.T) {
+		simulatedClient := client.ConnectSimulatedClient(deployer, "")
+		t.Cleanup(func() { simulatedClient.(io.Closer).Close() })
+		c := config.Config{
+			Client:   config.Client{GasLimit: 3000000},
+			Contract: config.Contract{Mode: utils.DEMO_MODE, Values: values},
+		}
+		estimated := EstimateRunCost(ctx, c, deployer, simulatedClient, nil)
+
+		before, _ := simulatedClient.BalanceAt(ctx, deployer, nil)
+		auth := GetSigner(ctx, simulatedClient, deployer, testPrivateKey)
+		contract := client.AttachToContract(ctx, client.DeployContract(ctx, auth, simulatedClient, 0).Hex(), simulatedClient)
+		ExecuteSetterGetterContractFunction(ctx, values, deployer, testPrivateKey, contract, simulatedClient, 0)
+		after, _ := simulatedClient.BalanceAt(ctx, deployer, nil)
+
+		actual := new(big.Int).Sub(before, after)
+		if actual.Sign() <= 0 || estimated.Cmp(actual) < 0 {
+			t.Errorf("estimated cost %v does not cover the actual cost %v", estimated, actual)
+		}
+	})
+
+	t.Run("upgrade estimate covers the actual cost", func(t *testing.T) {
+		const proxyAddress = "0x5FbDB2315678afecb367f032d93F642f64180aa3"
+		simulatedClient := client.ConnectSimulatedProxyClient(deployer, proxyAddress)
+		t.Cleanup(func() { simulatedClient.(io.Closer).Close() })
+		c := config.Config{
+			Client:   config.Client{GasLimit: 3000000},
+			Contract: config.Contract{Mode: utils.UPGRADE_MODE, Address: proxyAddress, Values: values},
+		}
+		estimated := EstimateRunCost(ctx, c, deployer, simulatedClient, nil)
+
+		before, _ := simulatedClient.BalanceAt(ctx, deployer, nil)
+		UpgradeGetterSetterProxy(ctx, values, "", proxyAddress, deployer, testPrivateKey, simulatedClient, 0)
+		after, _ := simulatedClient.BalanceAt(ctx, deployer, nil)
+
+		actual := new(big.Int).Sub(before, after)
+		if actual.Sign() <= 0 || estimated.Cmp(actual) < 0 {
+			t.Errorf("estimated cost %v does not cover the actual cost %v", estimated, actual)
+		}
+	})
+
+	t.Run("proxy deployment estimate covers the actual cost", func(t *testing.T) {
+		simulatedClient := client.ConnectSimulatedClient(deployer, "")
+		t.Cleanup(func() { simulatedClient.(io.Closer).Close() })
+		c := config.Config{
+			Client:   config.Client{GasLimit: 3000000},
+			Contract: config.Contract{Mode: utils.DEPLOY_MODE, Proxy: true},
+		}
+		estimated := EstimateRunCost(ctx, c, deployer, simulatedClient, nil)
+
+		before, _ := simulatedClient.BalanceAt(ctx, deployer, nil)
+		DeployGetterSetterProxy(ctx, deployer, testPrivateKey, simulatedClient, 0)
+		after, _ := simulatedClient.BalanceAt(ctx, deployer, nil)
+
+		actual := new(big.Int).Sub(before, after)
+		if actual.Sign() <= 0 || estimated.Cmp(actual) < 0 {
+			t.Errorf("estimated cost %v does not cover the actual cost %v", estimated, actual)
+		}
+	})
+
+	t.Run("CREATE2 estimate covers the actual cost, then skips the deployed contract", func(t *testing.T) {
+		simulatedClient := client.ConnectSimulatedClient(deployer, "")
+		t.Cleanup(func() { simulatedClient.(io.Closer).Close() })
+		c := config.Config{
+			Client:   config.Client{GasLimit: 3000000},
+			Contract: config.Contract{Mode: utils.DEPLOY_MODE, Create2: true, Salt: "0x2a"},
+		}
+		estimated := EstimateRunCost(ctx, c, deployer, simulatedClient, nil)
+
+		salt, _ := utils.ParseSalt(c.Contract.Salt)
+		before, _ := simulatedClient.BalanceAt(ctx, deployer, nil)
+		DeployGetterSetterCreate2(ctx, GetSigner(ctx, simulatedClient, deployer, testPrivateKey), simulatedClient, salt, 0)
+		after, _ := simulatedClient.BalanceAt(ctx, deployer, nil)
+
+		actual := new(big.Int).Sub(before, after)
+		if actual.Sign() <= 0 || estimated.Cmp(actual) < 0 {
+			t.Errorf("estimated cost %v does not cover the actual cost %v", estimated, actual)
+		}
+		if cost := EstimateRunCost(ctx, c, deployer, simulatedClient, nil); cost.Sign() != 0 {
+			t.Errorf("estimated cost with the contract deployed = %v, want 0", cost)
+		}
+	})
+
+	t.Run("resumed run estimates the steps left", func(t *testing.T) {
+		const contractAddress = "0x5FbDB2315678afecb367f032d93F642f64180aa3"
+		simulatedClient := client.ConnectSimulatedClient(deployer, contractAddress)
+		t.Cleanup(func() { simulatedClient.(io.Closer).Close() })
+		c := config.Config{
+			Client:   config.Client{GasLimit: 3000000},
+			Contract: config.Contract{Mode: utils.CALL_MODE, Address: contractAddress, Values: values},
+		}
+		recorded := journal.New(filepath.Join(t.TempDir(), "journal.json"), utils.CALL_MODE, 1337, deployer, journal.Inputs{})
+		full := EstimateRunCost(ctx, c, deployer, simulatedClient, recorded)
+
+		receipt := &gethtypes.Receipt{TxHash: common.HexToHash("0x01"), BlockNumber: big.NewInt(1)}
+		if err := recorded.Mined("setUint256", receipt); err != nil {
+			t.Fatalf("Mined() unexpected error: %v", err)
+		}
+		left := EstimateRunCost(ctx, c, deployer, simulatedClient, recorded)
+		if left.Sign() <= 0 || left.Cmp(full) >= 0 {
+			t.Errorf("estimated cost of the steps left = %v, want less than %v", left, full)
+		}
+
+		for _, step := range []string{"setBytes32", "setBytes"} {
+			if err := recorded.Mined(step, receipt); err != nil {
+				t.Fatalf("Mined() unexpected error: %v", err)
+			}
+		}
+		if cost := EstimateRunCost(ctx, c, deployer, simulatedClient, recorded); cost.Sign() != 0 {
+			t.Errorf("estimated cost with every step recorded = %v, want 0", cost)
+		}
+	})
+
+	t.Run("modes without transactions cost nothing", func(t *testing.T) {
+		const contractAddress = "0x5FbDB2315678afecb367f032d93F642f64180aa3"
+		simulatedClient := client.ConnectSimulatedClient(deployer, contractAddress)
+		t.Cleanup(func() { simulatedClient.(io.Closer).Close() })
+		for _, mode := range []string{utils.READ_ONLY_MODE, utils.VERIFY_MODE, utils.STORAGE_MODE} {
+			c := config.Config{Contract: config.Contract{Mode: mode, Address: contractAddress}}
+			if cost := EstimateRunCost(ctx, c, deployer, simulatedClient, nil); cost.Sign() != 0 {
+				t.Errorf("%s estimated cost = %v, want 0", mode, cost)
+			}
+		}
+	})
+}
+
+func TestReadWithoutAccountOnSimulatedChain(t *testing.T) {
+	ctx := context.Background()
+	const contractAddress = "0x5FbDB2315678afecb367f032d93F642f64180aa3"
+	simulatedClient := client.ConnectSimulatedClient(common.Address{}, contractAddress)
+	t.Cleanup(func() { simulatedClient.(io.Closer).Close() })
+
+	tests := []struct {
+		name         string
+		from         common.Address
+		wantDeployer string
+	}{
+		{name: "no sender address", from: common.Address{}, wantDeployer: ""},
+		{name: "with a from address", from: common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8"), wantDeployer: "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contract := client.AttachToContract(ctx, contractAddress, simulatedClient)
+			got := ReadGetterSetterContract(ctx, contract, contractAddress, tt.from)
+			if got.DeployerAddress != tt.wantDeployer || got.UintValue.Sign() != 0 {
+				t.Errorf("read = %+v, want deployer %q and a zero uint256", got, tt.wantDeployer)
+			}
+		})
+	}
+}
+
+func TestResumeJournaledStepsOnSimulatedChain(t *testing.T) {
+	ctx := context.Background()
+	deployer := account.GetDeployerAddressFromPrivateKey(testPrivateKey)
+	simulatedClient := client.ConnectSimulatedClient(deployer, "")
+	t.Cleanup(func() { simulatedClient.(io.Closer).Close() })
+	ethClient = simulatedClient
+	path := filepath.Join(t.TempDir(), "journal.json")
+	runJournal = journal.New(path, utils.DEMO_MODE, 1337, deployer, journal.Inputs{Uint256: "42"})
+	t.Cleanup(func() { ethClient, runJournal = nil, nil })
+
+	deployed := DeployGetterSetterContract(ctx, GetSigner(ctx, simulatedClient, deployer, testPrivateKey), simulatedClient, 0)
+	contract := client.AttachToContract(ctx, deployed.Hex(), simulatedClient)
+
+	// The previous run signed setUint256, but the node never got it
+	auth := GetSigner(ctx, simulatedClient, deployer, testPrivateKey)
+	auth.NoSend = true
+	lost, err := contract.SetUint256(auth, big.NewInt(42))
+	if err != nil {
+		t.Fatalf("could not sign setUint256: %v", err)
+	}
+	if err := runJournal.Sent("setUint256", lost); err != nil {
+		t.Fatal(err)
+	}
+
+	if runJournal, err = journal.Load(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkResumable(runJournal, utils.DEMO_MODE, 1337, deployer, journal.Inputs{Uint256: "42"}); err != nil {
+		t.Fatalf("checkResumable() unexpected error: %v", err)
+	}
+	nonceBefore, _ := simulatedClient.PendingNonceAt(ctx, deployer)
+
+	if resumed := DeployGetterSetterContract(ctx, GetSigner(ctx, simulatedClient, deployer, testPrivateKey), simulatedClient, 0); resumed != deployed {
+		t.Errorf("resumed deployment = %s, want the previous contract %s", resumed.Hex(), deployed.Hex())
+	}
+	ExecuteSetterGetterContractFunction(ctx, config.Values{Uint256: big.NewInt(42)}, deployer, testPrivateKey, contract, simulatedClient, 0)
+
+	nonceAfter, _ := simulatedClient.PendingNonceAt(ctx, deployer)
+	if nonceAfter != nonceBefore+1 {
+		t.Errorf("nonce went from %d to %d, want only the lost transaction to be sent", nonceBefore, nonceAfter)
+	}
+	if step, _ := runJournal.Step("setUint256"); step.Status != journal.StatusMined || step.TransactionHash != lost.Hash().Hex() {
+		t.Errorf("setUint256 step = %+v, want the lost transaction mined", step)
+	}
+	if got := ReadGetterSetterContract(ctx, contract, deployed.Hex(), deployer); got.UintValue.Cmp(big.NewInt(42)) != 0 {
+		t.Errorf("UintValue = %v, want 42", got.UintValue)
+	}
+}
+
+func TestCheckResumable(t *testing.T) {
+	deployer := account.GetDeployerAddressFromPrivateKey(testPrivateKey)
+	inputs := journal.Inputs{Uint256: "42", Artifact: "Counter.json", ConstructorArgs: []string{"1"}}
+	previous := journal.New("journal.json", utils.DEMO_MODE, 11155111, deployer, inputs)
+
+	tests := []struct {
+		name    string
+		mode    string
+		chainID uint64
+		sender  common.Address
+		inputs  journal.Inputs
+		wantErr bool
+	}{
+		{name: "same run", mode: utils.DEMO_MODE, chainID: 11155111, sender: deployer, inputs: inputs},
+		{name: "other mode", mode: utils.DEPLOY_MODE, chainID: 11155111, sender: deployer, inputs: inputs, wantErr: true},
+		{name: "other chain", mode: utils.DEMO_MODE, chainID: 1, sender: deployer, inputs: inputs, wantErr: true},
+		{name: "other sender", mode: utils.DEMO_MODE, chainID: 11155111, sender: common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8"), inputs: inputs, wantErr: true},
+		{name: "other value", mode: utils.DEMO_MODE, chainID: 11155111, sender: deployer, inputs: journal.Inputs{Uint256: "43", Artifact: "Counter.json", ConstructorArgs: []string{"1"}}, wantErr: true},
+		{name: "other constructor arguments", mode: utils.DEMO_MODE, chainID: 11155111, sender: deployer, inputs: journal.Inputs{Uint256: "42", Artifact: "Counter.json", ConstructorArgs: []string{"2"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkResumable(previous, tt.mode, tt.chainID, tt.sender, tt.inputs); (err != nil) != tt.wantErr {
+				t.Errorf("checkResumable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRegistryContractName(t *testing.T) {
+	counter := config.Artifact{Path: "out/Counter.sol/Counter.json"}
+	tests := []struct {
+		name     string
+		mode     string
+		artifact config.Artifact
+		want     string
+	}{
+		{name: "call mode", mode: utils.CALL_MODE, artifact: counter, want: "GetterSetter"},
+		{name: "read-only mode", mode: utils.READ_ONLY_MODE, want: "GetterSetter"},
+		{name: "verify mode without artifact", mode: utils.VERIFY_MODE, want: "GetterSetter"},
+		{name: "verify mode with an artifact", mode: utils.VERIFY_MODE, artifact: counter, want: "Counter"},
+		{name: "method mode with a raw .bin", mode: utils.METHOD_MODE, artifact: config.Artifact{Abi: "build/Token.abi", Bin: "build/Token.bin"}, want: "Token"},
+		{name: "storage mode", mode: utils.STORAGE_MODE, artifact: counter, want: "GetterSetter"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := registryContractName(tt.mode, tt.artifact); got != tt.want {
+				t.Errorf("registryContractName() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// A contract the CREATE2 deployment found already deployed is recorded in the registry, keeping what a previous run recorded
+func TestRecordFoundCreate2Deployment(t *testing.T) {
+	ctx := context.Background()
+	deployer := account.GetDeployerAddressFromPrivateKey(testPrivateKey)
+	simulatedClient := client.ConnectSimulatedClient(deployer, "")
+	t.Cleanup(func() { simulatedClient.(io.Closer).Close() })
+	deployed := DeployGetterSetterContract(ctx, GetSigner(ctx, simulatedClient, deployer, testPrivateKey), simulatedClient, 0)
+
+	registryPath := filepath.Join(t.TempDir(), "deployments.json")
+	// Recorded as on a live node, the simulated chain has no registry
+	ethClient, rpcURL = simulatedClient, "http://localhost:8545"
+	tomlConfig.Contract = config.Contract{Registry: registryPath, Create2: true, Alias: "found"}
+	runJournal = journal.New(filepath.Join(t.TempDir(), "journal.json"), utils.DEPLOY_MODE, 1337, deployer, journal.Inputs{})
+	t.Cleanup(func() { ethClient, rpcURL, runJournal, tomlConfig = nil, "", nil, config.Config{} })
+
+	// No "deploy" step: the contract was already there
+	recordDeployment(ctx, "deploy", getterSetterContractName, deployed)
+	deploymentRegistry, err := registry.Load(registryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found, err := deploymentRegistry.Resolve(1337, "found", getterSetterContractName, false)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if found.Address != deployed.Hex() || found.TransactionHash != "" || found.BytecodeHash == "" || found.Proxy {
+		t.Errorf("found deployment = %+v, want %s without a transaction", found, deployed.Hex())
+	}
+
+	// A deployment recorded before keeps its transaction, under the new alias
+	found.TransactionHash = "0x01"
+	if err := deploymentRegistry.Add(1337, found); err != nil {
+		t.Fatal(err)
+	}
+	tomlConfig.Contract.Alias = "again"
+	recordDeployment(ctx, "deploy", getterSetterContractName, deployed)
+	if deploymentRegistry, err = registry.Load(registryPath); err != nil {
+		t.Fatal(err)
+	}
+	if again, err := deploymentRegistry.Resolve(1337, "again", getterSetterContractName, false); err != nil || again.TransactionHash != "0x01" || len(deploymentRegistry.Chains["1337"]) != 1 {
+		t.Errorf("Resolve() = %+v, %v, want the recorded deployment under the new alias", again, err)
+	}
+}
+
+func TestDeployGetterSetterCreate2OnSimulatedChain(t *testing.T) {
+	ctx := context.Background()
+	deployer := account.GetDeployerAddressFromPrivateKey(testPrivateKey)
+	simulatedClient := client.ConnectSimulatedClient(deployer, "")
+	t.Cleanup(func() { simulatedClient.(io.Closer).Close() })
+
+	salt := common.HexToHash("0x2a")
+	initCode := common.FromHex(getter_setter.GetterSetterMetaData.Bin)
+	want := crypto.CreateAddress2(client.DeterministicDeployerAddress, salt, crypto.Keccak256(initCode))
+
+	deployed := DeployGetterSetterCreate2(ctx, GetSigner(ctx, simulatedClient, deployer, testPrivateKey), simulatedClient, salt, 0)
+	if deployed != want {
+		t.Fatalf("DeployGetterSetterCreate2() = %s, want %s", deployed.Hex(), want.Hex())
+	}
+	contract := client.AttachToContract(ctx, deployed.Hex(), simulatedClient)
+	ExecuteSetterGetterContractFunction(ctx, config.Values{Uint256: big.NewInt(42)}, deployer, testPrivateKey, contract, simulatedClient, 0)
+
+	// The same salt finds the contract already deployed: no transaction, the state is kept
+	nonce, _ := simulatedClient.NonceAt(ctx, deployer, nil)
+	if again := DeployGetterSetterCreate2(ctx, GetSigner(ctx, simulatedClient, deployer, testPrivateKey), simulatedClient, salt, 0); again != deployed {
+		t.Errorf("second DeployGetterSetterCreate2() = %s, want %s", again.Hex(), deployed.Hex())
+	}
+	if after, _ := simulatedClient.NonceAt(ctx, deployer, nil); after != nonce {
+		t.Errorf("nonce = %d after the skipped deployment, want %d", after, nonce)
+	}
+	if got := ReadGetterSetterContract(ctx, contract, deployed.Hex(), deployer); got.UintValue.Cmp(big.NewInt(42)) != 0 {
+		t.Errorf("UintValue = %v, want 42", got.UintValue)
+	}
+
+	// Another salt, another address
+	other := DeployGetterSetterCreate2(ctx, GetSigner(ctx, simulatedClient, deployer, testPrivateKey), simulatedClient, common.HexToHash("0x2b"), 0)
+	if other == deployed {
+		t.Errorf("deployments with different salts share the address %s", other.Hex())
+	}
+}
+
+func TestDeployAndUpgradeProxyOnSimulatedChain(t *testing.T) {
+	ctx := context.Background()
+	deployer := account.GetDeployerAddressFromPrivateKey(testPrivateKey)
+	simulatedClient := client.ConnectSimulatedClient(deployer, "")
+	t.Cleanup(func() { simulatedClient.(io.Closer).Close() })
+
+	proxy := DeployGetterSetterProxy(ctx, deployer, testPrivateKey, simulatedClient, 0)
+	contract := client.AttachToContract(ctx, proxy.Hex(), simulatedClient)
+	values := config.Values{Uint256: big.NewInt(42), Bytes32: "Test", Bytes: "Hello"}
+	ExecuteSetterGetterContractFunction(ctx, values, deployer, testPrivateKey, contract, simulatedClient, 0)
+
+	// A new implementation is deployed
+	upgraded := UpgradeGetterSetterProxy(ctx, config.Values{}, "", proxy.Hex(), deployer, testPrivateKey, simulatedClient, 0)
+	if !upgraded.StoragePreserved || upgraded.NewImplementation == upgraded.PreviousImplementation {
+		t.Fatalf("UpgradeGetterSetterProxy() = %+v, want a new implementation and the values preserved", upgraded)
+	}
+	if upgraded.ValuesAfter.UintValue.Cmp(values.Uint256) != 0 || string(upgraded.ValuesAfter.BytesValue) != values.Bytes {
+		t.Errorf("values after the upgrade = (%v, %q), want (%v, %q)", upgraded.ValuesAfter.UintValue, upgraded.ValuesAfter.BytesValue, values.Uint256, values.Bytes)
+	}
+	if upgraded.TransactionHash == (common.Hash{}).Hex() {
+		t.Error("TransactionHash is empty")
+	}
+
+	// Back to the previous implementation, given by address, with a value set before the upgrade
+	downgraded := UpgradeGetterSetterProxy(ctx, config.Values{Bytes: "changed"}, upgraded.PreviousImplementation, proxy.Hex(), deployer, testPrivateKey, simulatedClient, 0)
+	if !downgraded.StoragePreserved || downgraded.NewImplementation != upgraded.PreviousImplementation {
+		t.Fatalf("UpgradeGetterSetterProxy() = %+v, want the previous implementation and the values preserved", downgraded)
+	}
+	if string(downgraded.ValuesAfter.BytesValue) != "changed" {
+		t.Errorf("BytesValue after the upgrade = %q, want %q", downgraded.ValuesAfter.BytesValue, "changed")
+	}
+}
+
+func TestRunLoadTestOnSimulatedChain(t *testing.T) {
+	ctx := context.Background()
+	const contractAddress = "0x5FbDB2315678afecb367f032d93F642f64180aa3"
+	const secondKey = "59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d"
+	deployer := account.GetDeployerAddressFromPrivateKey(testPrivateKey)
+	second := account.GetDeployerAddressFromPrivateKey(secondKey)
+	simulatedClient := client.ConnectSimulatedClient(deployer, contractAddress)
+	t.Cleanup(func() { simulatedClient.(io.Closer).Close() })
+
+	// Only the deployer is funded on the simulated chain
+	funding := GetSigner(ctx, simulatedClient, deployer, testPrivateKey)
+	funding.Value = big.NewInt(1e18)
+	funding.GasLimit = 21000
+	transaction, err := bind.NewBoundContract(second, abi.ABI{}, simulatedClient, simulatedClient, simulatedClient).Transfer(funding)
+	if err != nil {
+		t.Fatalf("could not fund %s: %v", second.Hex(), err)
+	}
+	if _, err := bind.WaitMined(ctx, simulatedClient, transaction); err != nil {
+		t.Fatalf("funding was not mined: %v", err)
+	}
+
+	load := config.Load{Transactions: 20, Concurrency: 4, MaxInFlight: 5, PollInterval: 10}
+	report := RunLoadTest(ctx, load, contractAddress, []string{testPrivateKey, secondKey}, simulatedClient, 0)
+	if report.Sent != 20 || report.Mined != 20 || report.Reverted != 0 || report.SendFailures != 0 || report.WaitFailures != 0 {
+		t.Fatalf("RunLoadTest() = %+v, want the 20 transactions mined", report)
+	}
+	if len(report.Errors) != 0 {
+		t.Errorf("Errors = %v, want none", report.Errors)
+	}
+	if report.GasUsed == 0 || report.AverageGasUsed != report.GasUsed/20 {
+		t.Errorf("GasUsed = %d, AverageGasUsed = %d", report.GasUsed, report.AverageGasUsed)
+	}
+	if report.LatencyMs.Min > report.LatencyMs.P50 || report.LatencyMs.P50 > report.LatencyMs.Max {
+		t.Errorf("LatencyMs = %+v, want ordered percentiles", report.LatencyMs)
+	}
+
+	// Each account sent its share, without nonce gaps
+	for _, address := range []common.Address{deployer, second} {
+		nonce, err := simulatedClient.NonceAt(ctx, address, nil)
+		if err != nil {
+			t.Fatalf("NonceAt() error = %v", err)
+		}
+		want := uint64(10)
+		if address == deployer {
+			want = 11 // the funding transfer
+		}
+		if nonce != want {
+			t.Errorf("nonce of %s = %d, want %d", address.Hex(), nonce, want)
+		}
+	}
+	value, err := client.AttachToContract(ctx, contractAddress, simulatedClient).GetUint256(nil)
+	if err != nil {
+		t.Fatalf("GetUint256() error = %v", err)
+	}
+	if value.Sign() <= 0 || value.Cmp(big.NewInt(20)) > 0 {
+		t.Errorf("GetUint256() = %v, want one of the values set by the load test", value)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	latencies := make([]time.Duration, 100)
+	for i := range latencies {
+		latencies[i] = time.Duration(i+1) * time.Millisecond
+	}
+	tests := []struct {
+		name   string
+		sorted []time.Duration
+		p      float64
+		want   float64
+	}{
+		{name: "no latencies", sorted: nil, p: 50, want: 0},
+		{name: "min", sorted: latencies, p: 0, want: 1},
+		{name: "p50", sorted: latencies, p: 50, want: 50},
+		{name: "p99", sorted: latencies, p: 99, want: 99},
+		{name: "max", sorted: latencies, p: 100, want: 100},
+		{name: "single latency", sorted: []time.Duration{1500 * time.Microsecond}, p: 90, want: 1.5},
+		{name: "nearest rank", sorted: []time.Duration{time.Millisecond, 2 * time.Millisecond, 3 * time.Millisecond}, p: 50, want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := percentile(tt.sorted, tt.p); got != tt.want {
+				t.Errorf("percentile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFundAndSweepDerivedAccountsOnSimulatedChain(t *testing.T) {
+	ctx := context.Background()
+	deployer := account.GetDeployerAddressFromPrivateKey(testPrivateKey)
+	simulatedClient := client.ConnectSimulatedClient(deployer, "")
+	t.Cleanup(func() { simulatedClient.(io.Closer).Close() })
+
+	// The first account of the development mnemonic is the deployer
+	accounts := deriveAccounts(config.Account{Mnemonic: "test test test test test test test test test test test junk", Derived: 3})
+	target := big.NewInt(1e18)
+	funded := FundDerivedAccounts(ctx, deployer, testPrivateKey, accounts, target, simulatedClient, 0)
+	if funded.Total.Cmp(big.NewInt(2e18)) != 0 || funded.Transfers[0].Skipped == "" {
+		t.Fatalf("FundDerivedAccounts() = %+v, want 2 accounts funded, the deployer skipped", funded)
+	}
+	for _, transfer := range funded.Transfers[1:] {
+		if transfer.BalanceAfter.Cmp(target) != 0 || transfer.TransactionHash == "" {
+			t.Errorf("transfer to %s = %+v, want a balance of %v", transfer.To, transfer, target)
+		}
+	}
+	if again := FundDerivedAccounts(ctx, deployer, testPrivateKey, accounts, target, simulatedClient, 0); again.Total.Sign() != 0 {
+		t.Errorf("FundDerivedAccounts() of funded accounts transferred %v, want 0", again.Total)
+	}
+
+	// The setters are sent from the funded accounts in turn
+	contract := client.AttachToContract(ctx, client.DeployContract(ctx, GetSigner(ctx, simulatedClient, deployer, testPrivateKey), simulatedClient, 0).Hex(), simulatedClient)
+	executeSetters(ctx, config.Values{Uint256: big.NewInt(42), Bytes32: "Test", Bytes: "Hello"}, accounts[1:], contract, simulatedClient, 0)
+	for i, want := range []uint64{2, 1} {
+		if nonce, _ := simulatedClient.NonceAt(ctx, accounts[i+1].address, nil); nonce != want {
+			t.Errorf("nonce of %s = %d, want %d", accounts[i+1].path, nonce, want)
+		}
+	}
+
+	swept := SweepDerivedAccounts(ctx, deployer, accounts, simulatedClient, 0)
+	if swept.Total.Sign() <= 0 || swept.Transfers[0].Skipped == "" {
+		t.Fatalf("SweepDerivedAccounts() = %+v, want 2 accounts swept, the deployer skipped", swept)
+	}
+	for _, transfer := range swept.Transfers[1:] {
+		if transfer.BalanceAfter.Sign() != 0 {
+			t.Errorf("balance of %s after the sweep = %v, want 0", transfer.From, transfer.BalanceAfter)
+		}
+	}
+}
+
+func TestDryRunOnSimulatedChain(t *testing.T) {
+	ctx := context.Background()
+	deployer := account.GetDeployerAddressFromPrivateKey(testPrivateKey)
+	simulatedClient := client.ConnectSimulatedClient(deployer, "")
+	t.Cleanup(func() { simulatedClient.(io.Closer).Close() })
+	ethClient, mode, deployerAddress, privateKey = simulatedClient, utils.DEMO_MODE, deployer, testPrivateKey
+	values, gasLimit = config.Values{Uint256: big.NewInt(42), Bytes32: "Test", Bytes: "Hello"}, 3000000
+	t.Cleanup(func() {
+		ethClient, mode, deployerAddress, privateKey = nil, "", common.Address{}, ""
+		values, gasLimit = config.Values{}, 0
+	})
+
+	output := DryRun(ctx)
+	wantSteps := []string{"deploy", "setUint256", "setBytes32", "setBytes"}
+	if len(output.Transactions) != len(wantSteps) {
+		t.Fatalf("DryRun() signed %d transaction(s), want %d", len(output.Transactions), len(wantSteps))
+	}
+	for i, step := range output.Transactions {
+		if step.Step != wantSteps[i] || step.Nonce != uint64(i) || step.Simulation != "success" || step.EstimatedGas == 0 {
+			t.Errorf("transaction %d = %+v, want %s with nonce %d, simulated successfully", i, step, wantSteps[i], i)
+		}
+		if i > 0 && step.To != output.Transactions[0].ContractAddress {
+			t.Errorf("%s is sent to %s, want the deployed contract %s", step.Step, step.To, output.Transactions[0].ContractAddress)
+		}
+	}
+	if nonce, _ := simulatedClient.PendingNonceAt(ctx, deployer); nonce != 0 {
+		t.Fatalf("nonce after the dry run = %d, want nothing broadcast", nonce)
+	}
+
+	// The reviewed transactions are the ones sent
+	for _, step := range output.Transactions {
+		transaction := new(gethtypes.Transaction)
+		if err := transaction.UnmarshalBinary(common.FromHex(step.RawTransaction)); err != nil {
+			t.Fatalf("could not decode the %s transaction: %v", step.Step, err)
+		}
+		if err := simulatedClient.SendTransaction(ctx, transaction); err != nil {
+			t.Fatalf("could not send the %s transaction: %v", step.Step, err)
+		}
+	}
+	contractAddress := output.Transactions[0].ContractAddress
+	got := ReadGetterSetterContract(ctx, client.AttachToContract(ctx, contractAddress, simulatedClient), contractAddress, deployer)
+	if got.UintValue.Cmp(values.Uint256) != 0 || string(got.BytesValue) != values.Bytes {
+		t.Errorf("values after sending the dry run = (%v, %q), want (%v, %q)", got.UintValue, got.BytesValue, values.Uint256, values.Bytes)
+	}
+}
+
+func TestDryRunProxyDeploymentOnSimulatedChain(t *testing.T) {
+	ctx := context.Background()
+	deployer := account.GetDeployerAddressFromPrivateKey(testPrivateKey)
+	simulatedClient := client.ConnectSimulatedClient(deployer, "")
+	t.Cleanup(func() { simulatedClient.(io.Closer).Close() })
+	ethClient, mode, deployerAddress, privateKey, gasLimit = simulatedClient, utils.DEPLOY_MODE, deployer, testPrivateKey, 3000000
+	tomlConfig.Contract = config.Contract{Proxy: true}
+	t.Cleanup(func() {
+		ethClient, mode, deployerAddress, privateKey, gasLimit = nil, "", common.Address{}, "", 0
+		tomlConfig.Contract = config.Contract{}
+	})
+
+	output := DryRun(ctx)
+	wantSteps := []string{"deploy-implementation", "deploy-proxy-admin", "deploy"}
+	if len(output.Transactions) != len(wantSteps) {
+		t.Fatalf("DryRun() signed %d transaction(s), want %d", len(output.Transactions), len(wantSteps))
+	}
+	for i, step := range output.Transactions {
+		if step.Step != wantSteps[i] || step.Simulation != "success" {
+			t.Errorf("transaction %d = %+v, want %s simulated successfully", i, step, wantSteps[i])
+		}
+		transaction := new(gethtypes.Transaction)
+		if err := transaction.UnmarshalBinary(common.FromHex(step.RawTransaction)); err != nil {
+			t.Fatalf("could not decode the %s transaction: %v", step.Step, err)
+		}
+		if err := simulatedClient.SendTransaction(ctx, transaction); err != nil {
+			t.Fatalf("could not send the %s transaction: %v", step.Step, err)
+		}
+	}
+	implementation, proxyAdmin, err := client.ReadProxySlots(ctx, simulatedClient, common.HexToAddress(output.Transactions[2].ContractAddress))
+	if err != nil || implementation.Hex() != output.Transactions[0].ContractAddress || proxyAdmin.Hex() != output.Transactions[1].ContractAddress {
+		t.Errorf("ReadProxySlots() = (%s, %s, %v), want the implementation and the ProxyAdmin of the dry run", implementation.Hex(), proxyAdmin.Hex(), err)
+	}
+}
+
+func TestSimulateBeforeSending(t *testing.T) {
+	ctx := context.Background()
+	const proxyAddress = "0x5FbDB2315678afecb367f032d93F642f64180aa3"
+	deployer := account.GetDeployerAddressFromPrivateKey(testPrivateKey)
+	simulatedClient := client.ConnectSimulatedProxyClient(deployer, proxyAddress)
+	t.Cleanup(func() { simulatedClient.(io.Closer).Close() })
+	implementation, proxyAdmin, err := client.ReadProxySlots(ctx, simulatedClient, common.HexToAddress(proxyAddress))
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin, err := transparent_proxy.NewProxyAdmin(proxyAdmin, simulatedClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		target  common.Address
+		wantErr bool
+	}{
+		{name: "upgrade to an address without code reverts", target: common.HexToAddress("0x01"), wantErr: true},
+		{name: "upgrade to a contract succeeds", target: implementation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth := GetSigner(ctx, simulatedClient, deployer, testPrivateKey)
+			auth.GasLimit = 100000
+			auth.Signer = simulateBeforeSending(ctx, simulatedClient, auth.Signer)
+			nonceBefore, _ := simulatedClient.PendingNonceAt(ctx, deployer)
+			_, err := admin.Upgrade(auth, common.HexToAddress(proxyAddress), tt.target)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Transact() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			nonceAfter, _ := simulatedClient.PendingNonceAt(ctx, deployer)
+			if sent := nonceAfter != nonceBefore; sent == tt.wantErr {
+				t.Errorf("transaction sent = %t, want %t", sent, !tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDecodeBytesSlot(t *testing.T) {
+	tests := []struct {
+		name       string
+		raw        common.Hash
+		wantLength uint64
+		wantShort  bool
+		wantErr    bool
+	}{
+		{name: "empty", raw: common.Hash{}, wantLength: 0, wantShort: true},
+		{name: "short", raw: common.HexToHash("0x4869000000000000000000000000000000000000000000000000000000000004"), wantLength: 2, wantShort: true},
+		{name: "31 bytes", raw: common.HexToHash("0x616161616161616161616161616161616161616161616161616161616161613e"), wantLength: 31, wantShort: true},
+		{name: "long", raw: common.BigToHash(big.NewInt(40*2 + 1)), wantLength: 40},
+		{name: "dirty short", raw: common.HexToHash("0x4869000000000000000000000000000000000000000000000000000000ff0004"), wantErr: true},
+		{name: "short longer than 31 bytes", raw: common.BigToHash(big.NewInt(32 * 2)), wantErr: true},
+		{name: "long shorter than 32 bytes", raw: common.BigToHash(big.NewInt(1*2 + 1)), wantErr: true},
+		{name: "long beyond the inspected slots", raw: common.HexToHash("0x0100000000000000000000000000000000000000000000000000000000000001"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			length, short, err := decodeBytesSlot(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeBytesSlot() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if err == nil && (length != tt.wantLength || short != tt.wantShort) {
+				t.Errorf("decodeBytesSlot() = (%d, %t), want (%d, %t)", length, short, tt.wantLength, tt.wantShort)
+			}
+		})
+	}
+}
+
+func TestInspectGetterSetterStorageOnSimulatedChain(t *testing.T) {
+	ctx := context.Background()
+	deployer := account.GetDeployerAddressFromPrivateKey(testPrivateKey)
+	simulatedClient := client.ConnectSimulatedClient(deployer, "")
+	t.Cleanup(func() { simulatedClient.(io.Closer).Close() })
+	auth := GetSigner(ctx, simulatedClient, deployer, testPrivateKey)
+	deployed := client.DeployContract(ctx, auth, simulatedClient, 0)
+	contract := client.AttachToContract(ctx, deployed.Hex(), simulatedClient)
+
+	tests := []struct {
+		name          string
+		values        config.Values
+		wantEncoding  string
+		wantDataSlots int
+	}{
+		{name: "short bytes", values: config.Values{Uint256: big.NewInt(42), Bytes32: "Test", Bytes: "Hello"}, wantEncoding: "short"},
+		{name: "long bytes", values: config.Values{Bytes: strings.Repeat("a", 40)}, wantEncoding: "long", wantDataSlots: 2},
+		// Shortening the value clears its data slots
+		{name: "long then short bytes", values: config.Values{Bytes: "Hi"}, wantEncoding: "short"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ExecuteSetterGetterContractFunction(ctx, tt.values, deployer, testPrivateKey, contract, simulatedClient, 0)
+			output := InspectGetterSetterStorage(ctx, deployed.Hex(), simulatedClient)
+			if !output.Match {
+				t.Fatalf("Match = false, slots %+v", output.Slots)
+			}
+			bytesSlot := output.Slots[len(output.Slots)-1]
+			if bytesSlot.Encoding != tt.wantEncoding || len(bytesSlot.DataSlots) != tt.wantDataSlots {
+				t.Errorf("getBytes encoding = %s with %d data slots, want %s with %d", bytesSlot.Encoding, len(bytesSlot.DataSlots), tt.wantEncoding, tt.wantDataSlots)
+			}
+		})
+	}
+
+	t.Run("invalid bytes encoding", func(t *testing.T) {
+		// A long encoding of a single byte, which the getter rejects
+		address := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+		seeded := backend.NewSimulatedClient(gethtypes.GenesisAlloc{address: {
+			Code:    client.SimulateRuntimeCode(common.FromHex(getter_setter.GetterSetterMetaData.Bin)),
+			Storage: map[common.Hash]common.Hash{getter_setter.Uint256Slot: common.BigToHash(big.NewInt(7)), getter_setter.BytesSlot: common.BigToHash(big.NewInt(3))},
+		}})
+		t.Cleanup(func() { seeded.Close() })
+		output := InspectGetterSetterStorage(ctx, address.Hex(), seeded)
+		if output.Match {
+			t.Fatal("Match = true, want false")
+		}
+		if uint256Slot := output.Slots[1]; !uint256Slot.Match || uint256Slot.Decoded != "7" {
+			t.Errorf("getUint256 slot = %+v, want a match on 7", uint256Slot)
+		}
+		if bytesSlot := output.Slots[3]; bytesSlot.Match || bytesSlot.Error == "" {
+			t.Errorf("getBytes slot = %+v, want an invalid encoding", bytesSlot)
+		}
+	})
+}
+
+func TestOfflineSigningOnSimulatedChain(t *testing.T) {
+	ctx := context.Background()
+	deployer := account.GetDeployerAddressFromPrivateKey(testPrivateKey)
+	simulatedClient := client.ConnectSimulatedClient(deployer, "")
+	t.Cleanup(func() { simulatedClient.(io.Closer).Close() })
+	auth := GetSigner(ctx, simulatedClient, deployer, testPrivateKey)
+	deployed := client.DeployContract(ctx, auth, simulatedClient, 0)
+
+	values := config.Values{Uint256: big.NewInt(42), Bytes32: "Test", Bytes: "Hello"}
+	unsigned := PrepareSetterTransactions(ctx, values, deployed.Hex(), deployer, simulatedClient, 3000000)
+	if len(unsigned.Transactions) != 3 || unsigned.Transactions[0].Nonce != 1 || unsigned.Transactions[2].Nonce != 3 {
+		t.Fatalf("prepared transactions = %+v, want 3 with the nonces 1 to 3", unsigned.Transactions)
+	}
+	for _, prepared := range unsigned.Transactions {
+		if prepared.Simulation != "success" {
+			t.Errorf("%s simulation = %s, want success", prepared.Step, prepared.Simulation)
+		}
+	}
+
+	signed := SignTransactions(unsigned, testPrivateKey)
+	broadcast := BroadcastTransactions(ctx, signed, simulatedClient, 0)
+	for i, transaction := range broadcast.Transactions {
+		if transaction.AlreadyMined || transaction.TransactionHash != signed.Transactions[i].TransactionHash {
+			t.Errorf("broadcast transaction %d = %+v, want %s sent by this broadcast", i, transaction, signed.Transactions[i].TransactionHash)
+		}
+	}
+	contract := client.AttachToContract(ctx, deployed.Hex(), simulatedClient)
+	got := ReadGetterSetterContract(ctx, contract, deployed.Hex(), deployer)
+	if got.UintValue.Cmp(values.Uint256) != 0 || string(got.BytesValue) != values.Bytes {
+		t.Errorf("read values = (%v, %q), want (%v, %q)", got.UintValue, got.BytesValue, values.Uint256, values.Bytes)
+	}
+
+	// Broadcasting again sends nothing
+	for _, transaction := range BroadcastTransactions(ctx, signed, simulatedClient, 0).Transactions {
+		if !transaction.AlreadyMined {
+			t.Errorf("%s was sent again", transaction.Step)
+		}
+	}
+}
+
+func TestProposeSafeTransactionsOnSimulatedChain(t *testing.T) {
+	ctx := context.Background()
+	const contractAddress = "0x5FbDB2315678afecb367f032d93F642f64180aa3"
+	safeAddress := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	simulatedClient := backend.NewSimulatedClient(gethtypes.GenesisAlloc{
+		// answers nonce() with 5
+		safeAddress: {Code: common.FromHex("0x600560005260206000f3")},
+	})
+	t.Cleanup(func() { simulatedClient.Close() })
+	configuredNonce := uint64(9)
+
+	tests := []struct {
+		name          string
+		values        config.Values
+		safeConfig    config.Safe
+		wantOperation uint8
+		wantTo        string
+		wantNonce     uint64
+	}{
+		{name: "single setter without Safe", values: config.Values{Uint256: big.NewInt(42)}, wantTo: contractAddress},
+		{name: "single setter with the Safe nonce", values: config.Values{Uint256: big.NewInt(42)}, safeConfig: config.Safe{Address: safeAddress.Hex()}, wantTo: contractAddress, wantNonce: 5},
+		{
+			name:          "batched setters with a configured nonce",
+			values:        config.Values{Uint256: big.NewInt(42), Bytes32: "Test", Bytes: "Hello"},
+			safeConfig:    config.Safe{Address: safeAddress.Hex(), Nonce: &configuredNonce},
+			wantOperation: uint8(safe.DelegateCall),
+			wantTo:        safe.MultiSendCallOnlyAddress.Hex(),
+			wantNonce:     configuredNonce,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builderFile, proposal := ProposeSafeTransactions(ctx, tt.values, contractAddress, tt.safeConfig, common.Address{}, simulatedClient)
+			if len(builderFile.Transactions) != len(proposal.Setters) || builderFile.ChainId != "1337" {
+				t.Errorf("builder file = %+v, want a transaction per setter on chain 1337", builderFile)
+			}
+			for _, transaction := range builderFile.Transactions {
+				if transaction.To != contractAddress || transaction.Value != "0" || transaction.Operation != 0 {
+					t.Errorf("builder transaction = %+v, want a CALL to %s", transaction, contractAddress)
+				}
+			}
+			if proposal.Transaction.Operation != tt.wantOperation || proposal.Transaction.To != tt.wantTo {
+				t.Errorf("executed transaction = %+v, want operation %d to %s", proposal.Transaction, tt.wantOperation, tt.wantTo)
+			}
+			if tt.safeConfig.Address == "" {
+				if proposal.SafeTxHash != "" {
+					t.Errorf("SafeTxHash = %s without Safe.Address", proposal.SafeTxHash)
+				}
+				return
+			}
+			if proposal.Nonce == nil || *proposal.Nonce != tt.wantNonce {
+				t.Fatalf("Nonce = %v, want %d", proposal.Nonce, tt.wantNonce)
+			}
+			executed := safe.Transaction{
+				To:        common.HexToAddress(proposal.Transaction.To),
+				Value:     big.NewInt(0),
+				Data:      common.FromHex(proposal.Transaction.Data),
+				Operation: safe.Operation(proposal.Transaction.Operation),
+			}
+			want, err := safe.TransactionHash(big.NewInt(1337), safeAddress, executed, tt.wantNonce)
+			if err != nil || proposal.SafeTxHash != want.Hex() {
+				t.Errorf("SafeTxHash = %s, want %s (%v)", proposal.SafeTxHash, want.Hex(), err)
+			}
+		})
+	}
+}
+
+func TestSignAndVerifyMessage(t *testing.T) {
+	typedDataPath := filepath.Join(t.TempDir(), "typedData.json")
+	typedData := `{
+		"types": {
+			"EIP712Domain": [{"name": "name", "type": "string"}, {"name": "chainId", "type": "uint256"}],
+			"Login": [{"name": "account", "type": "address"}, {"name": "nonce", "type": "uint256"}]
+		},
+		"primaryType": "Login",
+		"domain": {"name": "GetterSetter", "chainId": 1337},
+		"message": {"account": "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266", "nonce": 1}
+	}`
+	if err := os.WriteFile(typedDataPath, []byte(typedData), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	signer := account.GetDeployerAddressFromPrivateKey(testPrivateKey).Hex()
+	tests := []struct {
+		name         string
+		message      config.Message
+		wantStandard string
+	}{
+		{name: "personal message", message: config.Message{Text: "I control the deployer"}, wantStandard: "EIP-191"},
+		{name: "typed data", message: config.Message{TypedData: typedDataPath}, wantStandard: "EIP-712"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signature := SignMessage(tt.message, testPrivateKey)
+			if signature.Standard != tt.wantStandard || signature.Signer != signer {
+				t.Fatalf("SignMessage() = %+v, want an %s signature by %s", signature, tt.wantStandard, signer)
+			}
+
+			verified := tt.message
+			verified.Signature = signature.Signature
+			verified.Signer = signer
+			verification := VerifyMessage(verified)
+			if verification.Signer != signer || !verification.Match || verification.Hash != signature.Hash {
+				t.Errorf("VerifyMessage() = %+v, want the signer %s", verification, signer)
+			}
+
+			// Another expected signer, and another message with the same signature
+			verified.Signer = "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"
+			if verification := VerifyMessage(verified); verification.Match {
+				t.Errorf("VerifyMessage() matches the expected signer %s, want a mismatch", verified.Signer)
+			}
+			tampered := config.Message{Text: "I don't control the deployer", Signature: signature.Signature}
+			if verification := VerifyMessage(tampered); verification.Signer == signer {
+				t.Errorf("VerifyMessage() of another message recovers %s", signer)
+			}
+		})
+	}
+}
+
+// childProcess is set in the environment of a test run in a child process
+const childProcess = "TEST_CHILD_PROCESS"
+
+// runFailingChild runs the test in a child process, in a temporary directory, and checks that it exits with
+// the code 1, as the application does on a fatal error. It returns the directory the child process ran in,
+// and its output.
+func runFailingChild(t *testing.T, name string, setup func(dir string)) (string, []byte) {
+	executable, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if setup != nil {
+		setup(dir)
+	}
+	cmd := exec.Command(executable, "-test.run=^"+name+"$")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), childProcess+"="+name)
+	output, err := cmd.CombinedOutput()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		t.Fatalf("%s exited with %v, want the exit code 1, output:\n%s", name, err, output)
+	}
+	return dir, output
+}
+
+// readRunReport reads the run report written in the directory
+func readRunReport(t *testing.T, dir string) types.RunReport {
+	content, err := os.ReadFile(filepath.Join(dir, "output", "runReport.json"))
+	if err != nil {
+		t.Fatalf("the run report is not written: %v", err)
+	}
+	var report types.RunReport
+	if err := json.Unmarshal(content, &report); err != nil {
+		t.Fatal(err)
+	}
+	return report
+}
+
+// The setter exits the process on a revert, so it runs in a child process
+func TestReportsWrittenWhenASetterReverts(t *testing.T) {
+	if os.Getenv(childProcess) == t.Name() {
+		runRevertedSetter(t)
+		return
+	}
+	dir, _ := runFailingChild(t, t.Name(), nil)
+
+	report := readRunReport(t, dir)
+	if len(report.Reverts) != 1 || report.Reverts[0].Call != "setUint256" || report.Reverts[0].Reason != `Error("not the owner")` {
+		t.Errorf("Reverts = %+v, want setUint256 reverted with Error(\"not the owner\")", report.Reverts)
+	}
+
+	// The setter traced before the revert is not lost
+	content, err := os.ReadFile(filepath.Join(dir, "output", "traces.json"))
+	if err != nil {
+		t.Fatalf("the traces are not written: %v", err)
+	}
+	var written []types.TransactionTrace
+	if err := json.Unmarshal(content, &written); err != nil {
+		t.Fatal(err)
+	}
+	if len(written) != 1 || written[0].Step != "setBytes32" {
+		t.Errorf("traces = %+v, want the trace of setBytes32", written)
+	}
+}
+
+// newRevertingClient starts a simulated chain, funding the account, with a contract reverting with
+// Error("not the owner") whatever the call
+func newRevertingClient(t *testing.T, funded common.Address) (*backend.SimulatedClient, common.Address) {
+	stringType, err := abi.NewType("string", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reason, err := abi.Arguments{{Type: stringType}}.Pack("not the owner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	revertData := append(crypto.Keccak256([]byte("Error(string)"))[:4], reason...)
+	// CODECOPY the revert data after these 12 bytes to memory, then REVERT with it
+	code := append([]byte{0x60, byte(len(revertData)), 0x60, 0x0c, 0x60, 0x00, 0x39, 0x60, byte(len(revertData)), 0x60, 0x00, 0xfd}, revertData...)
+
+	reverting := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	simulatedClient := backend.NewSimulatedClient(gethtypes.GenesisAlloc{
+		funded:    {Balance: big.NewInt(1e18)},
+		reverting: {Code: code},
+	})
+	t.Cleanup(func() { simulatedClient.Close() })
+	return simulatedClient, reverting
+}
+
+// runRevertedSetter sends setUint256 to a contract reverting with Error("not the owner"), after setBytes32 was
+// traced, with the run report and the traces registered as Run does
+func runRevertedSetter(t *testing.T) {
+	ctx := context.Background()
+	deployer := account.GetDeployerAddressFromPrivateKey(testPrivateKey)
+	simulatedClient, reverting := newRevertingClient(t, deployer)
+	ethClient, mode, gasLimit = simulatedClient, utils.CALL_MODE, 100000
+	utils.AtExit(writeRunReport)
+	utils.AtExit(writeTraces)
+	traces = []types.TransactionTrace{{Step: "setBytes32"}}
+
+	contract, err := getter_setter.NewGetterSetter(reverting, simulatedClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := GetSigner(ctx, simulatedClient, deployer, testPrivateKey)
+	SetUintInGetterSetterContract(ctx, contract, auth, SetGetterSetterDTO(config.Values{Uint256: big.NewInt(42)}), simulatedClient, 0)
+}
+
+func TestRunReportWrittenWhenNoEndpointAnswers(t *testing.T) {
+	if os.Getenv(childProcess) == t.Name() {
+		Run(context.Background(), Options{})
+		return
+	}
+	// Nothing listens on port 1, the connection is refused
+	dir, _ := runFailingChild(t, t.Name(), func(dir string) {
+		configuration := `
+[RPC]
+URL = "http://127.0.0.1:1"
+Urls = ["http://127.0.0.1:1/fallback"]
+Retries = 1
+[Account]
+Key = "` + testPrivateKey + `"
+[Contract]
+Mode = "deploy-contract"
+[Contract.Values]
+uint256 = 7
+`
+		if err := os.WriteFile(filepath.Join(dir, "config.toml"), []byte(configuration), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	report := readRunReport(t, dir)
+	if len(report.RpcRequests) != 1 || report.RpcRequests[0].Method != "eth_chainId" || report.RpcRequests[0].Attempts != 2 || report.RpcRequests[0].Error == "" {
+		t.Errorf("RpcRequests = %+v, want eth_chainId failed on both endpoints", report.RpcRequests)
+	}
+}
+
+// A step which failed in the previous run stops the resumed run too, whether it was mined or only sent then
+func TestResumeRevertedStep(t *testing.T) {
+	for _, status := range []string{journal.StatusMined, journal.StatusSent} {
+		t.Run(status, func(t *testing.T) {
+			if os.Getenv(childProcess) == t.Name() {
+				resumeRevertedStep(t, status)
+				return
+			}
+			_, output := runFailingChild(t, t.Name(), nil)
+			if !strings.Contains(string(output), `Failed to resume setUint256: setUint256 transaction`) || !strings.Contains(string(output), `revert reason: Error("not the owner")`) {
+				t.Errorf("output:\n%s\nwant the resumed setUint256 to fail with its revert reason", output)
+			}
+		})
+	}
+}
+
+// resumeRevertedStep resumes setUint256, journaled with the status by the previous run, whose transaction reverted
+func resumeRevertedStep(t *testing.T, status string) {
+	ctx := context.Background()
+	deployer := account.GetDeployerAddressFromPrivateKey(testPrivateKey)
+	simulatedClient, reverting := newRevertingClient(t, deployer)
+	ethClient = simulatedClient
+	contract, err := getter_setter.NewGetterSetter(reverting, simulatedClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := GetSigner(ctx, simulatedClient, deployer, testPrivateKey)
+	auth.GasLimit = 100000
+	transaction, err := contract.SetUint256(auth, big.NewInt(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	receipt, err := bind.WaitMined(ctx, simulatedClient, transaction)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runJournal = journal.New(filepath.Join(t.TempDir(), "journal.json"), utils.DEMO_MODE, 1337, deployer, journal.Inputs{})
+	if err := runJournal.Sent("setUint256", transaction); err != nil {
+		t.Fatal(err)
+	}
+	if status == journal.StatusMined {
+		if err := runJournal.Mined("setUint256", receipt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	journaledStep(ctx, "setUint256", GetSigner(ctx, simulatedClient, deployer, testPrivateKey), func(auth *bind.TransactOpts) {
+		log.Println("setUint256 is run again")
+	})
+}
+
+// A step is recorded as sent only if the node accepted its transaction, when the run stops before it is mined
+func TestJournalRecordsAcceptedTransactions(t *testing.T) {
+	tests := []struct {
+		name     string
+		rejected bool
+	}{
+		{name: "rejected", rejected: true},
+		{name: "accepted"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if os.Getenv(childProcess) == t.Name() {
+				stopInFlightStep(t, tt.rejected)
+				return
+			}
+			dir, output := runFailingChild(t, t.Name(), nil)
+			previous, err := journal.Load(filepath.Join(dir, "journal.json"))
+			if err != nil {
+				t.Fatalf("%v, output:\n%s", err, output)
+			}
+			if step, ok := previous.Step("deploy"); !ok || step.Status != journal.StatusMined {
+				t.Errorf("deploy step = %+v, want it mined", step)
+			}
+			step, ok := previous.Step("setUint256")
+			if tt.rejected && ok {
+				t.Errorf("setUint256 step = %+v, want the rejected transaction not recorded", step)
+			}
+			if !tt.rejected && (!ok || step.Status != journal.StatusSent || step.RawTransaction == "") {
+				t.Errorf("setUint256 step = %+v, want the accepted transaction recorded as sent", step)
+			}
+		})
+	}
+}
+
+// The step is journaled as sent as soon as the node accepted its transaction, before waiting for it to be mined
+func TestJournalingClientRecordsSentStep(t *testing.T) {
+	ctx := context.Background()
+	deployer := account.GetDeployerAddressFromPrivateKey(testPrivateKey)
+	simulatedClient := client.ConnectSimulatedClient(deployer, "")
+	t.Cleanup(func() { simulatedClient.(io.Closer).Close() })
+	ethClient = journalingClient{Client: simulatedClient}
+	runJournal = journal.New(filepath.Join(t.TempDir(), "journal.json"), utils.DEMO_MODE, 1337, deployer, journal.Inputs{})
+	t.Cleanup(func() { ethClient, runJournal = nil, nil })
+
+	deployed := DeployGetterSetterContract(ctx, GetSigner(ctx, ethClient, deployer, testPrivateKey), ethClient, 0)
+	contract := client.AttachToContract(ctx, deployed.Hex(), ethClient)
+	receipt, _ := journaledStep(ctx, "setUint256", GetSigner(ctx, ethClient, deployer, testPrivateKey), func(auth *bind.TransactOpts) {
+		transaction, err := contract.SetUint256(auth, big.NewInt(42))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if step, ok := runJournal.Step("setUint256"); !ok || step.Status != journal.StatusSent || step.TransactionHash != transaction.Hash().Hex() {
+			t.Errorf("setUint256 step = %+v, want it sent once the node accepted %s", step, transaction.Hash().Hex())
+		}
+	})
+	if step, _ := runJournal.Step("setUint256"); step.Status != journal.StatusMined || step.TransactionHash != receipt.TxHash.Hex() || step.RawTransaction == "" {
+		t.Errorf("setUint256 step = %+v, want it mined", step)
+	}
+	if _, ok := backend.Unwrap(ethClient).(*backend.SimulatedClient); !ok {
+		t.Errorf("Unwrap() = %T, want the simulated client", backend.Unwrap(ethClient))
+	}
+}
+
+// stopInFlightStep deploys the GetterSetter, then stops the run while sending setUint256: the node rejects
+// its transaction, or accepts it and the run stops before it is mined
+func stopInFlightStep(t *testing.T, rejected bool) {
+	ctx := context.Background()
+	deployer := account.GetDeployerAddressFromPrivateKey(testPrivateKey)
+	ethClient = journalingClient{Client: client.ConnectSimulatedClient(deployer, "")}
+	runJournal = journal.New("journal.json", utils.DEMO_MODE, 1337, deployer, journal.Inputs{})
+	utils.AtExit(recordInFlightStep)
+
+	deployed := DeployGetterSetterContract(ctx, GetSigner(ctx, ethClient, deployer, testPrivateKey), ethClient, 0)
+	contract := client.AttachToContract(ctx, deployed.Hex(), ethClient)
+	auth := GetSigner(ctx, ethClient, deployer, testPrivateKey)
+	if rejected {
+		// The nonce of the deployment, the node rejects it as too low
+		auth.Nonce = big.NewInt(0)
+	}
+	journaledStep(ctx, "setUint256", auth, func(auth *bind.TransactOpts) {
+		if _, err := contract.SetUint256(auth, big.NewInt(42)); err != nil {
+			utils.Fatalf("Failed to set uint256: %v", err)
+		}
+		utils.Fatal("Cancelled waiting for transaction status by timeout")
+	})
+}
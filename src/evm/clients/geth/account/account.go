@@ -5,8 +5,9 @@ import (
 	"crypto/ecdsa"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"log"
+	"main/src/evm/clients/geth/backend"
+	"main/src/evm/clients/geth/transactions"
 	"main/src/utils"
 	"math/big"
 )
@@ -21,7 +22,7 @@ func GetDeployerAddressFromPrivateKey(privateKey string) common.Address {
 func PrivateToECDSA(privateKey string) *ecdsa.PrivateKey {
 	privateKeyECDSA, err := crypto.HexToECDSA(privateKey)
 	if err != nil {
-		log.Fatal("Error converting private key to ECDSA: ", err)
+		utils.Fatal("Error converting private key to ECDSA: ", err)
 	}
 	return privateKeyECDSA
 }
@@ -30,7 +31,7 @@ func GetEOAPublicKey(privateKeyECDSA *ecdsa.PrivateKey) *ecdsa.PublicKey {
 	publicKey := privateKeyECDSA.Public()
 	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
 	if !ok {
-		log.Fatal("error casting public key to ECDSA")
+		utils.Fatal("error casting public key to ECDSA")
 	}
 	log.Println("Public key obtained")
 	return publicKeyECDSA
@@ -42,20 +43,46 @@ func GetOwnerAddress(publicKeyECDSA *ecdsa.PublicKey) common.Address {
 	return deployerAddress
 }
 
-// ValidateBalanceFunded Checks if the account has a balance greater than 0
-// If not, it throws an error
-func ValidateBalanceFunded(deployerAddress common.Address, client *ethclient.Client) {
-	account := common.HexToAddress(deployerAddress.String())
-	balanceInWei, err := client.BalanceAt(context.Background(), account, nil)
+// ValidateBalanceCoversCost checks if the account balance covers the estimated cost of the run.
+// If not, it throws an error with the shortfall. If the balance covers the cost, but not the cost
+// increased by the safety margin, it only warns, as gas prices may rise during the run.
+//
+// Parameters:
+// - ctx: the context of the run (context.Context)
+// - deployerAddress: the address of the account (common.Address)
+// - client: the Ethereum client (backend.Client)
+// - estimatedCost: the estimated cost of the run, in wei (*big.Int)
+// - safetyMargin: the safety margin, in percent of the estimated cost (int)
+func ValidateBalanceCoversCost(ctx context.Context, deployerAddress common.Address, client backend.Client, estimatedCost *big.Int, safetyMargin int) {
+	balanceInWei, err := client.BalanceAt(ctx, deployerAddress, nil)
 	if err != nil {
-		log.Fatal("Could not get balance:", err)
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatal("Could not get balance:", err)
 	}
-
 	if isUnfunded(balanceInWei) {
-		log.Fatalf("Balance of the account %s is 0. Fund the account and try again.", deployerAddress)
+		utils.Fatalf("Balance of the account %s is 0. Fund the account and try again.", deployerAddress)
 	}
-
 	log.Println("Current balance in ETH:", utils.WeiToEther(balanceInWei))
+
+	if missing := shortfall(balanceInWei, estimatedCost); missing.Sign() > 0 {
+		utils.Fatalf("Balance of the account %s (%s ETH) does not cover the estimated cost of the run (%s ETH). Shortfall: %s ETH. Fund the account and try again.",
+			deployerAddress, utils.WeiToEther(balanceInWei).String(), utils.WeiToEther(estimatedCost).String(), utils.WeiToEther(missing).String())
+	}
+	costWithMargin := new(big.Int).Mul(estimatedCost, big.NewInt(int64(100+safetyMargin)))
+	costWithMargin.Div(costWithMargin, big.NewInt(100))
+	if missing := shortfall(balanceInWei, costWithMargin); missing.Sign() > 0 {
+		log.Printf("WARNING: Balance of the account %s is below the estimated cost of the run increased by the %d%% safety margin (%s ETH). Consider funding it with %s ETH more.",
+			deployerAddress, safetyMargin, utils.WeiToEther(costWithMargin).String(), utils.WeiToEther(missing).String())
+	}
+}
+
+// shortfall returns how much the balance lacks to cover the cost, 0 if it covers it
+func shortfall(balance *big.Int, cost *big.Int) *big.Int {
+	missing := new(big.Int).Sub(cost, balance)
+	if missing.Sign() < 0 {
+		return big.NewInt(0)
+	}
+	return missing
 }
 
 func isUnfunded(balance *big.Int) bool {
@@ -0,0 +1,75 @@
+package account
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestIsUnfunded(t *testing.T) {
+	tests := []struct {
+		name    string
+		balance *big.Int
+		want    bool
+	}{
+		{name: "zero balance", balance: big.NewInt(0), want: true},
+		{name: "one wei", balance: big.NewInt(1), want: false},
+		{name: "one ether", balance: new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isUnfunded(tt.balance); got != tt.want {
+				t.Errorf("isUnfunded(%v) = %v, want %v", tt.balance, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetDeployerAddressFromPrivateKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		privateKey string
+		want       string
+	}{
+		{
+			name:       "first development account",
+			privateKey: "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80",
+			want:       "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266",
+		},
+		{
+			name:       "second development account",
+			privateKey: "59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d",
+			want:       "0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetDeployerAddressFromPrivateKey(tt.privateKey); got.Hex() != tt.want {
+				t.Errorf("GetDeployerAddressFromPrivateKey() = %s, want %s", got.Hex(), tt.want)
+			}
+		})
+	}
+}
+
+func TestShortfall(t *testing.T) {
+	tests := []struct {
+		name    string
+		balance int64
+		cost    int64
+		want    int64
+	}{
+		{name: "balance covers the cost", balance: 100, cost: 60, want: 0},
+		{name: "balance equals the cost", balance: 100, cost: 100, want: 0},
+		{name: "balance below the cost", balance: 1, cost: 100, want: 99},
+		{name: "nothing to pay", balance: 1, cost: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shortfall(big.NewInt(tt.balance), big.NewInt(tt.cost)); got.Int64() != tt.want {
+				t.Errorf("shortfall(%d, %d) = %v, want %d", tt.balance, tt.cost, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package account
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39"
+	"math/big"
+)
+
+// hardened is the offset of the BIP-32 hardened child indexes
+const hardened = uint32(0x80000000)
+
+// basePath is the BIP-44 path of the Ethereum accounts, m/44'/60'/0'/0, the account index is appended to it
+var basePath = []uint32{hardened + 44, hardened + 60, hardened + 0, 0}
+
+// DerivationPath returns the BIP-44 path of the account with the given index: m/44'/60'/0'/0/index
+func DerivationPath(index int) string {
+	return fmt.Sprintf("m/44'/60'/0'/0/%d", index)
+}
+
+// DerivePrivateKeys derives the private keys of the first accounts of a BIP-39 mnemonic, along the
+// BIP-44 path m/44'/60'/0'/0/i, as MetaMask, Hardhat and Anvil do.
+//
+// Parameters:
+// - mnemonic: the BIP-39 mnemonic (string)
+// - passphrase: the BIP-39 passphrase, usually empty (string)
+// - count: the number of accounts to derive (int)
+// Returns:
+// - []*ecdsa.PrivateKey: the private key of each account, by index
+// - error if the mnemonic is invalid
+func DerivePrivateKeys(mnemonic string, passphrase string, count int) ([]*ecdsa.PrivateKey, error) {
+	if !bip39.IsMnemonicValid(mnemonic) {
+		// The mnemonic itself must not end up in the logs
+		return nil, errors.New("the mnemonic is not a valid BIP-39 mnemonic (English word list, 12 to 24 words, with a valid checksum)")
+	}
+	seed := bip39.NewSeed(mnemonic, passphrase)
+	master := hmacSHA512([]byte("Bitcoin seed"), seed)
+	key, chainCode := master[:32], master[32:]
+	if _, err := crypto.ToECDSA(key); err != nil {
+		return nil, fmt.Errorf("the mnemonic gives an invalid master key: %w", err)
+	}
+
+	var err error
+	for _, index := range basePath {
+		if key, chainCode, err = deriveChild(key, chainCode, index); err != nil {
+			return nil, err
+		}
+	}
+	privateKeys := make([]*ecdsa.PrivateKey, count)
+	for i := range privateKeys {
+		childKey, _, err := deriveChild(key, chainCode, uint32(i))
+		if err != nil {
+			return nil, err
+		}
+		if privateKeys[i], err = crypto.ToECDSA(childKey); err != nil {
+			return nil, fmt.Errorf("invalid key at %s: %w", DerivationPath(i), err)
+		}
+	}
+	return privateKeys, nil
+}
+
+// deriveChild derives the BIP-32 child private key and chain code with the given index
+func deriveChild(key []byte, chainCode []byte, index uint32) ([]byte, []byte, error) {
+	var data []byte
+	if index >= hardened {
+		data = append([]byte{0}, key...)
+	} else {
+		privateKey, err := crypto.ToECDSA(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		data = crypto.CompressPubkey(&privateKey.PublicKey)
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+	sum := hmacSHA512(chainCode, data)
+
+	// The child key is (IL + parent key) mod n. BIP-32 skips the index when IL >= n or the key is 0,
+	// which happens with a probability lower than 1 in 2^127
+	n := crypto.S256().Params().N
+	il := new(big.Int).SetBytes(sum[:32])
+	if il.Cmp(n) >= 0 {
+		return nil, nil, fmt.Errorf("the child key %d is invalid", index)
+	}
+	child := il.Add(il, new(big.Int).SetBytes(key))
+	child.Mod(child, n)
+	if child.Sign() == 0 {
+		return nil, nil, fmt.Errorf("the child key %d is invalid", index)
+	}
+	return math.PaddedBigBytes(child, 32), sum[32:], nil
+}
+
+func hmacSHA512(key []byte, data []byte) []byte {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,52 @@
+package account
+
+import (
+	"github.com/ethereum/go-ethereum/crypto"
+	"testing"
+)
+
+const testMnemonic = "test test test test test test test test test test test junk"
+
+func TestDerivePrivateKeys(t *testing.T) {
+	// The development accounts of Hardhat and Anvil
+	want := []string{
+		"0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266",
+		"0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+		"0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC",
+	}
+	privateKeys, err := DerivePrivateKeys(testMnemonic, "", len(want))
+	if err != nil {
+		t.Fatalf("DerivePrivateKeys() error = %v", err)
+	}
+	for i, privateKey := range privateKeys {
+		if got := crypto.PubkeyToAddress(privateKey.PublicKey).Hex(); got != want[i] {
+			t.Errorf("account %s = %s, want %s", DerivationPath(i), got, want[i])
+		}
+	}
+}
+
+func TestDerivePrivateKeysErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		mnemonic   string
+		passphrase string
+		wantErr    bool
+	}{
+		{name: "with a passphrase", mnemonic: testMnemonic, passphrase: "secret"},
+		{name: "bad checksum", mnemonic: "test test test test test test test test test test test test", wantErr: true},
+		{name: "unknown word", mnemonic: "test test test test test test test test test test test junkk", wantErr: true},
+		{name: "empty", mnemonic: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			privateKeys, err := DerivePrivateKeys(tt.mnemonic, tt.passphrase, 1)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DerivePrivateKeys() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			// A passphrase gives other accounts
+			if !tt.wantErr && crypto.PubkeyToAddress(privateKeys[0].PublicKey).Hex() == "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266" {
+				t.Error("the passphrase was ignored")
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package artifacts
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ParseArguments converts string values into the Go types expected by the ABI arguments,
+// so they can be passed to abi.Pack, bind.DeployContract or BoundContract.Transact/Call.
+//
+// Value formats:
+// - int/uint: decimal or 0x-prefixed hex ("42", "0x2a")
+// - bool: "true" / "false"
+// - address: 0x-prefixed hex address
+// - bytes/bytesN: 0x-prefixed hex, any other value is taken as UTF-8 text (bytesN is left-aligned)
+// - string: taken as is
+// - arrays, slices and tuples: JSON array of the elements, e.g. `["1", "2"]` or `[["0xab", true]]`
+//
+// Parameters:
+// - arguments: the ABI arguments of a constructor or a method (abi.Arguments)
+// - values: the string values, one per argument (string slice)
+// Returns:
+// - the parsed values, in the order of the arguments
+// - error if the number of values doesn't match or a value can't be parsed
+func ParseArguments(arguments abi.Arguments, values []string) ([]interface{}, error) {
+	if len(arguments) != len(values) {
+		return nil, fmt.Errorf("expected %d argument(s), got %d", len(arguments), len(values))
+	}
+	parsed := make([]interface{}, len(arguments))
+	for i, argument := range arguments {
+		value, err := parseValue(argument.Type, values[i])
+		if err != nil {
+			name := argument.Name
+			if name == "" {
+				name = strconv.Itoa(i)
+			}
+			return nil, fmt.Errorf("argument %s (%s): %w", name, argument.Type.String(), err)
+		}
+		parsed[i] = value.Interface()
+	}
+	return parsed, nil
+}
+
+func parseValue(abiType abi.Type, value string) (reflect.Value, error) {
+	goType := abiType.GetType()
+	switch abiType.T {
+	case abi.IntTy, abi.UintTy:
+		return parseInteger(abiType, goType, value)
+	case abi.BoolTy:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid bool %q", value)
+		}
+		return reflect.ValueOf(parsed), nil
+	case abi.StringTy:
+		return reflect.ValueOf(value), nil
+	case abi.AddressTy:
+		if !common.IsHexAddress(value) {
+			return reflect.Value{}, fmt.Errorf("invalid address %q", value)
+		}
+		return reflect.ValueOf(common.HexToAddress(value)), nil
+	case abi.BytesTy:
+		parsed, err := parseBytes(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(parsed), nil
+	case abi.FixedBytesTy:
+		parsed, err := parseBytes(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if len(parsed) > abiType.Size {
+			return reflect.Value{}, fmt.Errorf("%d bytes do not fit into bytes%d", len(parsed), abiType.Size)
+		}
+		fixed := reflect.New(goType).Elem()
+		reflect.Copy(fixed, reflect.ValueOf(parsed))
+		return fixed, nil
+	case abi.SliceTy, abi.ArrayTy:
+		elements, err := splitJSONArray(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if abiType.T == abi.ArrayTy && len(elements) != abiType.Size {
+			return reflect.Value{}, fmt.Errorf("expected %d elements, got %d", abiType.Size, len(elements))
+		}
+		var list reflect.Value
+		if abiType.T == abi.ArrayTy {
+			list = reflect.New(goType).Elem()
+		} else {
+			list = reflect.MakeSlice(goType, len(elements), len(elements))
+		}
+		for i, element := range elements {
+			parsed, err := parseValue(*abiType.Elem, element)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("element %d: %w", i, err)
+			}
+			list.Index(i).Set(parsed)
+		}
+		return list, nil
+	case abi.TupleTy:
+		elements, err := splitJSONArray(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if len(elements) != len(abiType.TupleElems) {
+			return reflect.Value{}, fmt.Errorf("expected %d tuple fields, got %d", len(abiType.TupleElems), len(elements))
+		}
+		tuple := reflect.New(goType).Elem()
+		for i, element := range elements {
+			parsed, err := parseValue(*abiType.TupleElems[i], element)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("field %s: %w", abiType.TupleRawNames[i], err)
+			}
+			tuple.Field(i).Set(parsed)
+		}
+		return tuple, nil
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported ABI type %s", abiType.String())
+	}
+}
+
+// parseInteger parses a decimal or hex integer into either a sized Go integer or *big.Int,
+// depending on what the ABI packer expects for the type size.
+func parseInteger(abiType abi.Type, goType reflect.Type, value string) (reflect.Value, error) {
+	parsed, ok := new(big.Int).SetString(value, 0)
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("invalid integer %q", value)
+	}
+	// uintN: [0, 2^N - 1], intN: [-2^(N-1), 2^(N-1) - 1]
+	minimum, maximum := big.NewInt(0), new(big.Int).Lsh(big.NewInt(1), uint(abiType.Size))
+	if abiType.T == abi.IntTy {
+		maximum.Rsh(maximum, 1)
+		minimum.Neg(maximum)
+	}
+	maximum.Sub(maximum, big.NewInt(1))
+	if parsed.Cmp(minimum) < 0 || parsed.Cmp(maximum) > 0 {
+		return reflect.Value{}, fmt.Errorf("value %s is out of range for %s", parsed, abiType.String())
+	}
+
+	switch goType.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return reflect.ValueOf(parsed.Int64()).Convert(goType), nil
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return reflect.ValueOf(parsed.Uint64()).Convert(goType), nil
+	default:
+		return reflect.ValueOf(parsed), nil
+	}
+}
+
+func parseBytes(value string) ([]byte, error) {
+	if strings.HasPrefix(value, "0x") || strings.HasPrefix(value, "0X") {
+		parsed, err := hexutil.Decode("0x" + value[2:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex value %q: %w", value, err)
+		}
+		return parsed, nil
+	}
+	return []byte(value), nil
+}
+
+// splitJSONArray splits a JSON array into its elements as plain strings:
+// JSON strings are unquoted, numbers, booleans and nested arrays are kept as raw JSON.
+func splitJSONArray(value string) ([]string, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal([]byte(value), &raw); err != nil {
+		return nil, fmt.Errorf("expected a JSON array, got %q", value)
+	}
+	elements := make([]string, len(raw))
+	for i, element := range raw {
+		var unquoted string
+		if err := json.Unmarshal(element, &unquoted); err == nil {
+			elements[i] = unquoted
+		} else {
+			elements[i] = string(element)
+		}
+	}
+	return elements, nil
+}
@@ -0,0 +1,95 @@
+package artifacts
+
+import (
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const argumentsABI = `[{"type":"function","name":"all","stateMutability":"nonpayable","outputs":[],"inputs":[
+	{"name":"u256","type":"uint256"},
+	{"name":"i64","type":"int64"},
+	{"name":"u8","type":"uint8"},
+	{"name":"flag","type":"bool"},
+	{"name":"owner","type":"address"},
+	{"name":"b32","type":"bytes32"},
+	{"name":"data","type":"bytes"},
+	{"name":"text","type":"string"},
+	{"name":"list","type":"uint256[]"},
+	{"name":"pair","type":"address[2]"},
+	{"name":"point","type":"tuple","components":[{"name":"x","type":"uint256"},{"name":"ok","type":"bool"}]}
+]}]`
+
+func TestParseArguments(t *testing.T) {
+	parsedABI, err := abi.JSON(strings.NewReader(argumentsABI))
+	if err != nil {
+		t.Fatalf("invalid test ABI: %v", err)
+	}
+	inputs := parsedABI.Methods["all"].Inputs
+	owner := "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"
+
+	valid := []string{"42", "-7", "0xff", "true", owner, "Test", "0x0102", "hello", `["1", 2]`, `["` + owner + `", "` + owner + `"]`, `[5, true]`}
+	got, err := ParseArguments(inputs, valid)
+	if err != nil {
+		t.Fatalf("ParseArguments() unexpected error: %v", err)
+	}
+	if _, err := inputs.Pack(got...); err != nil {
+		t.Fatalf("parsed arguments could not be packed: %v", err)
+	}
+	wantBytes32 := [32]byte{'T', 'e', 's', 't'}
+	expectations := []interface{}{
+		big.NewInt(42),
+		int64(-7),
+		uint8(255),
+		true,
+		common.HexToAddress(owner),
+		wantBytes32,
+		[]byte{1, 2},
+		"hello",
+		[]*big.Int{big.NewInt(1), big.NewInt(2)},
+		[2]common.Address{common.HexToAddress(owner), common.HexToAddress(owner)},
+	}
+	for i, want := range expectations {
+		if !reflect.DeepEqual(got[i], want) {
+			t.Errorf("argument %d = %#v, want %#v", i, got[i], want)
+		}
+	}
+	point := reflect.ValueOf(got[10])
+	if point.Field(0).Interface().(*big.Int).Cmp(big.NewInt(5)) != 0 || !point.Field(1).Bool() {
+		t.Errorf("tuple argument = %#v, want {5, true}", got[10])
+	}
+
+	invalid := []struct {
+		name  string
+		index int
+		value string
+	}{
+		{name: "not a number", index: 0, value: "forty-two"},
+		{name: "negative unsigned", index: 0, value: "-1"},
+		{name: "uint8 overflow", index: 2, value: "256"},
+		{name: "int64 overflow", index: 1, value: "9223372036854775808"},
+		{name: "not a bool", index: 3, value: "yes"},
+		{name: "invalid address", index: 4, value: "0x1234"},
+		{name: "bytes32 too long", index: 5, value: "0x" + strings.Repeat("ab", 33)},
+		{name: "invalid hex bytes", index: 6, value: "0xzz"},
+		{name: "slice is not JSON", index: 8, value: "1,2"},
+		{name: "wrong array length", index: 9, value: `["` + owner + `"]`},
+		{name: "wrong tuple length", index: 10, value: `[5]`},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			values := append([]string(nil), valid...)
+			values[tt.index] = tt.value
+			if _, err := ParseArguments(inputs, values); err == nil {
+				t.Errorf("ParseArguments() with %q expected an error", tt.value)
+			}
+		})
+	}
+
+	if _, err := ParseArguments(inputs, valid[:3]); err == nil {
+		t.Error("ParseArguments() with a missing argument expected an error")
+	}
+}
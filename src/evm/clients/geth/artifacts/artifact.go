@@ -0,0 +1,123 @@
+package artifacts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"os"
+	"strings"
+)
+
+// Artifact holds everything needed to deploy and interact with a compiled contract
+type Artifact struct {
+	ABI      abi.ABI
+	Bytecode []byte // creation (init) code, empty if only the ABI was loaded
+}
+
+// jsonArtifact covers both Foundry (bytecode.object) and Hardhat (bytecode as a string) JSON artifacts
+type jsonArtifact struct {
+	ABI      json.RawMessage `json:"abi"`
+	Bytecode json.RawMessage `json:"bytecode"`
+}
+
+type foundryBytecode struct {
+	Object string `json:"object"`
+}
+
+// LoadArtifact loads the ABI and the bytecode from a Foundry or Hardhat JSON artifact.
+//
+// Parameters:
+// - path: path to the JSON artifact (string)
+// Returns:
+// - Artifact
+// - error if the file can't be read or parsed
+func LoadArtifact(path string) (Artifact, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Artifact{}, fmt.Errorf("could not read the artifact %s: %w", path, err)
+	}
+
+	var parsed jsonArtifact
+	if err := json.Unmarshal(content, &parsed); err != nil {
+		return Artifact{}, fmt.Errorf("could not parse the artifact %s: %w", path, err)
+	}
+	if len(parsed.ABI) == 0 {
+		return Artifact{}, fmt.Errorf("the artifact %s has no \"abi\" field", path)
+	}
+	contractABI, err := abi.JSON(strings.NewReader(string(parsed.ABI)))
+	if err != nil {
+		return Artifact{}, fmt.Errorf("could not parse the ABI of %s: %w", path, err)
+	}
+
+	bytecode, err := parseArtifactBytecode(parsed.Bytecode)
+	if err != nil {
+		return Artifact{}, fmt.Errorf("could not parse the bytecode of %s: %w", path, err)
+	}
+	return Artifact{ABI: contractABI, Bytecode: bytecode}, nil
+}
+
+// LoadAbiAndBin loads the ABI and the bytecode from raw solc outputs (.abi and .bin files).
+// The .bin path is optional, leave it empty to load the ABI only.
+//
+// Parameters:
+// - abiPath: path to the .abi file (string)
+// - binPath: path to the .bin file (string)
+// Returns:
+// - Artifact
+// - error if any of the files can't be read or parsed
+func LoadAbiAndBin(abiPath string, binPath string) (Artifact, error) {
+	abiFile, err := os.Open(abiPath)
+	if err != nil {
+		return Artifact{}, fmt.Errorf("could not read the ABI %s: %w", abiPath, err)
+	}
+	defer abiFile.Close()
+
+	contractABI, err := abi.JSON(abiFile)
+	if err != nil {
+		return Artifact{}, fmt.Errorf("could not parse the ABI %s: %w", abiPath, err)
+	}
+	if binPath == "" {
+		return Artifact{ABI: contractABI}, nil
+	}
+
+	bin, err := os.ReadFile(binPath)
+	if err != nil {
+		return Artifact{}, fmt.Errorf("could not read the bytecode %s: %w", binPath, err)
+	}
+	bytecode, err := decodeBytecode(string(bin))
+	if err != nil {
+		return Artifact{}, fmt.Errorf("could not parse the bytecode %s: %w", binPath, err)
+	}
+	return Artifact{ABI: contractABI, Bytecode: bytecode}, nil
+}
+
+func parseArtifactBytecode(raw json.RawMessage) ([]byte, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var hardhat string
+	if err := json.Unmarshal(raw, &hardhat); err == nil {
+		return decodeBytecode(hardhat)
+	}
+	var foundry foundryBytecode
+	if err := json.Unmarshal(raw, &foundry); err != nil {
+		return nil, errors.New("\"bytecode\" is neither a hex string nor an object with an \"object\" field")
+	}
+	return decodeBytecode(foundry.Object)
+}
+
+func decodeBytecode(bytecode string) ([]byte, error) {
+	bytecode = strings.TrimSpace(bytecode)
+	if strings.Contains(bytecode, "__") {
+		return nil, errors.New("the bytecode contains unlinked library placeholders")
+	}
+	if !strings.HasPrefix(bytecode, "0x") {
+		bytecode = "0x" + bytecode
+	}
+	if bytecode == "0x" {
+		return nil, nil
+	}
+	return hexutil.Decode(bytecode)
+}
@@ -0,0 +1,92 @@
+package artifacts
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const artifactABI = `[{"type":"constructor","inputs":[{"name":"owner","type":"address"}],"stateMutability":"nonpayable"}]`
+
+func writeFile(t *testing.T, name string, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("could not write %s: %v", path, err)
+	}
+	return path
+}
+
+func TestLoadArtifact(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		wantBytecode []byte
+		wantErr      bool
+	}{
+		{
+			name:         "foundry",
+			content:      `{"abi":` + artifactABI + `,"bytecode":{"object":"0x6080604052","linkReferences":{}}}`,
+			wantBytecode: []byte{0x60, 0x80, 0x60, 0x40, 0x52},
+		},
+		{
+			name:         "hardhat",
+			content:      `{"contractName":"Test","abi":` + artifactABI + `,"bytecode":"0x6080604052"}`,
+			wantBytecode: []byte{0x60, 0x80, 0x60, 0x40, 0x52},
+		},
+		{
+			name:    "abi only",
+			content: `{"abi":` + artifactABI + `}`,
+		},
+		{name: "missing abi", content: `{"bytecode":"0x60"}`, wantErr: true},
+		{name: "invalid bytecode", content: `{"abi":[],"bytecode":"0xzz"}`, wantErr: true},
+		{name: "unlinked library", content: `{"abi":[],"bytecode":"0x73__$abc$__"}`, wantErr: true},
+		{name: "not JSON", content: `abi`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			artifact, err := LoadArtifact(writeFile(t, "artifact.json", tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadArtifact() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !bytes.Equal(artifact.Bytecode, tt.wantBytecode) {
+				t.Errorf("Bytecode = %x, want %x", artifact.Bytecode, tt.wantBytecode)
+			}
+			if len(artifact.ABI.Constructor.Inputs) != 1 {
+				t.Errorf("constructor inputs = %d, want 1", len(artifact.ABI.Constructor.Inputs))
+			}
+		})
+	}
+}
+
+func TestLoadAbiAndBin(t *testing.T) {
+	tests := []struct {
+		name         string
+		bin          string
+		wantBytecode []byte
+		wantErr      bool
+	}{
+		{name: "solc output without prefix", bin: "6080604052\n", wantBytecode: []byte{0x60, 0x80, 0x60, 0x40, 0x52}},
+		{name: "prefixed", bin: "0x6080", wantBytecode: []byte{0x60, 0x80}},
+		{name: "empty file", bin: ""},
+		{name: "odd length", bin: "608", wantErr: true},
+	}
+
+	abiPath := writeFile(t, "Test.abi", artifactABI)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			artifact, err := LoadAbiAndBin(abiPath, writeFile(t, "Test.bin", tt.bin))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadAbiAndBin() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !bytes.Equal(artifact.Bytecode, tt.wantBytecode) {
+				t.Errorf("Bytecode = %x, want %x", artifact.Bytecode, tt.wantBytecode)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package artifacts
+
+import (
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+	"reflect"
+	"strconv"
+)
+
+// Event is an emitted log decoded with the contract ABI
+type Event struct {
+	Name    string                 `json:"name"`
+	Address string                 `json:"address"`
+	Args    map[string]interface{} `json:"args,omitempty"`
+	Raw     *types.Log             `json:"raw,omitempty"` // set only when the log doesn't match any ABI event
+}
+
+// DecodeLogs decodes the logs emitted by the contract, usually taken from a transaction receipt.
+// Logs of other contracts are skipped, logs with an unknown signature are returned raw.
+//
+// Parameters:
+// - contractABI: the ABI of the contract (abi.ABI)
+// - contractAddress: only the logs emitted by this address are decoded (common.Address)
+// - logs: the logs to decode ([]*types.Log)
+// Returns:
+// - []Event
+func DecodeLogs(contractABI abi.ABI, contractAddress common.Address, logs []*types.Log) []Event {
+	var events []Event
+	for _, log := range logs {
+		if log.Address != contractAddress {
+			continue
+		}
+		if len(log.Topics) == 0 {
+			events = append(events, Event{Name: "anonymous", Address: log.Address.Hex(), Raw: log})
+			continue
+		}
+		event, err := contractABI.EventByID(log.Topics[0])
+		if err != nil {
+			events = append(events, Event{Name: "unknown", Address: log.Address.Hex(), Raw: log})
+			continue
+		}
+
+		args := map[string]interface{}{}
+		if err := contractABI.UnpackIntoMap(args, event.Name, log.Data); err != nil {
+			events = append(events, Event{Name: event.Name, Address: log.Address.Hex(), Raw: log})
+			continue
+		}
+		var indexed abi.Arguments
+		for _, input := range event.Inputs {
+			if input.Indexed {
+				indexed = append(indexed, input)
+			}
+		}
+		if err := abi.ParseTopicsIntoMap(args, indexed, log.Topics[1:]); err != nil {
+			events = append(events, Event{Name: event.Name, Address: log.Address.Hex(), Raw: log})
+			continue
+		}
+		events = append(events, Event{Name: event.Name, Address: log.Address.Hex(), Args: ToJSONValues(args)})
+	}
+	return events
+}
+
+// NamedValues maps the unpacked values to the argument names, the position is used for unnamed arguments.
+func NamedValues(arguments abi.Arguments, values []interface{}) map[string]interface{} {
+	named := make(map[string]interface{}, len(values))
+	for i, value := range values {
+		name := strconv.Itoa(i)
+		if i < len(arguments) && arguments[i].Name != "" {
+			name = arguments[i].Name
+		}
+		named[name] = value
+	}
+	return ToJSONValues(named)
+}
+
+// ToJSONValues converts the unpacked ABI values into a readable JSON form:
+// addresses, hashes and byte values become hex strings, tuples become objects.
+func ToJSONValues(values map[string]interface{}) map[string]interface{} {
+	converted := make(map[string]interface{}, len(values))
+	for name, value := range values {
+		converted[name] = toJSONValue(reflect.ValueOf(value))
+	}
+	return converted
+}
+
+func toJSONValue(value reflect.Value) interface{} {
+	if !value.IsValid() {
+		return nil
+	}
+	switch typed := value.Interface().(type) {
+	case common.Address:
+		return typed.Hex()
+	case common.Hash:
+		return typed.Hex()
+	case *big.Int:
+		return typed
+	case []byte:
+		return hexutil.Encode(typed)
+	}
+
+	switch value.Kind() {
+	case reflect.Array:
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			fixed := make([]byte, value.Len())
+			reflect.Copy(reflect.ValueOf(fixed), value)
+			return hexutil.Encode(fixed)
+		}
+		fallthrough
+	case reflect.Slice:
+		list := make([]interface{}, value.Len())
+		for i := range list {
+			list[i] = toJSONValue(value.Index(i))
+		}
+		return list
+	case reflect.Struct:
+		tuple := make(map[string]interface{}, value.NumField())
+		for i := 0; i < value.NumField(); i++ {
+			tuple[value.Type().Field(i).Name] = toJSONValue(value.Field(i))
+		}
+		return tuple
+	case reflect.Ptr:
+		if value.IsNil() {
+			return nil
+		}
+		return toJSONValue(value.Elem())
+	default:
+		return value.Interface()
+	}
+}
@@ -0,0 +1,47 @@
+package artifacts
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// StripMetadata removes the CBOR-encoded metadata solc appends to the runtime code.
+// The last two bytes hold the length of the metadata, which is a CBOR map (0xa1..0xb7 first byte)
+// with the IPFS/Swarm hash of the compilation metadata and the compiler version. It changes
+// with comments, file paths or compiler settings which don't affect the executed code.
+//
+// Parameters:
+// - code: the runtime code ([]byte)
+// Returns:
+// - the code without the metadata, or the code as is if no metadata is found
+func StripMetadata(code []byte) []byte {
+	if len(code) < 2 {
+		return code
+	}
+	metadataLength := int(code[len(code)-2])<<8 | int(code[len(code)-1])
+	start := len(code) - 2 - metadataLength
+	if metadataLength == 0 || start < 0 {
+		return code
+	}
+	if code[start] < 0xa1 || code[start] > 0xb7 {
+		return code
+	}
+	return code[:start]
+}
+
+// IPFSHash returns the multihash solc embeds in the code for the compilation metadata, with --metadata-hash ipfs:
+// the sha256 of the UnixFS file node holding the content, which fits in a single IPFS chunk.
+//
+// Parameters:
+// - content: the metadata JSON, as written by solc --metadata ([]byte)
+// Returns:
+// - the multihash, 0x1220 followed by the digest
+func IPFSHash(content []byte) []byte {
+	field := func(tag byte, value []byte) []byte {
+		return append(binary.AppendUvarint([]byte{tag}, uint64(len(value))), value...)
+	}
+	unixfs := append([]byte{0x08, 0x02}, field(0x12, content)...)
+	unixfs = binary.AppendUvarint(append(unixfs, 0x18), uint64(len(content)))
+	digest := sha256.Sum256(field(0x0a, unixfs))
+	return append([]byte{0x12, 0x20}, digest[:]...)
+}
@@ -0,0 +1,33 @@
+package artifacts
+
+import (
+	"bytes"
+	"github.com/ethereum/go-ethereum/common"
+	"testing"
+)
+
+func TestStripMetadata(t *testing.T) {
+	// ipfs hash + solc version (0.8.6) map, as appended by solc 0.8.6
+	metadata := common.FromHex("a2646970667358221220e4c28180718748980fcf73c8581ea6929c884b2b1610b3a6d947a5d109ddd54664736f6c63430008060033")
+	code := common.FromHex("6080604052348015600f57600080fd5b50fe")
+
+	tests := []struct {
+		name string
+		code []byte
+		want []byte
+	}{
+		{name: "with metadata", code: append(append([]byte{}, code...), metadata...), want: code},
+		{name: "without metadata", code: code, want: code},
+		{name: "length longer than the code", code: []byte{0x60, 0x80, 0xff, 0xff}, want: []byte{0x60, 0x80, 0xff, 0xff}},
+		{name: "not a CBOR map", code: []byte{0x60, 0x80, 0x00, 0x02}, want: []byte{0x60, 0x80, 0x00, 0x02}},
+		{name: "empty", code: nil, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StripMetadata(tt.code); !bytes.Equal(got, tt.want) {
+				t.Errorf("StripMetadata() = %x, want %x", got, tt.want)
+			}
+		})
+	}
+}
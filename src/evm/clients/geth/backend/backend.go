@@ -0,0 +1,50 @@
+package backend
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum"
+)
+
+// Client is the set of node capabilities the application relies on.
+// It is satisfied by *ethclient.Client as well as by the in-process simulated backend,
+// so the same deploy/call/read logic runs against a live RPC node or offline.
+type Client interface {
+	ethereum.BlockNumberReader
+	ethereum.ChainReader
+	ethereum.ChainStateReader
+	ethereum.ContractCaller
+	ethereum.GasEstimator
+	ethereum.GasPricer
+	ethereum.GasPricer1559
+	ethereum.FeeHistoryReader
+	ethereum.LogFilterer
+	ethereum.PendingStateReader
+	ethereum.PendingContractCaller
+	ethereum.TransactionReader
+	ethereum.TransactionSender
+	ethereum.ChainIDReader
+}
+
+// RawCaller is implemented by the clients able to make any JSON-RPC call,
+// e.g. to the debug namespace, which the Client capabilities don't cover
+type RawCaller interface {
+	CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error
+}
+
+// Wrapper is implemented by the clients wrapping another one to act on its requests,
+// e.g. to journal the sent transactions. The capabilities of the wrapped client
+// (RawCaller, RequestRecorder) are reached through Unwrap.
+type Wrapper interface {
+	Unwrap() Client
+}
+
+// Unwrap returns the innermost client, the client itself if it wraps none
+func Unwrap(client Client) Client {
+	for {
+		wrapper, ok := client.(Wrapper)
+		if !ok {
+			return client
+		}
+		client = wrapper.Unwrap()
+	}
+}
@@ -0,0 +1,552 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"io"
+	"log"
+	"math/big"
+	"math/rand"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// maxBackoff caps the exponential backoff between two attempts
+const maxBackoff = 10 * time.Second
+
+// maxRecords caps the requests recorded for the run report, a load test sends thousands of them.
+// The oldest records are dropped, the requests served by each endpoint are still counted.
+const maxRecords = 1000
+
+// RetryPolicy defines how many times a request is retried on an endpoint before failing over to the next one
+type RetryPolicy struct {
+	Attempts int           // attempts per endpoint, at least 1
+	Backoff  time.Duration // delay before the first retry, doubled on each following one
+}
+
+// RequestRecord tells which endpoint served a request, recorded for the run report
+type RequestRecord struct {
+	Method   string    `json:"method"`
+	Endpoint string    `json:"endpoint"`
+	Attempts int       `json:"attempts"` // attempts across all the endpoints
+	Error    string    `json:"error,omitempty"`
+	Time     time.Time `json:"time"`
+}
+
+// RequestRecorder is implemented by the clients recording which endpoint served each request
+type RequestRecorder interface {
+	Records() []RequestRecord
+	Served() map[string]int // number of requests served by each endpoint, including the dropped records
+	Dropped() int           // number of records dropped, the oldest ones, past the cap
+}
+
+// DialFunc connects to a single endpoint
+type DialFunc func(ctx context.Context, rawURL string) (*ethclient.Client, error)
+
+type endpoint struct {
+	url    string
+	label  string // URL without credentials, safe to log
+	client *ethclient.Client
+}
+
+// FailoverClient is a Client spreading requests over a list of RPC endpoints. Transient errors
+// (timeouts, connection errors, HTTP 429 and 5xx) are retried with exponential backoff and jitter,
+// then the request fails over to the next endpoint, which keeps serving the following requests.
+// Other errors (e.g. reverts) are returned as they are.
+type FailoverClient struct {
+	endpoints []*endpoint
+	dial      DialFunc
+	policy    RetryPolicy
+
+	mu      sync.Mutex
+	current int
+	records []RequestRecord
+	served  map[string]int
+	dropped int
+}
+
+// NewFailoverClient creates a client for the given endpoints, in order of preference.
+// Endpoints are dialed lazily, on their first request.
+//
+// Parameters:
+// - urls: the RPC URLs (string slice)
+// - dial: the function connecting to an endpoint (DialFunc)
+// - policy: the retry policy (RetryPolicy)
+// Returns:
+// - *FailoverClient
+func NewFailoverClient(urls []string, dial DialFunc, policy RetryPolicy) *FailoverClient {
+	if policy.Attempts < 1 {
+		policy.Attempts = 1
+	}
+	endpoints := make([]*endpoint, len(urls))
+	for i, rawURL := range urls {
+		endpoints[i] = &endpoint{url: rawURL, label: RedactURL(rawURL)}
+	}
+	return &FailoverClient{endpoints: endpoints, dial: dial, policy: policy, served: map[string]int{}}
+}
+
+// Records returns which endpoint served each of the last requests, at most maxRecords of them
+func (f *FailoverClient) Records() []RequestRecord {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]RequestRecord(nil), f.records...)
+}
+
+// Served returns the number of requests served by each endpoint so far
+func (f *FailoverClient) Served() map[string]int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	served := make(map[string]int, len(f.served))
+	for label, count := range f.served {
+		served[label] = count
+	}
+	return served
+}
+
+// Dropped returns the number of records dropped past maxRecords
+func (f *FailoverClient) Dropped() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.dropped
+}
+
+// Close closes the connections to all the dialed endpoints
+func (f *FailoverClient) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, e := range f.endpoints {
+		if e.client != nil {
+			e.client.Close()
+			e.client = nil
+		}
+	}
+	return nil
+}
+
+// Connect dials the endpoints until one answers eth_chainId, so a dead configuration fails early
+func (f *FailoverClient) Connect(ctx context.Context) error {
+	_, err := f.ChainID(ctx)
+	return err
+}
+
+// do runs the request, retrying and failing over on transient errors
+func (f *FailoverClient) do(ctx context.Context, method string, request func(client *ethclient.Client) error) error {
+	f.mu.Lock()
+	start := f.current
+	f.mu.Unlock()
+
+	attempts := 0
+	var lastErr error
+	for i := 0; i < len(f.endpoints); i++ {
+		index := (start + i) % len(f.endpoints)
+		e := f.endpoints[index]
+		for attempt := 0; attempt < f.policy.Attempts; attempt++ {
+			if attempt > 0 {
+				if err := sleep(ctx, backoff(f.policy.Backoff, attempt)); err != nil {
+					return f.record(method, e.label, attempts, err)
+				}
+			}
+			attempts++
+			client, err := f.connect(ctx, e)
+			if err == nil {
+				err = request(client)
+			}
+			if err == nil {
+				f.mu.Lock()
+				f.current = index
+				f.mu.Unlock()
+				return f.record(method, e.label, attempts, nil)
+			}
+			// Transport errors quote the URL, which may hold an API key
+			err = e.redact(err)
+			lastErr = err
+			if ctx.Err() != nil || !IsTransientError(err) {
+				return f.record(method, e.label, attempts, err)
+			}
+			log.Printf("RPC %s failed on %s (attempt %d/%d): %v", method, e.label, attempt+1, f.policy.Attempts, err)
+		}
+		if i < len(f.endpoints)-1 {
+			log.Printf("RPC endpoint %s is unavailable, failing over to %s", e.label, f.endpoints[(index+1)%len(f.endpoints)].label)
+		}
+	}
+	return f.record(method, "", attempts, fmt.Errorf("all RPC endpoints failed, last error: %w", lastErr))
+}
+
+// connect returns the client of the endpoint, dialing it on its first request. It dials without holding the lock,
+// so a slow endpoint doesn't block the requests to the others; the first connection made is kept.
+func (f *FailoverClient) connect(ctx context.Context, e *endpoint) (*ethclient.Client, error) {
+	f.mu.Lock()
+	client := e.client
+	f.mu.Unlock()
+	if client != nil {
+		return client, nil
+	}
+
+	client, err := f.dial(ctx, e.url)
+	if err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if e.client != nil {
+		// Dialed concurrently by another request
+		client.Close()
+		return e.client, nil
+	}
+	e.client = client
+	return client, nil
+}
+
+func (f *FailoverClient) record(method string, label string, attempts int, err error) error {
+	record := RequestRecord{Method: method, Endpoint: label, Attempts: attempts, Time: time.Now().UTC()}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	f.mu.Lock()
+	if err == nil {
+		f.served[label]++
+	}
+	if len(f.records) == maxRecords {
+		f.records = append(f.records[1:], record)
+		f.dropped++
+	} else {
+		f.records = append(f.records, record)
+	}
+	f.mu.Unlock()
+	return err
+}
+
+// redactedError hides the endpoint URL in the message, while keeping the original error for errors.Is/As
+type redactedError struct {
+	error
+	message string
+}
+
+func (r redactedError) Error() string { return r.message }
+func (r redactedError) Unwrap() error { return r.error }
+
+func (e *endpoint) redact(err error) error {
+	if e.url == e.label || !strings.Contains(err.Error(), e.url) {
+		return err
+	}
+	return redactedError{error: err, message: strings.ReplaceAll(err.Error(), e.url, e.label)}
+}
+
+// backoff returns the delay before the given retry: exponential, capped, with jitter in [delay/2, delay)
+func backoff(base time.Duration, attempt int) time.Duration {
+	delay := base << (attempt - 1)
+	if delay <= 0 || delay > maxBackoff {
+		delay = maxBackoff
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// IsTransientError tells whether the request may succeed if retried: timeouts, connection
+// errors, rate limiting (HTTP 429) and server errors (HTTP 5xx).
+func IsTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == 429 || httpErr.StatusCode >= 500
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	// JSON-RPC "limit exceeded" and the rate limiting messages of the common providers
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == -32005 {
+		return true
+	}
+	message := strings.ToLower(err.Error())
+	return strings.Contains(message, "too many requests") || strings.Contains(message, "rate limit")
+}
+
+// RedactURL keeps only the scheme and the host of the URL, as credentials and API keys
+// are often part of the user info, the path or the query.
+func RedactURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return "<invalid url>"
+	}
+	redacted := parsed.Scheme + "://" + parsed.Host
+	if parsed.User != nil || (parsed.Path != "" && parsed.Path != "/") || parsed.RawQuery != "" {
+		redacted += "/***"
+	}
+	return redacted
+}
+
+func (f *FailoverClient) BlockNumber(ctx context.Context) (result uint64, err error) {
+	err = f.do(ctx, "eth_blockNumber", func(c *ethclient.Client) (err error) {
+		result, err = c.BlockNumber(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (f *FailoverClient) BlockByHash(ctx context.Context, hash common.Hash) (result *types.Block, err error) {
+	err = f.do(ctx, "eth_getBlockByHash", func(c *ethclient.Client) (err error) {
+		result, err = c.BlockByHash(ctx, hash)
+		return err
+	})
+	return result, err
+}
+
+func (f *FailoverClient) BlockByNumber(ctx context.Context, number *big.Int) (result *types.Block, err error) {
+	err = f.do(ctx, "eth_getBlockByNumber", func(c *ethclient.Client) (err error) {
+		result, err = c.BlockByNumber(ctx, number)
+		return err
+	})
+	return result, err
+}
+
+func (f *FailoverClient) HeaderByHash(ctx context.Context, hash common.Hash) (result *types.Header, err error) {
+	err = f.do(ctx, "eth_getBlockByHash", func(c *ethclient.Client) (err error) {
+		result, err = c.HeaderByHash(ctx, hash)
+		return err
+	})
+	return result, err
+}
+
+func (f *FailoverClient) HeaderByNumber(ctx context.Context, number *big.Int) (result *types.Header, err error) {
+	err = f.do(ctx, "eth_getBlockByNumber", func(c *ethclient.Client) (err error) {
+		result, err = c.HeaderByNumber(ctx, number)
+		return err
+	})
+	return result, err
+}
+
+func (f *FailoverClient) TransactionCount(ctx context.Context, blockHash common.Hash) (result uint, err error) {
+	err = f.do(ctx, "eth_getBlockTransactionCountByHash", func(c *ethclient.Client) (err error) {
+		result, err = c.TransactionCount(ctx, blockHash)
+		return err
+	})
+	return result, err
+}
+
+func (f *FailoverClient) TransactionInBlock(ctx context.Context, blockHash common.Hash, index uint) (result *types.Transaction, err error) {
+	err = f.do(ctx, "eth_getTransactionByBlockHashAndIndex", func(c *ethclient.Client) (err error) {
+		result, err = c.TransactionInBlock(ctx, blockHash, index)
+		return err
+	})
+	return result, err
+}
+
+// SubscribeNewHead is not retried, the subscription lives on the endpoint currently in use
+func (f *FailoverClient) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (result ethereum.Subscription, err error) {
+	err = f.do(ctx, "eth_subscribe", func(c *ethclient.Client) (err error) {
+		result, err = c.SubscribeNewHead(ctx, ch)
+		return err
+	})
+	return result, err
+}
+
+func (f *FailoverClient) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (result *big.Int, err error) {
+	err = f.do(ctx, "eth_getBalance", func(c *ethclient.Client) (err error) {
+		result, err = c.BalanceAt(ctx, account, blockNumber)
+		return err
+	})
+	return result, err
+}
+
+func (f *FailoverClient) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) (result []byte, err error) {
+	err = f.do(ctx, "eth_getStorageAt", func(c *ethclient.Client) (err error) {
+		result, err = c.StorageAt(ctx, account, key, blockNumber)
+		return err
+	})
+	return result, err
+}
+
+func (f *FailoverClient) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) (result []byte, err error) {
+	err = f.do(ctx, "eth_getCode", func(c *ethclient.Client) (err error) {
+		result, err = c.CodeAt(ctx, account, blockNumber)
+		return err
+	})
+	return result, err
+}
+
+func (f *FailoverClient) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (result uint64, err error) {
+	err = f.do(ctx, "eth_getTransactionCount", func(c *ethclient.Client) (err error) {
+		result, err = c.NonceAt(ctx, account, blockNumber)
+		return err
+	})
+	return result, err
+}
+
+func (f *FailoverClient) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) (result []byte, err error) {
+	err = f.do(ctx, "eth_call", func(c *ethclient.Client) (err error) {
+		result, err = c.CallContract(ctx, call, blockNumber)
+		return err
+	})
+	return result, err
+}
+
+func (f *FailoverClient) EstimateGas(ctx context.Context, call ethereum.CallMsg) (result uint64, err error) {
+	err = f.do(ctx, "eth_estimateGas", func(c *ethclient.Client) (err error) {
+		result, err = c.EstimateGas(ctx, call)
+		return err
+	})
+	return result, err
+}
+
+func (f *FailoverClient) SuggestGasPrice(ctx context.Context) (result *big.Int, err error) {
+	err = f.do(ctx, "eth_gasPrice", func(c *ethclient.Client) (err error) {
+		result, err = c.SuggestGasPrice(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (f *FailoverClient) SuggestGasTipCap(ctx context.Context) (result *big.Int, err error) {
+	err = f.do(ctx, "eth_maxPriorityFeePerGas", func(c *ethclient.Client) (err error) {
+		result, err = c.SuggestGasTipCap(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (f *FailoverClient) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (result *ethereum.FeeHistory, err error) {
+	err = f.do(ctx, "eth_feeHistory", func(c *ethclient.Client) (err error) {
+		result, err = c.FeeHistory(ctx, blockCount, lastBlock, rewardPercentiles)
+		return err
+	})
+	return result, err
+}
+
+func (f *FailoverClient) FilterLogs(ctx context.Context, q ethereum.FilterQuery) (result []types.Log, err error) {
+	err = f.do(ctx, "eth_getLogs", func(c *ethclient.Client) (err error) {
+		result, err = c.FilterLogs(ctx, q)
+		return err
+	})
+	return result, err
+}
+
+// SubscribeFilterLogs is not retried, the subscription lives on the endpoint currently in use
+func (f *FailoverClient) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (result ethereum.Subscription, err error) {
+	err = f.do(ctx, "eth_subscribe", func(c *ethclient.Client) (err error) {
+		result, err = c.SubscribeFilterLogs(ctx, q, ch)
+		return err
+	})
+	return result, err
+}
+
+func (f *FailoverClient) PendingBalanceAt(ctx context.Context, account common.Address) (result *big.Int, err error) {
+	err = f.do(ctx, "eth_getBalance", func(c *ethclient.Client) (err error) {
+		result, err = c.PendingBalanceAt(ctx, account)
+		return err
+	})
+	return result, err
+}
+
+func (f *FailoverClient) PendingStorageAt(ctx context.Context, account common.Address, key common.Hash) (result []byte, err error) {
+	err = f.do(ctx, "eth_getStorageAt", func(c *ethclient.Client) (err error) {
+		result, err = c.PendingStorageAt(ctx, account, key)
+		return err
+	})
+	return result, err
+}
+
+func (f *FailoverClient) PendingCodeAt(ctx context.Context, account common.Address) (result []byte, err error) {
+	err = f.do(ctx, "eth_getCode", func(c *ethclient.Client) (err error) {
+		result, err = c.PendingCodeAt(ctx, account)
+		return err
+	})
+	return result, err
+}
+
+func (f *FailoverClient) PendingNonceAt(ctx context.Context, account common.Address) (result uint64, err error) {
+	err = f.do(ctx, "eth_getTransactionCount", func(c *ethclient.Client) (err error) {
+		result, err = c.PendingNonceAt(ctx, account)
+		return err
+	})
+	return result, err
+}
+
+func (f *FailoverClient) PendingTransactionCount(ctx context.Context) (result uint, err error) {
+	err = f.do(ctx, "eth_getBlockTransactionCountByNumber", func(c *ethclient.Client) (err error) {
+		result, err = c.PendingTransactionCount(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (f *FailoverClient) PendingCallContract(ctx context.Context, call ethereum.CallMsg) (result []byte, err error) {
+	err = f.do(ctx, "eth_call", func(c *ethclient.Client) (err error) {
+		result, err = c.PendingCallContract(ctx, call)
+		return err
+	})
+	return result, err
+}
+
+func (f *FailoverClient) TransactionByHash(ctx context.Context, hash common.Hash) (result *types.Transaction, isPending bool, err error) {
+	err = f.do(ctx, "eth_getTransactionByHash", func(c *ethclient.Client) (err error) {
+		result, isPending, err = c.TransactionByHash(ctx, hash)
+		return err
+	})
+	return result, isPending, err
+}
+
+func (f *FailoverClient) TransactionReceipt(ctx context.Context, txHash common.Hash) (result *types.Receipt, err error) {
+	err = f.do(ctx, "eth_getTransactionReceipt", func(c *ethclient.Client) (err error) {
+		result, err = c.TransactionReceipt(ctx, txHash)
+		return err
+	})
+	return result, err
+}
+
+// SendTransaction may be retried after a timeout while the node already accepted the transaction,
+// so "already known" on a retry means the transaction is in the pool.
+func (f *FailoverClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	attempt := 0
+	return f.do(ctx, "eth_sendRawTransaction", func(c *ethclient.Client) error {
+		attempt++
+		err := c.SendTransaction(ctx, tx)
+		if err != nil && attempt > 1 && strings.Contains(strings.ToLower(err.Error()), "already known") {
+			return nil
+		}
+		return err
+	})
+}
+
+// CallContext makes any JSON-RPC call, retried and failed over like the other requests
+func (f *FailoverClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	return f.do(ctx, method, func(c *ethclient.Client) error {
+		return c.Client().CallContext(ctx, result, method, args...)
+	})
+}
+
+func (f *FailoverClient) ChainID(ctx context.Context) (result *big.Int, err error) {
+	err = f.do(ctx, "eth_chainId", func(c *ethclient.Client) (err error) {
+		result, err = c.ChainID(ctx)
+		return err
+	})
+	return result, err
+}
@@ -0,0 +1,45 @@
+package backend
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/node"
+)
+
+// SimulatedClient is an in-process chain, built on go-ethereum's simulated.Backend.
+// Every sent transaction is sealed into a block straight away (automine), so code waiting
+// for receipts behaves the same way it does against a live node.
+type SimulatedClient struct {
+	simulated.Client
+	backend *simulated.Backend
+}
+
+// NewSimulatedClient starts a simulated chain (chain ID 1337) with the given genesis allocation.
+//
+// Parameters:
+// - alloc: accounts (balances, code) that exist in the genesis block (types.GenesisAlloc)
+// Returns:
+// - *SimulatedClient
+func NewSimulatedClient(alloc types.GenesisAlloc) *SimulatedClient {
+	// Like the development nodes, it accepts presigned transactions without replay protection
+	sim := simulated.NewBackend(alloc, func(nodeConf *node.Config, ethConf *ethconfig.Config) {
+		nodeConf.AllowUnprotectedTxs = true
+	})
+	return &SimulatedClient{Client: sim.Client(), backend: sim}
+}
+
+// SendTransaction submits the transaction and immediately seals it into a new block.
+func (s *SimulatedClient) SendTransaction(ctx context.Context, transaction *types.Transaction) error {
+	if err := s.Client.SendTransaction(ctx, transaction); err != nil {
+		return err
+	}
+	s.backend.Commit()
+	return nil
+}
+
+// Close shuts the simulated chain down. The client can't be used afterwards.
+func (s *SimulatedClient) Close() error {
+	return s.backend.Close()
+}
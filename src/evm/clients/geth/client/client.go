@@ -1,54 +1,240 @@
 package client
 
 import (
+	"context"
 	"crypto/ecdsa"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
 	"log"
+	"main/src/config"
 	"main/src/contracts/getter_setter"
+	"main/src/evm/clients/geth/artifacts"
+	"main/src/evm/clients/geth/backend"
+	"main/src/evm/clients/geth/reverts"
 	"main/src/evm/clients/geth/transactions"
+	"main/src/utils"
 	"math/big"
+	"time"
 )
 
-func ConnectClient(rpcURL string) *ethclient.Client {
-	log.Println("Connecting to the client...")
-	client, err := ethclient.Dial(rpcURL)
+// simulatedChainID is the chain ID go-ethereum's simulated backend always runs with
+const simulatedChainID = 1337
+
+// NewClient creates the client of RPC.Url and its RPC.Urls fallbacks. Transient errors are retried
+// according to RPC.Retries and RPC.RetryBackoff, then the requests fail over to the next endpoint.
+// Each endpoint is dialed with the headers, authentication, TLS and proxy settings of the [RPC] section,
+// on its first request.
+//
+// Parameters:
+// - rpcConfig: the [RPC] section of the configuration (config.RPC)
+// Returns:
+// - *backend.FailoverClient
+func NewClient(rpcConfig config.RPC) *backend.FailoverClient {
+	dial, err := NewDialFunc(rpcConfig)
 	if err != nil {
-		log.Fatal("Could not connect to the client:", err)
+		utils.Fatal("Could not configure the client: ", err)
+	}
+	policy := backend.RetryPolicy{
+		Attempts: rpcConfig.Retries,
+		Backoff:  time.Duration(rpcConfig.RetryBackoff) * time.Millisecond,
+	}
+	return backend.NewFailoverClient(append([]string{rpcConfig.Url}, rpcConfig.Urls...), dial, policy)
+}
+
+// ConnectClient connects the client to the first endpoint answering, so a dead configuration fails early.
+//
+// Parameters:
+// - ctx: the context of the run (context.Context)
+// - client: the client created by NewClient (*backend.FailoverClient)
+func ConnectClient(ctx context.Context, client *backend.FailoverClient) {
+	log.Println("Connecting to the client...")
+	if err := client.Connect(ctx); err != nil {
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatal("Could not connect to the client:", err)
 	}
 	log.Println("Client connected")
+}
+
+// ConnectSimulatedClient starts an in-process simulated chain instead of dialing an RPC node.
+// The given account is pre-funded with 1000 ETH. If a contract address is provided,
+// the GetterSetter runtime code is placed at that address in the genesis block,
+// so "call-contract" and "read-only-contract" modes work without deploying first.
+//
+// Parameters:
+// - fundedAddress: the address to pre-fund (common.Address)
+// - contractAddress: the address to seed with GetterSetter code, optional (string)
+// Returns:
+// - backend.Client
+func ConnectSimulatedClient(fundedAddress common.Address, contractAddress string) backend.Client {
+	log.Println("Starting the simulated client...")
+	alloc := simulatedGenesis(fundedAddress)
+	if contractAddress != "" {
+		alloc[common.HexToAddress(contractAddress)] = types.Account{Code: SimulateRuntimeCode(common.FromHex(getter_setter.GetterSetterMetaData.Bin))}
+		log.Println("Simulated GetterSetter contract is available at:", contractAddress)
+	}
+	client := backend.NewSimulatedClient(alloc)
+	log.Println("Simulated client started, account pre-funded:", fundedAddress)
 	return client
 }
 
-func DeployContract(auth *bind.TransactOpts, client *ethclient.Client, timeout int) common.Address {
+// simulatedGenesis returns the genesis accounts every simulated chain has: the funded account and the CREATE2 factory
+func simulatedGenesis(fundedAddress common.Address) types.GenesisAlloc {
+	return types.GenesisAlloc{
+		fundedAddress: {Balance: new(big.Int).Mul(big.NewInt(1000), big.NewInt(params.Ether))},
+		// Available from genesis as on the public networks, its presigned transaction has no replay protection
+		DeterministicDeployerAddress: {Code: DeterministicDeployerCode},
+	}
+}
+
+// SimulateRuntimeCode deploys the creation code to a throw-away simulated chain and returns
+// the runtime code, i.e. the code which ends up stored on-chain after the constructor has run.
+//
+// Parameters:
+// - creationCode: the creation (init) code, as produced by solc --bin ([]byte)
+// Returns:
+// - the runtime code ([]byte)
+func SimulateRuntimeCode(creationCode []byte) []byte {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		utils.Fatalf("Failed to generate a key for the simulated deployment: %v", err)
+	}
+	scratchAddress := crypto.PubkeyToAddress(key.PublicKey)
+	scratch := backend.NewSimulatedClient(types.GenesisAlloc{
+		scratchAddress: {Balance: big.NewInt(params.Ether)},
+	})
+	defer scratch.Close()
+
+	auth, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(simulatedChainID))
+	if err != nil {
+		utils.Fatalf("Failed to create the simulated transactor: %v", err)
+	}
+	address, _, _, err := bind.DeployContract(auth, abi.ABI{}, creationCode, scratch)
+	if err != nil {
+		utils.Fatalf("Failed to deploy the code to the simulated chain: %v", err)
+	}
+	code, err := scratch.CodeAt(context.Background(), address, nil)
+	if err != nil || len(code) == 0 {
+		utils.Fatalf("Failed to get the runtime code from the simulated chain: %v", err)
+	}
+	return code
+}
+
+func DeployContract(ctx context.Context, auth *bind.TransactOpts, client backend.Client, timeout int) common.Address {
 	address, transaction, _, err := getter_setter.DeployGetterSetter(auth, client)
 	if err != nil {
-		log.Fatalf("Failed to deploy new GetterSetter contract: %v", err)
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatalf("Failed to deploy new GetterSetter contract: %v", reverts.Explain("GetterSetter deployment", err))
 	}
 
 	log.Printf("Waiting for pending contract deployment with transaction hash: 0x%x, for contract address: 0x%x", transaction.Hash(), address)
-	deployedContractAddress := transactions.WaitDeployed(client, transaction, timeout)
-	log.Printf("Contract address: %s. Write it down for future (re-)usage.", deployedContractAddress)
+	deployedContractAddress := transactions.WaitDeployed(ctx, client, transaction, timeout)
+	log.Printf("Contract address: %s", deployedContractAddress)
 
 	return deployedContractAddress
 }
 
+// DeployArtifact deploys an arbitrary contract from its ABI and bytecode and waits for the deployment
+// with the same timeout logic as DeployContract.
+//
+// Parameters:
+// - ctx: the context of the run (context.Context)
+// - auth: the signer options (*bind.TransactOpts)
+// - client: the Ethereum client (backend.Client)
+// - artifact: the ABI and the creation bytecode of the contract (artifacts.Artifact)
+// - constructorArgs: the constructor arguments, already parsed into ABI Go types ([]interface{})
+// - timeout: the waiting timeout, in seconds (int)
+// Returns:
+// - common.Address: address of the deployed contract
+// - *types.Transaction: the deployment transaction
+func DeployArtifact(ctx context.Context, auth *bind.TransactOpts, client backend.Client, artifact artifacts.Artifact, constructorArgs []interface{}, timeout int) (common.Address, *types.Transaction) {
+	if len(artifact.Bytecode) == 0 {
+		utils.Fatal("Failed to deploy the artifact: the bytecode is empty")
+	}
+	address, transaction, _, err := bind.DeployContract(auth, artifact.ABI, artifact.Bytecode, client, constructorArgs...)
+	if err != nil {
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatalf("Failed to deploy the artifact: %v", reverts.Explain("artifact deployment", err))
+	}
+
+	log.Printf("Waiting for pending artifact deployment with transaction hash: 0x%x, for contract address: 0x%x", transaction.Hash(), address)
+	deployedContractAddress := transactions.WaitDeployed(ctx, client, transaction, timeout)
+	log.Printf("Contract address: %s", deployedContractAddress)
+
+	return deployedContractAddress, transaction
+}
+
 // GetTransactor returns the transactor (signer) for the given private key
 func GetTransactor(privateKeyECDSA *ecdsa.PrivateKey, chainID *big.Int) *bind.TransactOpts {
 	auth, err := bind.NewKeyedTransactorWithChainID(privateKeyECDSA, chainID)
 	if err != nil {
-		log.Fatalf("Failed to create authorized transactor: %v", err)
+		utils.Fatalf("Failed to create authorized transactor: %v", err)
 	}
 	log.Println("Authorized transactor for address", auth.From)
 	return auth
 }
 
-func AttachToContract(contractAddress string, client *ethclient.Client) *getter_setter.GetterSetter {
+// AttachToContract attaches the GetterSetter binding to the contract address, after checking
+// that there is contract code at the address and that it answers all the GetterSetter getters.
+func AttachToContract(ctx context.Context, contractAddress string, client backend.Client) *getter_setter.GetterSetter {
+	if err := CheckContractCode(ctx, contractAddress, client); err != nil {
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatalf("Failed to attach to the contract: %v", err)
+	}
 	getterSetterContract, err := getter_setter.NewGetterSetter(common.HexToAddress(contractAddress), client)
 	if err != nil {
-		log.Fatalf("Failed to attach to the contract: %v", err)
+		utils.Fatalf("Failed to attach to the contract: %v", err)
+	}
+	if err := ProbeGetterSetter(ctx, getterSetterContract); err != nil {
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatalf("Failed to attach to the contract: %s does not look like a GetterSetter contract: %v", contractAddress, err)
 	}
 	log.Println("Successfully attached to the contract:", contractAddress)
 	return getterSetterContract
 }
+
+// CheckContractCode checks that there is contract code deployed at the address (eth_getCode),
+// so an EOA or a wrong network is detected before any transaction is sent.
+func CheckContractCode(ctx context.Context, contractAddress string, client backend.Client) error {
+	code, err := client.CodeAt(ctx, common.HexToAddress(contractAddress), nil)
+	if err != nil {
+		return fmt.Errorf("could not get the code at %s: %w", contractAddress, err)
+	}
+	if len(code) == 0 {
+		return fmt.Errorf("there is no contract code at %s, check the address and the network", contractAddress)
+	}
+	return nil
+}
+
+// ProbeGetterSetter calls each GetterSetter getter to check that the contract is ABI-compatible.
+func ProbeGetterSetter(ctx context.Context, getterSetterContract *getter_setter.GetterSetter) error {
+	opts := &bind.CallOpts{Context: ctx}
+	if _, err := getterSetterContract.GetUint256(opts); err != nil {
+		return fmt.Errorf("getUint256() failed: %w", err)
+	}
+	if _, err := getterSetterContract.GetBytes32(opts); err != nil {
+		return fmt.Errorf("getBytes32() failed: %w", err)
+	}
+	if _, err := getterSetterContract.GetBytes(opts); err != nil {
+		return fmt.Errorf("getBytes() failed: %w", err)
+	}
+	if _, err := getterSetterContract.RequestId(opts); err != nil {
+		return fmt.Errorf("requestId() failed: %w", err)
+	}
+	return nil
+}
+
+// BindContract attaches to an arbitrary contract by its ABI, after checking that there is contract code at the address
+func BindContract(ctx context.Context, contractAddress string, contractABI abi.ABI, client backend.Client) *bind.BoundContract {
+	if err := CheckContractCode(ctx, contractAddress, client); err != nil {
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatalf("Failed to bind to the contract: %v", err)
+	}
+	boundContract := bind.NewBoundContract(common.HexToAddress(contractAddress), contractABI, client, client, client)
+	log.Println("Successfully bound to the contract:", contractAddress)
+	return boundContract
+}
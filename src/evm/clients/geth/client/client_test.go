@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"main/src/contracts/getter_setter"
+	"main/src/evm/clients/geth/backend"
+	"testing"
+)
+
+func TestCheckContractCodeAndProbeGetterSetter(t *testing.T) {
+	getterSetterAddress := common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3")
+	otherContractAddress := common.HexToAddress("0xe7f1725E7734CE288F8367e1Bb143E90bb3F0512")
+	accountAddress := common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+
+	simulatedClient := backend.NewSimulatedClient(types.GenesisAlloc{
+		getterSetterAddress: {Code: SimulateRuntimeCode(common.FromHex(getter_setter.GetterSetterMetaData.Bin))},
+		// returns 42 for any call
+		otherContractAddress: {Code: common.FromHex("602a60005260206000f3")},
+		accountAddress:       {Balance: common.Big1},
+	})
+	t.Cleanup(func() { simulatedClient.Close() })
+
+	tests := []struct {
+		name         string
+		address      common.Address
+		wantCodeErr  bool
+		wantProbeErr bool
+	}{
+		{name: "GetterSetter contract", address: getterSetterAddress},
+		{name: "other contract", address: otherContractAddress, wantProbeErr: true},
+		{name: "externally owned account", address: accountAddress, wantCodeErr: true},
+		{name: "unused address", address: common.HexToAddress("0x0000000000000000000000000000000000000001"), wantCodeErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckContractCode(context.Background(), tt.address.Hex(), simulatedClient)
+			if (err != nil) != tt.wantCodeErr {
+				t.Fatalf("CheckContractCode() error = %v, wantErr %v", err, tt.wantCodeErr)
+			}
+			if tt.wantCodeErr {
+				return
+			}
+			contract, err := getter_setter.NewGetterSetter(tt.address, simulatedClient)
+			if err != nil {
+				t.Fatalf("could not bind the contract: %v", err)
+			}
+			if err := ProbeGetterSetter(context.Background(), contract); (err != nil) != tt.wantProbeErr {
+				t.Errorf("ProbeGetterSetter() error = %v, wantErr %v", err, tt.wantProbeErr)
+			}
+		})
+	}
+}
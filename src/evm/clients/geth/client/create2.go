@@ -0,0 +1,161 @@
+package client
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"log"
+	"main/src/evm/clients/geth/backend"
+	"main/src/evm/clients/geth/reverts"
+	"main/src/evm/clients/geth/transactions"
+	"main/src/utils"
+	"math/big"
+)
+
+// The deterministic deployment proxy (https://github.com/Arachnid/deterministic-deployment-proxy):
+// a CREATE2 factory deployed at the same address on most networks, by a pre-EIP-155 transaction
+// signed with a made up signature, so nobody knows the key of its deployer.
+var (
+	DeterministicDeployerAddress = common.HexToAddress("0x4e59b44847b379578588920ca78fbf26c0b4956c")
+	deterministicDeployerSigner  = common.HexToAddress("0x3fab184622dc19b6109349b94811493bf2a45362")
+	// The presigned deployment transaction: nonce 0, gas price 100 gwei, gas limit 100000
+	deterministicDeployerTransaction = common.FromHex("0xf8a58085174876e800830186a08080b853604580600e600039806000f350fe7fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffe03601600081602082378035828234f58015156039578182fd5b8082525050506014600cf31ba02222222222222222222222222222222222222222222222222222222222222222a02222222222222222222222222222222222222222222222222222222222222222")
+	// DeterministicDeployerCode is the runtime code of the proxy: it CREATE2s the calldata after the first 32 bytes, with them as salt
+	DeterministicDeployerCode = common.FromHex("0x7fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffe03601600081602082378035828234f58015156039578182fd5b8082525050506014600cf3")
+)
+
+// devChainIDs are the chain IDs of the local development chains (geth --dev, Ganache, Hardhat, Anvil),
+// where the deterministic deployment proxy is deployed by the application if it is absent
+var devChainIDs = map[uint64]bool{1337: true, 31337: true}
+
+// Create2Address pre-computes the address of a contract deployed through the deterministic deployment proxy.
+//
+// Parameters:
+// - salt: the CREATE2 salt (common.Hash)
+// - initCode: the creation code, including the encoded constructor arguments ([]byte)
+// Returns:
+// - common.Address
+func Create2Address(salt common.Hash, initCode []byte) common.Address {
+	return crypto.CreateAddress2(DeterministicDeployerAddress, salt, crypto.Keccak256(initCode))
+}
+
+// EnsureDeterministicDeployer checks that the deterministic deployment proxy is deployed. On the development
+// chains, it is deployed if absent: its deployer is funded from the signer, then the presigned transaction is sent.
+//
+// Parameters:
+// - ctx: the context of the run (context.Context)
+// - auth: the signer options, funding the proxy deployer (*bind.TransactOpts)
+// - client: the Ethereum client (backend.Client)
+// - timeout: the waiting timeout, in seconds (int)
+func EnsureDeterministicDeployer(ctx context.Context, auth *bind.TransactOpts, client backend.Client, timeout int) {
+	code, err := client.CodeAt(ctx, DeterministicDeployerAddress, nil)
+	if err != nil {
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatalf("Failed to get the code of the deterministic deployment proxy: %v", err)
+	}
+	if len(code) > 0 {
+		return
+	}
+	chainID := transactions.GetChainId(ctx, client)
+	if !devChainIDs[chainID.Uint64()] {
+		utils.Fatalf("The deterministic deployment proxy is not deployed at %s on chain %s. It is only deployed by the application on development chains.", DeterministicDeployerAddress.Hex(), chainID)
+	}
+
+	log.Printf("Deploying the deterministic deployment proxy at %s", DeterministicDeployerAddress.Hex())
+	if value := DeterministicDeployerFunding(ctx, client); value.Sign() > 0 {
+		funding := *auth
+		funding.Value = value
+		funding.GasLimit = 21000
+		transaction, err := bind.NewBoundContract(deterministicDeployerSigner, abi.ABI{}, client, client, client).Transfer(&funding)
+		if err != nil {
+			transactions.ExitIfInterrupted(ctx)
+			utils.Fatalf("Failed to fund the proxy deployer %s: %v", deterministicDeployerSigner.Hex(), err)
+		}
+		transactions.WaitMined(ctx, client, transaction, timeout)
+		// The nonce is used, the next transaction of the signer takes the following one
+		auth.Nonce = new(big.Int).Add(auth.Nonce, big.NewInt(1))
+	}
+
+	presigned := presignedDeterministicDeployment()
+	if err := client.SendTransaction(ctx, presigned); err != nil {
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatalf("Failed to deploy the deterministic deployment proxy: %v. The node must accept transactions without replay protection (e.g. geth --rpc.allow-unprotected-txs).", err)
+	}
+	transactions.WaitMined(ctx, client, presigned, timeout)
+}
+
+// DeterministicDeployerFunding returns the ETH EnsureDeterministicDeployer sends to the deployer of the deterministic
+// deployment proxy, the cost of the presigned transaction it lacks, zero if it has enough.
+//
+// Parameters:
+// - ctx: the context of the run (context.Context)
+// - client: the Ethereum client (backend.Client)
+// Returns:
+// - the funding, in wei (*big.Int)
+func DeterministicDeployerFunding(ctx context.Context, client backend.Client) *big.Int {
+	presigned := presignedDeterministicDeployment()
+	deploymentCost := new(big.Int).Mul(presigned.GasPrice(), new(big.Int).SetUint64(presigned.Gas()))
+	balance, err := client.BalanceAt(ctx, deterministicDeployerSigner, nil)
+	if err != nil {
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatalf("Failed to get the balance of the proxy deployer: %v", err)
+	}
+	if balance.Cmp(deploymentCost) >= 0 {
+		return big.NewInt(0)
+	}
+	return new(big.Int).Sub(deploymentCost, balance)
+}
+
+func presignedDeterministicDeployment() *types.Transaction {
+	presigned := new(types.Transaction)
+	if err := presigned.UnmarshalBinary(deterministicDeployerTransaction); err != nil {
+		utils.Fatalf("Failed to decode the deterministic deployment proxy transaction: %v", err)
+	}
+	return presigned
+}
+
+// DeployCreate2 deploys the creation code through the deterministic deployment proxy, at an address which
+// only depends on the salt and the code. The deployment is skipped if there is already code at the address,
+// e.g. deployed by a previous run or on another network with the same salt.
+//
+// Parameters:
+// - ctx: the context of the run (context.Context)
+// - auth: the signer options (*bind.TransactOpts)
+// - client: the Ethereum client (backend.Client)
+// - salt: the CREATE2 salt (common.Hash)
+// - initCode: the creation code, including the encoded constructor arguments ([]byte)
+// - timeout: the waiting timeout, in seconds (int)
+// Returns:
+// - common.Address: address of the contract
+// - *types.Transaction: the deployment transaction, nil if the deployment was skipped
+func DeployCreate2(ctx context.Context, auth *bind.TransactOpts, client backend.Client, salt common.Hash, initCode []byte, timeout int) (common.Address, *types.Transaction) {
+	address := Create2Address(salt, initCode)
+	code, err := client.CodeAt(ctx, address, nil)
+	if err != nil {
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatalf("Failed to get the code at %s: %v", address.Hex(), err)
+	}
+	if len(code) > 0 {
+		log.Printf("Contract address: %s, already deployed with salt %s, skipping the deployment", address.Hex(), salt.Hex())
+		return address, nil
+	}
+
+	calldata := append(salt.Bytes(), initCode...)
+	transaction, err := bind.NewBoundContract(DeterministicDeployerAddress, abi.ABI{}, client, client, client).RawTransact(auth, calldata)
+	if err != nil {
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatalf("Failed to deploy through the deterministic deployment proxy: %v", reverts.Explain("CREATE2 deployment", err))
+	}
+	log.Printf("Waiting for pending CREATE2 deployment with transaction hash: 0x%x, for contract address: %s", transaction.Hash(), address.Hex())
+	receipt := transactions.WaitMined(ctx, client, transaction, timeout)
+	// The proxy reverts when CREATE2 fails, e.g. when the constructor reverts or runs out of gas
+	if err := reverts.CheckReceipt(ctx, client, "CREATE2 deployment", transaction, receipt); err != nil {
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatal(err)
+	}
+	log.Printf("Contract address: %s", address.Hex())
+	return address, transaction
+}
@@ -0,0 +1,61 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"main/src/contracts/getter_setter"
+	"main/src/evm/clients/geth/backend"
+	"math/big"
+	"testing"
+)
+
+func TestDeployCreate2OnDevChainWithoutProxy(t *testing.T) {
+	ctx := context.Background()
+	key, err := crypto.HexToECDSA("ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	// No proxy in the genesis, as on a fresh development node
+	simulatedClient := backend.NewSimulatedClient(types.GenesisAlloc{
+		from: {Balance: new(big.Int).Mul(big.NewInt(10), big.NewInt(params.Ether))},
+	})
+	t.Cleanup(func() { simulatedClient.Close() })
+
+	signer := func() *bind.TransactOpts {
+		auth := GetTransactor(key, big.NewInt(1337))
+		nonce, err := simulatedClient.PendingNonceAt(ctx, from)
+		if err != nil {
+			t.Fatal(err)
+		}
+		auth.Nonce = new(big.Int).SetUint64(nonce)
+		auth.GasLimit = 3000000
+		return auth
+	}
+
+	EnsureDeterministicDeployer(ctx, signer(), simulatedClient, 0)
+	code, err := simulatedClient.CodeAt(ctx, DeterministicDeployerAddress, nil)
+	if err != nil || !bytes.Equal(code, DeterministicDeployerCode) {
+		t.Fatalf("proxy code = %x (err: %v), want %x", code, err, DeterministicDeployerCode)
+	}
+	// Deployed already, nothing is sent
+	EnsureDeterministicDeployer(ctx, signer(), simulatedClient, 0)
+
+	initCode := common.FromHex(getter_setter.GetterSetterMetaData.Bin)
+	salt := common.HexToHash("0x01")
+	address, transaction := DeployCreate2(ctx, signer(), simulatedClient, salt, initCode, 0)
+	if want := Create2Address(salt, initCode); address != want || transaction == nil {
+		t.Fatalf("DeployCreate2() = (%s, %v), want (%s, a transaction)", address.Hex(), transaction, want.Hex())
+	}
+	if err := CheckContractCode(ctx, address.Hex(), simulatedClient); err != nil {
+		t.Errorf("CheckContractCode() error = %v", err)
+	}
+	if _, transaction := DeployCreate2(ctx, signer(), simulatedClient, salt, initCode, 0); transaction != nil {
+		t.Errorf("second DeployCreate2() sent %s, want the deployment skipped", transaction.Hash().Hex())
+	}
+}
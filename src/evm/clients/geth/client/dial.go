@@ -0,0 +1,139 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/gorilla/websocket"
+	"main/src/config"
+	"main/src/evm/clients/geth/backend"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// NewDialFunc returns the function connecting to an endpoint with the headers, authentication,
+// TLS and proxy settings of the [RPC] section. The secrets are only ever sent to the node, never logged.
+//
+// Parameters:
+// - rpcConfig: the [RPC] section of the configuration (config.RPC)
+// Returns:
+// - backend.DialFunc
+// - error if a secret, a certificate or the proxy URL can't be loaded
+func NewDialFunc(rpcConfig config.RPC) (backend.DialFunc, error) {
+	options, err := dialOptions(rpcConfig)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, rawURL string) (*ethclient.Client, error) {
+		rpcClient, err := rpc.DialOptions(ctx, rawURL, options...)
+		if err != nil {
+			return nil, err
+		}
+		return ethclient.NewClient(rpcClient), nil
+	}, nil
+}
+
+func dialOptions(rpcConfig config.RPC) ([]rpc.ClientOption, error) {
+	var options []rpc.ClientOption
+
+	headers := http.Header{}
+	for name, value := range rpcConfig.Headers {
+		headers.Set(name, value)
+	}
+	switch {
+	case rpcConfig.BearerToken != "":
+		headers.Set("Authorization", "Bearer "+rpcConfig.BearerToken)
+	case rpcConfig.Username != "" || rpcConfig.Password != "":
+		credentials := base64.StdEncoding.EncodeToString([]byte(rpcConfig.Username + ":" + rpcConfig.Password))
+		headers.Set("Authorization", "Basic "+credentials)
+	case rpcConfig.JwtSecret != "":
+		secret, err := loadJwtSecret(rpcConfig.JwtSecret)
+		if err != nil {
+			return nil, err
+		}
+		// A fresh token is signed for each request, as the node only accepts recently issued ones
+		options = append(options, rpc.WithHTTPAuth(node.NewJWTAuth(secret)))
+	}
+	if len(headers) > 0 {
+		options = append(options, rpc.WithHeaders(headers))
+	}
+
+	tlsConfig, err := loadTLSConfig(rpcConfig)
+	if err != nil {
+		return nil, err
+	}
+	proxy := http.ProxyFromEnvironment
+	if rpcConfig.Proxy != "" {
+		proxyURL, err := url.Parse(rpcConfig.Proxy)
+		if err != nil {
+			return nil, errors.New("RPC.Proxy is not a valid URL")
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+	if tlsConfig != nil || rpcConfig.Proxy != "" {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = proxy
+		transport.TLSClientConfig = tlsConfig
+		options = append(options,
+			rpc.WithHTTPClient(&http.Client{Transport: transport}),
+			rpc.WithWebsocketDialer(websocket.Dialer{
+				Proxy:            proxy,
+				TLSClientConfig:  tlsConfig,
+				HandshakeTimeout: websocket.DefaultDialer.HandshakeTimeout,
+			}),
+		)
+	}
+	return options, nil
+}
+
+// loadJwtSecret reads a hex encoded 32 bytes secret, in the format of geth's --authrpc.jwtsecret file
+func loadJwtSecret(path string) ([32]byte, error) {
+	var secret [32]byte
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return secret, fmt.Errorf("could not read RPC.JwtSecret: %w", err)
+	}
+	decoded := common.FromHex(strings.TrimSpace(string(content)))
+	if len(decoded) != len(secret) {
+		// The content is a secret, it must not end up in the error
+		return secret, fmt.Errorf("RPC.JwtSecret %s must hold a hex encoded 32 bytes secret, got %d bytes", path, len(decoded))
+	}
+	copy(secret[:], decoded)
+	return secret, nil
+}
+
+// loadTLSConfig loads the mTLS client certificate and the CA certificates, nil if none is configured
+func loadTLSConfig(rpcConfig config.RPC) (*tls.Config, error) {
+	if rpcConfig.TlsCert == "" && rpcConfig.TlsCa == "" {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if rpcConfig.TlsCert != "" {
+		certificate, err := tls.LoadX509KeyPair(rpcConfig.TlsCert, rpcConfig.TlsKey)
+		if err != nil {
+			return nil, fmt.Errorf("could not load RPC.TlsCert/TlsKey: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	if rpcConfig.TlsCa != "" {
+		content, err := os.ReadFile(rpcConfig.TlsCa)
+		if err != nil {
+			return nil, fmt.Errorf("could not read RPC.TlsCa: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(content) {
+			return nil, fmt.Errorf("RPC.TlsCa %s holds no PEM certificate", rpcConfig.TlsCa)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
@@ -0,0 +1,202 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"main/src/config"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// chainIdHandler answers eth_chainId with 0x1 and passes the request to inspect
+func chainIdHandler(inspect func(r *http.Request)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inspect(r)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"result":"0x1"}`)
+	})
+}
+
+func dialChainId(t *testing.T, rpcConfig config.RPC, rawURL string) error {
+	t.Helper()
+	dial, err := NewDialFunc(rpcConfig)
+	if err != nil {
+		t.Fatalf("NewDialFunc() unexpected error: %v", err)
+	}
+	client, err := dial(context.Background(), rawURL)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	_, err = client.ChainID(context.Background())
+	return err
+}
+
+func writeFile(t *testing.T, name string, content []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestNewDialFuncHeaders(t *testing.T) {
+	secret := strings.Repeat("ab", 32)
+	tests := []struct {
+		name      string
+		rpcConfig config.RPC
+		check     func(t *testing.T, header http.Header)
+	}{
+		{
+			name:      "custom headers and bearer token",
+			rpcConfig: config.RPC{Headers: map[string]string{"X-Api-Key": "key"}, BearerToken: "token"},
+			check: func(t *testing.T, header http.Header) {
+				if header.Get("X-Api-Key") != "key" || header.Get("Authorization") != "Bearer token" {
+					t.Errorf("headers = %v, want X-Api-Key and the bearer token", header)
+				}
+			},
+		},
+		{
+			name:      "basic auth",
+			rpcConfig: config.RPC{Username: "user", Password: "password"},
+			check: func(t *testing.T, header http.Header) {
+				want := "Basic " + base64.StdEncoding.EncodeToString([]byte("user:password"))
+				if header.Get("Authorization") != want {
+					t.Errorf("Authorization = %q, want %q", header.Get("Authorization"), want)
+				}
+			},
+		},
+		{
+			name:      "JWT signed with the secret",
+			rpcConfig: config.RPC{JwtSecret: writeFile(t, "jwt.hex", []byte("0x"+secret+"\n"))},
+			check: func(t *testing.T, header http.Header) {
+				token, ok := strings.CutPrefix(header.Get("Authorization"), "Bearer ")
+				parts := strings.Split(token, ".")
+				if !ok || len(parts) != 3 {
+					t.Fatalf("Authorization = %q, want a bearer JWT", header.Get("Authorization"))
+				}
+				mac := hmac.New(sha256.New, common.FromHex(secret))
+				mac.Write([]byte(parts[0] + "." + parts[1]))
+				if base64.RawURLEncoding.EncodeToString(mac.Sum(nil)) != parts[2] {
+					t.Error("JWT is not signed with the HS256 secret")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var header http.Header
+			server := httptest.NewServer(chainIdHandler(func(r *http.Request) { header = r.Header.Clone() }))
+			defer server.Close()
+
+			if err := dialChainId(t, tt.rpcConfig, server.URL); err != nil {
+				t.Fatalf("ChainID() unexpected error: %v", err)
+			}
+			tt.check(t, header)
+		})
+	}
+}
+
+func TestNewDialFuncInvalidJwtSecret(t *testing.T) {
+	_, err := NewDialFunc(config.RPC{JwtSecret: writeFile(t, "jwt.hex", []byte("0x1234"))})
+	if err == nil || strings.Contains(err.Error(), "1234") {
+		t.Errorf("NewDialFunc() error = %v, want an error without the secret", err)
+	}
+}
+
+func TestNewDialFuncMutualTLS(t *testing.T) {
+	clientCert, clientKey := selfSignedCertificate(t)
+	clientPool := x509.NewCertPool()
+	parsed, err := x509.ParseCertificate(clientCert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientPool.AddCert(parsed)
+
+	server := httptest.NewUnstartedServer(chainIdHandler(func(r *http.Request) {}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientPool}
+	server.StartTLS()
+	defer server.Close()
+
+	caPath := writeFile(t, "ca.pem", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+	certPath := writeFile(t, "client.pem", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: clientCert}))
+	keyPath := writeFile(t, "client.key", pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: clientKey}))
+
+	tests := []struct {
+		name      string
+		rpcConfig config.RPC
+		wantErr   bool
+	}{
+		{name: "client certificate and CA", rpcConfig: config.RPC{TlsCert: certPath, TlsKey: keyPath, TlsCa: caPath}},
+		{name: "without the client certificate", rpcConfig: config.RPC{TlsCa: caPath}, wantErr: true},
+		{name: "without the CA", rpcConfig: config.RPC{TlsCert: certPath, TlsKey: keyPath}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := dialChainId(t, tt.rpcConfig, server.URL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ChainID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewDialFuncProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(chainIdHandler(func(r *http.Request) { proxied = r.URL.String() }))
+	defer proxy.Close()
+
+	nodeURL := "http://node.invalid:8545/"
+	if err := dialChainId(t, config.RPC{Proxy: proxy.URL}, nodeURL); err != nil {
+		t.Fatalf("ChainID() unexpected error: %v", err)
+	}
+	if proxied != nodeURL {
+		t.Errorf("proxy received %q, want %q", proxied, nodeURL)
+	}
+}
+
+// selfSignedCertificate returns a DER client certificate and its DER EC private key
+func selfSignedCertificate(t *testing.T) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	certificate, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKey, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return certificate, privateKey
+}
@@ -0,0 +1,237 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"log"
+	"main/src/contracts/getter_setter"
+	"main/src/contracts/transparent_proxy"
+	"main/src/evm/clients/geth/backend"
+	"main/src/evm/clients/geth/reverts"
+	"main/src/evm/clients/geth/transactions"
+	"main/src/utils"
+)
+
+// DeployProxyAdmin deploys a ProxyAdmin owned by the signer, the admin of the proxies deployed with it.
+//
+// Parameters:
+// - ctx: the context of the run (context.Context)
+// - auth: the signer options, of the owner (*bind.TransactOpts)
+// - client: the Ethereum client (backend.Client)
+// - timeout: the waiting timeout, in seconds (int)
+// Returns:
+// - common.Address: address of the ProxyAdmin
+func DeployProxyAdmin(ctx context.Context, auth *bind.TransactOpts, client backend.Client, timeout int) common.Address {
+	address, transaction, _, err := transparent_proxy.DeployProxyAdmin(auth, client)
+	if err != nil {
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatalf("Failed to deploy the ProxyAdmin: %v", reverts.Explain("ProxyAdmin deployment", err))
+	}
+
+	log.Printf("Waiting for pending ProxyAdmin deployment with transaction hash: 0x%x, for address: 0x%x", transaction.Hash(), address)
+	deployedAddress := transactions.WaitDeployed(ctx, client, transaction, timeout)
+	log.Printf("ProxyAdmin address: %s, owner: %s", deployedAddress, auth.From)
+	return deployedAddress
+}
+
+// DeployProxy deploys a TransparentUpgradeableProxy delegating to the implementation, administered by the ProxyAdmin.
+//
+// Parameters:
+// - ctx: the context of the run (context.Context)
+// - auth: the signer options (*bind.TransactOpts)
+// - client: the Ethereum client (backend.Client)
+// - implementation: the address of the implementation contract (common.Address)
+// - proxyAdmin: the address of the ProxyAdmin upgrading the proxy (common.Address)
+// - timeout: the waiting timeout, in seconds (int)
+// Returns:
+// - common.Address: address of the proxy
+func DeployProxy(ctx context.Context, auth *bind.TransactOpts, client backend.Client, implementation common.Address, proxyAdmin common.Address, timeout int) common.Address {
+	address, transaction, _, err := transparent_proxy.DeployTransparentUpgradeableProxy(auth, client, implementation, proxyAdmin, nil)
+	if err != nil {
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatalf("Failed to deploy the TransparentUpgradeableProxy: %v", reverts.Explain("proxy deployment", err))
+	}
+
+	log.Printf("Waiting for pending proxy deployment with transaction hash: 0x%x, for proxy address: 0x%x", transaction.Hash(), address)
+	deployedProxyAddress := transactions.WaitDeployed(ctx, client, transaction, timeout)
+	log.Printf("Proxy address: %s, implementation: %s, admin: %s", deployedProxyAddress, implementation, proxyAdmin)
+	return deployedProxyAddress
+}
+
+// ReadProxySlots reads the implementation and the admin from the ERC-1967 storage slots of the proxy.
+//
+// Parameters:
+// - ctx: the context of the run (context.Context)
+// - client: the Ethereum client (backend.Client)
+// - proxy: the address of the proxy (common.Address)
+// Returns:
+// - common.Address: the implementation
+// - common.Address: the admin, a ProxyAdmin
+// - error if the slots can't be read or the address is not an ERC-1967 proxy
+func ReadProxySlots(ctx context.Context, client backend.Client, proxy common.Address) (common.Address, common.Address, error) {
+	implementation, err := client.StorageAt(ctx, proxy, transparent_proxy.ImplementationSlot, nil)
+	if err != nil {
+		return common.Address{}, common.Address{}, fmt.Errorf("failed to read the implementation slot of %s: %w", proxy.Hex(), err)
+	}
+	admin, err := client.StorageAt(ctx, proxy, transparent_proxy.AdminSlot, nil)
+	if err != nil {
+		return common.Address{}, common.Address{}, fmt.Errorf("failed to read the admin slot of %s: %w", proxy.Hex(), err)
+	}
+	implementationAddress := common.BytesToAddress(implementation)
+	if implementationAddress == (common.Address{}) {
+		return common.Address{}, common.Address{}, fmt.Errorf("%s is not an ERC-1967 proxy, its implementation slot is empty", proxy.Hex())
+	}
+	return implementationAddress, common.BytesToAddress(admin), nil
+}
+
+// ReadProxyAdminOwner returns the owner of the ProxyAdmin, the account allowed to upgrade its proxies.
+//
+// Parameters:
+// - ctx: the context of the run (context.Context)
+// - client: the Ethereum client (backend.Client)
+// - proxyAdmin: the address of the ProxyAdmin (common.Address)
+// Returns:
+// - common.Address: the owner
+// - error if the address is not a ProxyAdmin
+func ReadProxyAdminOwner(ctx context.Context, client backend.Client, proxyAdmin common.Address) (common.Address, error) {
+	contract, err := transparent_proxy.NewProxyAdmin(proxyAdmin, client)
+	if err != nil {
+		return common.Address{}, err
+	}
+	owner, err := contract.Owner(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to read the owner of the ProxyAdmin %s: %w", proxyAdmin.Hex(), err)
+	}
+	return owner, nil
+}
+
+// UpgradeProxy points the proxy at the new implementation through its ProxyAdmin, with upgrade, or with upgradeAndCall
+// when data is given: the new implementation is then called with it, in the context of the proxy, e.g. to initialize
+// it. It waits for the transaction to be mined and checks that the proxy emitted Upgraded(newImplementation).
+//
+// Parameters:
+// - ctx: the context of the run (context.Context)
+// - auth: the signer options of the ProxyAdmin owner (*bind.TransactOpts)
+// - client: the Ethereum client (backend.Client)
+// - proxyAdmin: the address of the ProxyAdmin of the proxy (common.Address)
+// - proxy: the address of the proxy (common.Address)
+// - newImplementation: the address of the new implementation (common.Address)
+// - data: the calldata of the call to the new implementation, optional ([]byte)
+// - timeout: the waiting timeout, in seconds (int)
+// Returns:
+// - *types.Receipt: the receipt of the upgrade transaction
+func UpgradeProxy(ctx context.Context, auth *bind.TransactOpts, client backend.Client, proxyAdmin common.Address, proxy common.Address, newImplementation common.Address, data []byte, timeout int) *types.Receipt {
+	contract, err := transparent_proxy.NewProxyAdmin(proxyAdmin, client)
+	if err != nil {
+		utils.Fatalf("Failed to bind the ProxyAdmin %s: %v", proxyAdmin.Hex(), err)
+	}
+	method := "upgrade"
+	var transaction *types.Transaction
+	if len(data) == 0 {
+		transaction, err = contract.Upgrade(auth, proxy, newImplementation)
+	} else {
+		method = "upgradeAndCall"
+		transaction, err = contract.UpgradeAndCall(auth, proxy, newImplementation, data)
+	}
+	if err != nil {
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatalf("Failed to upgrade the proxy %s: %v", proxy.Hex(), reverts.Explain(method, err))
+	}
+	log.Printf("Waiting for transaction for %s: %s\n", method, transaction.Hash().Hex())
+	receipt := transactions.WaitMined(ctx, client, transaction, timeout)
+	if err := reverts.CheckReceipt(ctx, client, method, transaction, receipt); err != nil {
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatalf("Failed to upgrade the proxy %s: %v", proxy.Hex(), err)
+	}
+	if err := CheckUpgradedEvent(receipt, proxy, newImplementation); err != nil {
+		utils.Fatal(err)
+	}
+	return receipt
+}
+
+// CheckUpgradedEvent checks that the receipt is successful and has the Upgraded(newImplementation) event of the proxy
+func CheckUpgradedEvent(receipt *types.Receipt, proxy common.Address, newImplementation common.Address) error {
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return fmt.Errorf("upgrade transaction %s failed, is the sender the ProxyAdmin owner and does the new implementation have code?", receipt.TxHash.Hex())
+	}
+	filterer, err := transparent_proxy.NewTransparentUpgradeableProxyFilterer(proxy, nil)
+	if err != nil {
+		return err
+	}
+	for _, event := range receipt.Logs {
+		if event.Address != proxy {
+			continue
+		}
+		if upgraded, err := filterer.ParseUpgraded(*event); err == nil && upgraded.Implementation == newImplementation {
+			return nil
+		}
+	}
+	return fmt.Errorf("upgrade transaction %s emitted no Upgraded(%s) event", receipt.TxHash.Hex(), newImplementation.Hex())
+}
+
+// ConnectSimulatedProxyClient starts an in-process simulated chain, like ConnectSimulatedClient, with a GetterSetter
+// behind a TransparentUpgradeableProxy at the proxy address, administered by a ProxyAdmin owned by the funded account,
+// so the "upgrade-contract" mode works without deploying first. The implementation and the ProxyAdmin are placed at the
+// addresses the proxy would create with its first two nonces, so they are predictable.
+//
+// Parameters:
+// - fundedAddress: the address to pre-fund, the ProxyAdmin owner (common.Address)
+// - proxyAddress: the address to seed with the proxy (string)
+// Returns:
+// - backend.Client
+func ConnectSimulatedProxyClient(fundedAddress common.Address, proxyAddress string) backend.Client {
+	log.Println("Starting the simulated client...")
+	proxy := common.HexToAddress(proxyAddress)
+	implementation := crypto.CreateAddress(proxy, 0)
+	proxyAdmin := crypto.CreateAddress(proxy, 1)
+	alloc := simulatedGenesis(fundedAddress)
+	alloc[implementation] = types.Account{Code: SimulateRuntimeCode(common.FromHex(getter_setter.GetterSetterMetaData.Bin))}
+	alloc[proxyAdmin] = types.Account{
+		Code:    transparent_proxy.ProxyAdminRuntimeCode(),
+		Storage: map[common.Hash]common.Hash{transparent_proxy.OwnerSlot: common.BytesToHash(fundedAddress.Bytes())},
+	}
+	alloc[proxy] = types.Account{
+		Code: transparent_proxy.TransparentUpgradeableProxyRuntimeCode(),
+		Storage: map[common.Hash]common.Hash{
+			transparent_proxy.ImplementationSlot: common.BytesToHash(implementation.Bytes()),
+			transparent_proxy.AdminSlot:          common.BytesToHash(proxyAdmin.Bytes()),
+		},
+	}
+	log.Printf("Simulated GetterSetter proxy is available at: %s, implementation: %s, ProxyAdmin: %s", proxy.Hex(), implementation.Hex(), proxyAdmin.Hex())
+	client := backend.NewSimulatedClient(alloc)
+	log.Println("Simulated client started, account pre-funded:", fundedAddress)
+	return client
+}
+
+// ConnectSimulatedProxyDeploymentClient starts an in-process simulated chain, like ConnectSimulatedClient, where the
+// proxy deployment can be simulated before the implementation and the ProxyAdmin it points to are mined: a GetterSetter
+// and a ProxyAdmin owned by the funded account are seeded at their addresses, and the funded account starts at the
+// nonce of the proxy deployment, so the proxy is created where it will be on-chain.
+//
+// Parameters:
+// - fundedAddress: the address to pre-fund, the deployer (common.Address)
+// - nonce: the nonce of the proxy deployment (uint64)
+// - implementation: the address to seed with the GetterSetter (common.Address)
+// - proxyAdmin: the address to seed with the ProxyAdmin (common.Address)
+// Returns:
+// - backend.Client
+func ConnectSimulatedProxyDeploymentClient(fundedAddress common.Address, nonce uint64, implementation common.Address, proxyAdmin common.Address) backend.Client {
+	log.Println("Starting the simulated client...")
+	alloc := simulatedGenesis(fundedAddress)
+	funded := alloc[fundedAddress]
+	funded.Nonce = nonce
+	alloc[fundedAddress] = funded
+	alloc[implementation] = types.Account{Code: SimulateRuntimeCode(common.FromHex(getter_setter.GetterSetterMetaData.Bin))}
+	alloc[proxyAdmin] = types.Account{
+		Code:    transparent_proxy.ProxyAdminRuntimeCode(),
+		Storage: map[common.Hash]common.Hash{transparent_proxy.OwnerSlot: common.BytesToHash(fundedAddress.Bytes())},
+	}
+	log.Printf("Simulated GetterSetter is available at: %s, ProxyAdmin: %s", implementation.Hex(), proxyAdmin.Hex())
+	client := backend.NewSimulatedClient(alloc)
+	log.Println("Simulated client started, account pre-funded:", fundedAddress)
+	return client
+}
@@ -0,0 +1,156 @@
+package client
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"main/src/contracts/getter_setter"
+	"main/src/contracts/transparent_proxy"
+	"main/src/evm/clients/geth/backend"
+	"math/big"
+	"testing"
+)
+
+func TestDeployAndUpgradeProxy(t *testing.T) {
+	ctx := context.Background()
+	key, err := crypto.HexToECDSA("ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner := crypto.PubkeyToAddress(key.PublicKey)
+	other := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	simulatedClient := backend.NewSimulatedClient(types.GenesisAlloc{
+		owner: {Balance: new(big.Int).Mul(big.NewInt(10), big.NewInt(params.Ether))},
+	})
+	t.Cleanup(func() { simulatedClient.Close() })
+	signer := func() *bind.TransactOpts {
+		auth := GetTransactor(key, big.NewInt(1337))
+		auth.GasLimit = 3000000
+		return auth
+	}
+
+	implementation := DeployContract(ctx, signer(), simulatedClient, 0)
+	proxyAdmin := DeployProxyAdmin(ctx, signer(), simulatedClient, 0)
+	proxy := DeployProxy(ctx, signer(), simulatedClient, implementation, proxyAdmin, 0)
+	gotImplementation, gotAdmin, err := ReadProxySlots(ctx, simulatedClient, proxy)
+	if err != nil || gotImplementation != implementation || gotAdmin != proxyAdmin {
+		t.Fatalf("ReadProxySlots() = (%s, %s, %v), want (%s, %s)", gotImplementation.Hex(), gotAdmin.Hex(), err, implementation.Hex(), proxyAdmin.Hex())
+	}
+	if _, _, err := ReadProxySlots(ctx, simulatedClient, implementation); err == nil {
+		t.Error("ReadProxySlots() of the implementation succeeded, want an error")
+	}
+	if gotOwner, err := ReadProxyAdminOwner(ctx, simulatedClient, proxyAdmin); err != nil || gotOwner != owner {
+		t.Errorf("ReadProxyAdminOwner() = (%s, %v), want %s", gotOwner.Hex(), err, owner.Hex())
+	}
+	if _, err := ReadProxyAdminOwner(ctx, simulatedClient, other); err == nil {
+		t.Error("ReadProxyAdminOwner() of an account without code succeeded, want an error")
+	}
+
+	// The binding works through the proxy, the owner of the ProxyAdmin is not the proxy's admin
+	contract, err := getter_setter.NewGetterSetter(proxy, simulatedClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := contract.SetUint256(signer(), big.NewInt(42)); err != nil {
+		t.Fatalf("SetUint256() through the proxy error = %v", err)
+	}
+
+	adminABI, err := transparent_proxy.ProxyAdminMetaData.GetAbi()
+	if err != nil {
+		t.Fatal(err)
+	}
+	upgrade := func(newImplementation common.Address) []byte {
+		data, err := adminABI.Pack("upgrade", proxy, newImplementation)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+	newImplementation := DeployContract(ctx, signer(), simulatedClient, 0)
+	// Only the owner of the ProxyAdmin can upgrade, only to a contract, and the proxy only takes upgrades from its admin
+	if _, err := simulatedClient.CallContract(ctx, ethereum.CallMsg{From: other, To: &proxyAdmin, Data: upgrade(newImplementation)}, nil); err == nil {
+		t.Error("upgrade from another account succeeded, want a revert")
+	}
+	if _, err := simulatedClient.CallContract(ctx, ethereum.CallMsg{From: owner, To: &proxyAdmin, Data: upgrade(other)}, nil); err == nil {
+		t.Error("upgrade to an account without code succeeded, want a revert")
+	}
+	proxyABI, err := transparent_proxy.TransparentUpgradeableProxyMetaData.GetAbi()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := proxyABI.Methods["upgradeTo"]; ok {
+		t.Error("upgradeTo is in the proxy's ABI, want it only dispatched for the admin")
+	}
+	upgradeTo := append(crypto.Keccak256([]byte("upgradeTo(address)"))[:4], common.LeftPadBytes(newImplementation.Bytes(), 32)...)
+	if _, err := simulatedClient.CallContract(ctx, ethereum.CallMsg{From: owner, To: &proxy, Data: upgradeTo}, nil); err == nil {
+		t.Error("upgradeTo sent to the proxy by the ProxyAdmin owner succeeded, want it delegated to the implementation and reverted")
+	}
+
+	receipt := UpgradeProxy(ctx, signer(), simulatedClient, proxyAdmin, proxy, newImplementation, nil, 0)
+	if err := CheckUpgradedEvent(receipt, proxy, implementation); err == nil {
+		t.Error("CheckUpgradedEvent() found the previous implementation in the Upgraded event")
+	}
+	if gotImplementation, _, _ := ReadProxySlots(ctx, simulatedClient, proxy); gotImplementation != newImplementation {
+		t.Errorf("implementation after the upgrade = %s, want %s", gotImplementation.Hex(), newImplementation.Hex())
+	}
+	value, err := contract.GetUint256(&bind.CallOpts{Context: ctx})
+	if err != nil || value.Cmp(big.NewInt(42)) != 0 {
+		t.Errorf("GetUint256() after the upgrade = (%v, %v), want 42", value, err)
+	}
+
+	// upgradeAndCall calls the new implementation in the context of the proxy
+	getterSetterABI, err := getter_setter.GetterSetterMetaData.GetAbi()
+	if err != nil {
+		t.Fatal(err)
+	}
+	setUint256, err := getterSetterABI.Pack("setUint256", big.NewInt(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	UpgradeProxy(ctx, signer(), simulatedClient, proxyAdmin, proxy, implementation, setUint256, 0)
+	if gotImplementation, _, _ := ReadProxySlots(ctx, simulatedClient, proxy); gotImplementation != implementation {
+		t.Errorf("implementation after upgradeAndCall = %s, want %s", gotImplementation.Hex(), implementation.Hex())
+	}
+	value, err = contract.GetUint256(&bind.CallOpts{Context: ctx})
+	if err != nil || value.Cmp(big.NewInt(7)) != 0 {
+		t.Errorf("GetUint256() after upgradeAndCall = (%v, %v), want 7", value, err)
+	}
+}
+
+func TestConnectSimulatedProxyClient(t *testing.T) {
+	ctx := context.Background()
+	owner := common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	proxy := common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3")
+	simulatedClient := ConnectSimulatedProxyClient(owner, proxy.Hex())
+	t.Cleanup(func() { simulatedClient.(*backend.SimulatedClient).Close() })
+
+	implementation, proxyAdmin, err := ReadProxySlots(ctx, simulatedClient, proxy)
+	if err != nil {
+		t.Fatalf("ReadProxySlots() error = %v", err)
+	}
+	if gotOwner, err := ReadProxyAdminOwner(ctx, simulatedClient, proxyAdmin); err != nil || gotOwner != owner {
+		t.Errorf("ReadProxyAdminOwner() = (%s, %v), want %s", gotOwner.Hex(), err, owner.Hex())
+	}
+	// The seeded ProxyAdmin is the proxy's admin
+	admin, err := transparent_proxy.NewProxyAdmin(proxyAdmin, simulatedClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := admin.GetProxyImplementation(&bind.CallOpts{Context: ctx}, proxy); err != nil || got != implementation {
+		t.Errorf("GetProxyImplementation() = (%s, %v), want %s", got.Hex(), err, implementation.Hex())
+	}
+	if err := CheckContractCode(ctx, proxy.Hex(), simulatedClient); err != nil {
+		t.Errorf("CheckContractCode() error = %v", err)
+	}
+	contract, err := getter_setter.NewGetterSetter(proxy, simulatedClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ProbeGetterSetter(ctx, contract); err != nil {
+		t.Errorf("ProbeGetterSetter() through the proxy error = %v", err)
+	}
+}
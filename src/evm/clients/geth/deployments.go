@@ -0,0 +1,136 @@
+package geth
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"log"
+	"main/src/config"
+	"main/src/evm/clients/geth/journal"
+	"main/src/evm/clients/geth/registry"
+	"main/src/evm/clients/geth/transactions"
+	"main/src/utils"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// getterSetterContractName is the contract name of the GetterSetter deployments in the registry
+const getterSetterContractName = "GetterSetter"
+
+// artifactContractName returns the contract name of an artifact, i.e. its file name without extension
+func artifactContractName(artifactConfig config.Artifact) string {
+	path := artifactConfig.Path
+	if path == "" {
+		path = artifactConfig.Bin
+	}
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
+
+// registryContractName returns the contract name the mode looks up in the registry
+func registryContractName(mode string, artifactConfig config.Artifact) string {
+	// The verify mode defaults to GetterSetter too when no artifact is configured
+	if utils.UsesExistingGetterSetter(mode) || (artifactConfig.Path == "" && artifactConfig.Bin == "") {
+		return getterSetterContractName
+	}
+	return artifactContractName(artifactConfig)
+}
+
+func loadRegistry() *registry.Registry {
+	deploymentRegistry, err := registry.Load(tomlConfig.Contract.Registry)
+	if err != nil {
+		utils.Fatalf("Failed to load the deployment registry: %v", err)
+	}
+	return deploymentRegistry
+}
+
+// resolveContractAddress sets the contract address, when Contract.Address is empty, to the deployment
+// recorded in the registry for the connected chain: the latest one with Contract.Alias, if set,
+// or else the latest one of the contract used by the mode. The upgrade mode only resolves the
+// deployments behind a proxy.
+func resolveContractAddress(ctx context.Context) {
+	chainID := transactions.GetChainId(ctx, ethClient).Uint64()
+	contractName := registryContractName(mode, tomlConfig.Contract.Artifact)
+	deployment, err := loadRegistry().Resolve(chainID, tomlConfig.Contract.Alias, contractName, mode == utils.UPGRADE_MODE)
+	if err != nil {
+		utils.Fatalf("Contract.Address is not set and could not be resolved: %v", err)
+	}
+	log.Printf("Contract.Address is not set, using the %s deployment at %s (alias: '%s', block: %d, deployed: %s)",
+		deployment.Contract, deployment.Address, deployment.Alias, deployment.BlockNumber, deployment.Timestamp.Format(time.RFC3339))
+	contractAddress = deployment.Address
+	tomlConfig.Contract.Address = deployment.Address
+}
+
+// recordDeployment records the contract deployed by the journal step in the deployment registry,
+// under Contract.Alias, so the later runs can use it without setting Contract.Address. A contract
+// the CREATE2 deployment found already deployed is recorded too: as it was recorded before, if it was,
+// or else without a deployment transaction, in the block it was found in.
+//
+// Parameters:
+// - ctx: the context of the run (context.Context)
+// - stepName: the journal step of the deployment (string)
+// - contractName: the name of the deployed contract (string)
+// - address: the address of the deployed contract (common.Address)
+func recordDeployment(ctx context.Context, stepName string, contractName string, address common.Address) {
+	if rpcURL == utils.SIMULATED_RPC_URL {
+		log.Println("The deployment is not recorded in the registry, the simulated chain is lost when the application exits")
+		return
+	}
+	if runJournal == nil {
+		return
+	}
+	chainID := transactions.GetChainId(ctx, ethClient).Uint64()
+	deploymentRegistry := loadRegistry()
+	// The CREATE2 deployment is the one of GetterSetter, the proxy is deployed with CREATE
+	create2 := contractName == getterSetterContractName && tomlConfig.Contract.Create2 && !tomlConfig.Contract.Proxy
+	deployment := registry.Deployment{
+		Contract: contractName,
+		Address:  address.Hex(),
+		Proxy:    contractName == getterSetterContractName && tomlConfig.Contract.Proxy,
+	}
+	step, ok := runJournal.Step(stepName)
+	switch {
+	case !ok && create2:
+		if recorded, found := deploymentRegistry.Find(chainID, address.Hex()); found {
+			deployment = recorded
+		} else {
+			blockNumber, err := ethClient.BlockNumber(ctx)
+			if err != nil {
+				transactions.ExitIfInterrupted(ctx)
+				utils.Fatalf("Failed to get the block number: %v", err)
+			}
+			deployment.BlockNumber = blockNumber
+		}
+	case !ok:
+		log.Printf("The deployment of %s sent no transaction, nothing to record in the registry", contractName)
+		return
+	case step.Status != journal.StatusMined:
+		log.Printf("WARNING: The deployment of %s is not recorded in the registry, its receipt is unknown", contractName)
+		return
+	default:
+		deployment.Deployer = deployerAddress.Hex()
+		deployment.TransactionHash = step.TransactionHash
+		deployment.BlockNumber = step.BlockNumber
+	}
+	if tomlConfig.Contract.Alias != "" {
+		deployment.Alias = tomlConfig.Contract.Alias
+	}
+
+	code, err := ethClient.CodeAt(ctx, address, nil)
+	if err != nil {
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatalf("Failed to get the code at %s: %v", address.Hex(), err)
+	}
+	header, err := ethClient.HeaderByNumber(ctx, new(big.Int).SetUint64(deployment.BlockNumber))
+	if err != nil {
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatalf("Failed to get the block %d of the deployment: %v", deployment.BlockNumber, err)
+	}
+	deployment.BytecodeHash = crypto.Keccak256Hash(code).Hex()
+	deployment.Timestamp = time.Unix(int64(header.Time), 0).UTC()
+	if err := deploymentRegistry.Add(chainID, deployment); err != nil {
+		utils.Fatalf("Failed to record the deployment: %v", err)
+	}
+	log.Printf("Deployment of %s recorded in %s for chain %d (alias: '%s')", contractName, tomlConfig.Contract.Registry, chainID, deployment.Alias)
+}
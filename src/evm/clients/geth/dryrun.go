@@ -0,0 +1,205 @@
+package geth
+
+import (
+	"context"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"io"
+	"log"
+	"main/src/contracts/getter_setter"
+	"main/src/contracts/transparent_proxy"
+	"main/src/evm/clients/geth/backend"
+	"main/src/evm/clients/geth/client"
+	"main/src/evm/clients/geth/reverts"
+	"main/src/evm/clients/geth/transactions"
+	"main/src/evm/clients/geth/types"
+	"main/src/utils"
+	"math/big"
+)
+
+// dryRun is set by the -dry-run flag: the transactions are signed, but not broadcast
+var dryRun bool
+
+// simulate runs the transaction with eth_call, with the same sender, calldata, value and gas limit
+func simulate(ctx context.Context, ethClient backend.Client, from common.Address, transaction *gethtypes.Transaction) error {
+	call := ethereum.CallMsg{From: from, To: transaction.To(), Gas: transaction.Gas(), Value: transaction.Value(), Data: transaction.Data()}
+	_, err := ethClient.CallContract(ctx, call, nil)
+	return err
+}
+
+// simulateBeforeSending wraps the signer, so each transaction is simulated with eth_call before it is signed and sent.
+// A transaction failing the simulation is not sent, the signing error stops the run.
+func simulateBeforeSending(ctx context.Context, ethClient backend.Client, signer bind.SignerFn) bind.SignerFn {
+	return func(from common.Address, transaction *gethtypes.Transaction) (*gethtypes.Transaction, error) {
+		if err := simulate(ctx, ethClient, from, transaction); err != nil {
+			return nil, fmt.Errorf("the eth_call simulation failed, the transaction is not sent: %w", reverts.Explain("simulation", err))
+		}
+		return signer(from, transaction)
+	}
+}
+
+// DryRun signs the transactions the deploy-contract, call-contract and demo modes would send, with the
+// same senders, nonces, gas limit and gas price, without broadcasting them. Each one is simulated with
+// eth_call and its gas estimated. The setters of a contract deployed by the run itself are simulated
+// against a throw-away simulated chain, where a GetterSetter is seeded at the predicted address.
+//
+// Parameters:
+// - ctx: the context of the run (context.Context)
+// Return type:
+// - types.DryRunInformation
+func DryRun(ctx context.Context) types.DryRunInformation {
+	if mode != utils.DEPLOY_MODE && mode != utils.CALL_MODE && mode != utils.DEMO_MODE {
+		utils.Fatalf("-dry-run supports the modes: %s, %s, %s", utils.DEPLOY_MODE, utils.CALL_MODE, utils.DEMO_MODE)
+	}
+	getterSetterABI, err := getter_setter.GetterSetterMetaData.GetAbi()
+	if err != nil {
+		utils.Fatalf("Failed to parse the GetterSetter ABI: %v", err)
+	}
+	output := types.DryRunInformation{Mode: mode, TotalEstimatedCost: big.NewInt(0)}
+	record := func(step types.DryRunTransaction) {
+		output.Transactions = append(output.Transactions, step)
+		output.TotalEstimatedCost.Add(output.TotalEstimatedCost, step.EstimatedCost)
+	}
+
+	// The signer options of each sender, their nonce is incremented with each signed transaction
+	signers := map[common.Address]*bind.TransactOpts{}
+	signerOf := func(from sender) *bind.TransactOpts {
+		if auth, ok := signers[from.address]; ok {
+			return auth
+		}
+		auth := GetSigner(ctx, ethClient, from.address, from.privateKey)
+		auth.NoSend = true
+		signers[from.address] = auth
+		return auth
+	}
+	deployer := signerOf(sender{address: deployerAddress, privateKey: privateKey})
+
+	target := common.HexToAddress(contractAddress)
+	if mode == utils.DEPLOY_MODE || mode == utils.DEMO_MODE {
+		target = dryRunDeployment(ctx, deployer, record)
+	}
+	if mode == utils.DEPLOY_MODE {
+		return output
+	}
+
+	simulationClient := ethClient
+	if mode == utils.DEMO_MODE {
+		scratch := client.ConnectSimulatedClient(deployerAddress, target.Hex())
+		defer scratch.(io.Closer).Close()
+		simulationClient = scratch
+	}
+	senders := setterSenders()
+	for i, data := range setterCalldata(getterSetterABI, values) {
+		method, err := getterSetterABI.MethodById(data[:4])
+		if err != nil {
+			utils.Fatalf("Failed to find the setter of %x: %v", data[:4], err)
+		}
+		auth := signerOf(senders[i%len(senders)])
+		transaction, err := bind.NewBoundContract(target, *getterSetterABI, ethClient, ethClient, ethClient).RawTransact(auth, data)
+		if err != nil {
+			transactions.ExitIfInterrupted(ctx)
+			utils.Fatalf("Failed to sign %s: %v", method.Name, err)
+		}
+		auth.Nonce = new(big.Int).Add(auth.Nonce, big.NewInt(1))
+		record(dryRunStep(ctx, method.Name, auth.From, transaction, simulationClient))
+	}
+	return output
+}
+
+// dryRunDeployment signs the GetterSetter deployment, behind a proxy or through CREATE2 if configured,
+// and returns the address of the contract the setters are sent to
+func dryRunDeployment(ctx context.Context, auth *bind.TransactOpts, record func(types.DryRunTransaction)) common.Address {
+	deploy := func(name string, initCode []byte, simulationClient backend.Client) common.Address {
+		address := crypto.CreateAddress(auth.From, auth.Nonce.Uint64())
+		_, transaction, _, err := bind.DeployContract(auth, abi.ABI{}, initCode, ethClient)
+		if err != nil {
+			transactions.ExitIfInterrupted(ctx)
+			utils.Fatalf("Failed to sign the %s step: %v", name, err)
+		}
+		auth.Nonce = new(big.Int).Add(auth.Nonce, big.NewInt(1))
+		step := dryRunStep(ctx, name, auth.From, transaction, simulationClient)
+		step.ContractAddress = address.Hex()
+		record(step)
+		return address
+	}
+	getterSetterCode := common.FromHex(getter_setter.GetterSetterMetaData.Bin)
+
+	if tomlConfig.Contract.Proxy {
+		implementation := deploy("deploy-implementation", getterSetterCode, ethClient)
+		proxyAdmin := deploy("deploy-proxy-admin", common.FromHex(transparent_proxy.ProxyAdminMetaData.Bin), ethClient)
+		// The proxy constructor checks that the implementation has code, it is simulated on a throw-away
+		// simulated chain, where the implementation and the ProxyAdmin are seeded at their predicted addresses
+		scratch := client.ConnectSimulatedProxyDeploymentClient(auth.From, auth.Nonce.Uint64(), implementation, proxyAdmin)
+		defer scratch.(io.Closer).Close()
+		return deploy("deploy", proxyDeployment(auth.From, implementation, proxyAdmin).Data, scratch)
+	}
+	if !tomlConfig.Contract.Create2 {
+		return deploy("deploy", getterSetterCode, ethClient)
+	}
+
+	salt, err := utils.ParseSalt(tomlConfig.Contract.Salt)
+	if err != nil {
+		utils.Fatalf("Contract.Salt is invalid: %v", err)
+	}
+	address := client.Create2Address(salt, getterSetterCode)
+	if code, err := ethClient.CodeAt(ctx, address, nil); err == nil && len(code) > 0 {
+		log.Printf("Contract address: %s, already deployed with salt %s, the deployment would be skipped", address.Hex(), salt.Hex())
+		return address
+	}
+	if code, err := ethClient.CodeAt(ctx, client.DeterministicDeployerAddress, nil); err == nil && len(code) == 0 {
+		log.Printf("WARNING: The deterministic deployment proxy is not deployed at %s, the run would deploy it first on a development chain", client.DeterministicDeployerAddress.Hex())
+	}
+	transaction, err := bind.NewBoundContract(client.DeterministicDeployerAddress, abi.ABI{}, ethClient, ethClient, ethClient).RawTransact(auth, append(salt.Bytes(), getterSetterCode...))
+	if err != nil {
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatalf("Failed to sign the deploy step: %v", err)
+	}
+	auth.Nonce = new(big.Int).Add(auth.Nonce, big.NewInt(1))
+	step := dryRunStep(ctx, "deploy", auth.From, transaction, ethClient)
+	step.ContractAddress = address.Hex()
+	record(step)
+	return address
+}
+
+// dryRunStep simulates the signed transaction, estimates its gas and logs it
+func dryRunStep(ctx context.Context, name string, from common.Address, transaction *gethtypes.Transaction, simulationClient backend.Client) types.DryRunTransaction {
+	simulation := "success"
+	if err := simulate(ctx, simulationClient, from, transaction); err != nil {
+		transactions.ExitIfInterrupted(ctx)
+		simulation = reverts.Explain(name, err).Error()
+	}
+	call := ethereum.CallMsg{From: from, To: transaction.To(), Value: transaction.Value(), Data: transaction.Data()}
+	estimatedGas := estimateGas(ctx, simulationClient, call, transaction.Gas())
+	raw, err := transaction.MarshalBinary()
+	if err != nil {
+		utils.Fatalf("Failed to encode the %s transaction: %v", name, err)
+	}
+
+	step := types.DryRunTransaction{
+		Step:            name,
+		From:            from.Hex(),
+		Nonce:           transaction.Nonce(),
+		Value:           transaction.Value(),
+		GasLimit:        transaction.Gas(),
+		GasPrice:        transaction.GasPrice(),
+		EstimatedGas:    estimatedGas,
+		EstimatedCost:   new(big.Int).Add(new(big.Int).Mul(new(big.Int).SetUint64(estimatedGas), transaction.GasPrice()), transaction.Value()),
+		MaxCost:         transaction.Cost(),
+		Simulation:      simulation,
+		TransactionHash: transaction.Hash().Hex(),
+		RawTransaction:  hexutil.Encode(raw),
+	}
+	if transaction.To() != nil {
+		step.To = transaction.To().Hex()
+	}
+	log.Printf("Dry run of %s: from %s, nonce %d, gas limit %d, estimated gas %d, estimated cost %s ETH (max %s ETH), simulation: %s",
+		name, step.From, step.Nonce, step.GasLimit, step.EstimatedGas, utils.WeiToEther(step.EstimatedCost).String(), utils.WeiToEther(step.MaxCost).String(), simulation)
+	log.Printf("Signed transaction of %s: %s", name, step.RawTransaction)
+	return step
+}
@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+func TestEthereumDTOBuilder(t *testing.T) {
+	tests := []struct {
+		name        string
+		uint256     *big.Int
+		bytes32     string
+		bytes       []byte
+		wantBytes32 [32]byte
+	}{
+		{
+			name:        "all values",
+			uint256:     big.NewInt(42),
+			bytes32:     "Test",
+			bytes:       []byte("Test"),
+			wantBytes32: [32]byte{28: 'T', 29: 'e', 30: 's', 31: 't'},
+		},
+		{
+			name: "no values",
+		},
+		{
+			name:        "bytes32 longer than 32 bytes keeps the last 32 bytes",
+			bytes32:     "0123456789abcdef0123456789abcdefXYZ",
+			wantBytes32: [32]byte([]byte("3456789abcdef0123456789abcdefXYZ")),
+		},
+		{
+			name:    "zero uint256",
+			uint256: big.NewInt(0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewEthereumDTOBuilder().
+				SetUint256(tt.uint256).
+				SetBytes32(tt.bytes32).
+				SetBytes(tt.bytes).
+				Build()
+			if err != nil {
+				t.Fatalf("Build() unexpected error: %v", err)
+			}
+			if got.Uint256 != tt.uint256 {
+				t.Errorf("Uint256 = %v, want %v", got.Uint256, tt.uint256)
+			}
+			if got.Bytes32 != tt.wantBytes32 {
+				t.Errorf("Bytes32 = %x, want %x", got.Bytes32, tt.wantBytes32)
+			}
+			if !bytes.Equal(got.Bytes, tt.bytes) {
+				t.Errorf("Bytes = %x, want %x", got.Bytes, tt.bytes)
+			}
+		})
+	}
+}
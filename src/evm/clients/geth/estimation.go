@@ -0,0 +1,293 @@
+package geth
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+	"io"
+	"log"
+	"main/src/config"
+	"main/src/contracts/getter_setter"
+	"main/src/contracts/transparent_proxy"
+	"main/src/evm/clients/geth/artifacts"
+	"main/src/evm/clients/geth/backend"
+	"main/src/evm/clients/geth/client"
+	"main/src/evm/clients/geth/dto"
+	"main/src/evm/clients/geth/journal"
+	"main/src/evm/clients/geth/transactions"
+	"main/src/utils"
+	"math/big"
+)
+
+// EstimateRunCost estimates the total cost, in wei, of the transactions the configured mode is going to send:
+// the deployment and/or each configured setter (or method call), multiplied by the gas price used by the signer,
+// plus the ETH the "fund" mode transfers.
+// Gas is estimated with eth_estimateGas. In demo mode the contract doesn't exist yet, so the setters are estimated
+// against a GetterSetter on a throw-away simulated chain. Client.GasLimit is used when an estimation fails.
+// The steps recorded in the journal of a resumed run are not sent again, so they are not estimated, nor is
+// a CREATE2 deployment of a contract already deployed.
+//
+// Parameters:
+// - ctx: the context of the run (context.Context)
+// - c: the application configuration (config.Config)
+// - fromAddress: the address sending the transactions (common.Address)
+// - ethClient: the Ethereum client for interaction (backend.Client)
+// - recorded: the journal of the run, nil if there is none (*journal.Journal)
+// Returns:
+// - the estimated cost, in wei (*big.Int)
+func EstimateRunCost(ctx context.Context, c config.Config, fromAddress common.Address, ethClient backend.Client, recorded *journal.Journal) *big.Int {
+	getterSetterABI, err := getter_setter.GetterSetterMetaData.GetAbi()
+	if err != nil {
+		utils.Fatalf("Failed to parse the GetterSetter ABI: %v", err)
+	}
+	deployment := ethereum.CallMsg{From: fromAddress, Data: common.FromHex(getter_setter.GetterSetterMetaData.Bin)}
+
+	var gasEstimates []uint64
+	value := big.NewInt(0) // ETH sent along with the transactions
+	journaled := func(step string) bool {
+		if recorded == nil {
+			return false
+		}
+		_, ok := recorded.Step(step)
+		if ok {
+			log.Printf("Step %s is recorded in the journal, it is not estimated", step)
+		}
+		return ok
+	}
+	estimate := func(step string, estimationClient backend.Client, call ethereum.CallMsg) {
+		if !journaled(step) {
+			gasEstimates = append(gasEstimates, estimateGas(ctx, estimationClient, call, c.Client.GasLimit))
+		}
+	}
+	estimateSetters := func(estimationClient backend.Client, contractAddress common.Address) {
+		for _, data := range setterCalldata(getterSetterABI, c.Contract.Values) {
+			method, err := getterSetterABI.MethodById(data[:4])
+			if err != nil {
+				utils.Fatalf("Failed to find the setter of %x: %v", data[:4], err)
+			}
+			estimate(method.Name, estimationClient, ethereum.CallMsg{From: fromAddress, To: &contractAddress, Data: data})
+		}
+	}
+	estimateDeployment := func() {
+		switch {
+		case c.Contract.Proxy:
+			estimate("deploy-implementation", ethClient, deployment)
+			estimate("deploy-proxy-admin", ethClient, ethereum.CallMsg{From: fromAddress, Data: common.FromHex(transparent_proxy.ProxyAdminMetaData.Bin)})
+			// The proxy constructor checks that the implementation has code, it isn't deployed yet: the proxy
+			// deployment is estimated on a throw-away simulated chain, with a GetterSetter as implementation
+			scratchAddress := common.HexToAddress("0x00000000000000000000000000000000000000ff")
+			scratch := client.ConnectSimulatedClient(fromAddress, scratchAddress.Hex())
+			defer scratch.(io.Closer).Close()
+			estimate("deploy", scratch, proxyDeployment(fromAddress, scratchAddress, fromAddress))
+		case c.Contract.Create2:
+			if journaled("deploy") {
+				return
+			}
+			gas, funding := estimateCreate2Deployment(ctx, c, fromAddress, ethClient)
+			gasEstimates = append(gasEstimates, gas...)
+			value.Add(value, funding)
+		default:
+			estimate("deploy", ethClient, deployment)
+		}
+	}
+
+	switch c.Contract.Mode {
+	case utils.DEPLOY_MODE:
+		estimateDeployment()
+
+	case utils.UPGRADE_MODE:
+		proxyAddress := common.HexToAddress(c.Contract.Address)
+		estimateSetters(ethClient, proxyAddress)
+		// The new implementation doesn't exist yet, the upgrade is estimated with the current one
+		newImplementation := common.HexToAddress(c.Contract.Implementation)
+		if c.Contract.Implementation == "" {
+			estimate("deploy-implementation", ethClient, deployment)
+			newImplementation, _, _ = client.ReadProxySlots(ctx, ethClient, proxyAddress)
+		}
+		_, proxyAdmin, _ := client.ReadProxySlots(ctx, ethClient, proxyAddress)
+		estimate("upgrade", ethClient, ethereum.CallMsg{From: fromAddress, To: &proxyAdmin, Data: upgradeCalldata(proxyAddress, newImplementation)})
+
+	case utils.CALL_MODE:
+		estimateSetters(ethClient, common.HexToAddress(c.Contract.Address))
+
+	case utils.LOAD_MODE:
+		// The transactions are sent from Account.Key and the Load.Keys accounts in turn, only the share of
+		// Account.Key is estimated here, with the largest value the load test sets
+		contractAddress := common.HexToAddress(c.Contract.Address)
+		data, err := getterSetterABI.Pack("setUint256", big.NewInt(int64(c.Load.Transactions)))
+		if err != nil {
+			utils.Fatalf("Failed to pack the arguments of setUint256: %v", err)
+		}
+		call := ethereum.CallMsg{From: fromAddress, To: &contractAddress, Data: data}
+		gas := estimateGas(ctx, ethClient, call, c.Client.GasLimit)
+		accounts := 1 + len(c.Load.Keys)
+		if c.Account.DerivedSenders {
+			accounts += c.Account.Derived
+		}
+		for i := 0; i < (c.Load.Transactions+accounts-1)/accounts; i++ {
+			gasEstimates = append(gasEstimates, gas)
+		}
+
+	case utils.FUND_MODE:
+		// The top-ups are paid along with the transfer fees
+		target, _ := utils.EtherToWei(c.Account.Funding)
+		for _, derived := range deriveAccounts(c.Account) {
+			if balance := balanceOf(ctx, ethClient, derived.address); derived.address != fromAddress && balance.Cmp(target) < 0 {
+				value.Add(value, new(big.Int).Sub(target, balance))
+				gasEstimates = append(gasEstimates, params.TxGas)
+			}
+		}
+
+	case utils.DEMO_MODE:
+		estimateDeployment()
+		scratchAddress := common.HexToAddress("0x00000000000000000000000000000000000000ff")
+		scratch := client.ConnectSimulatedClient(fromAddress, scratchAddress.Hex())
+		defer scratch.(io.Closer).Close()
+		estimateSetters(scratch, scratchAddress)
+
+	case utils.ARTIFACT_MODE:
+		artifact := LoadArtifact(c.Contract.Artifact)
+		constructorArgs, err := artifacts.ParseArguments(artifact.ABI.Constructor.Inputs, c.Contract.Artifact.ConstructorArgs)
+		if err != nil {
+			utils.Fatalf("Failed to parse the constructor arguments: %v", err)
+		}
+		packedArgs, err := artifact.ABI.Pack("", constructorArgs...)
+		if err != nil {
+			utils.Fatalf("Failed to pack the constructor arguments: %v", err)
+		}
+		estimate("deploy-artifact", ethClient, ethereum.CallMsg{From: fromAddress, Data: append(append([]byte{}, artifact.Bytecode...), packedArgs...)})
+
+	case utils.METHOD_MODE:
+		artifact := LoadArtifact(c.Contract.Artifact)
+		data, err := artifact.ABI.Pack(c.Contract.Method.Name, parseMethodArguments(artifact, c.Contract.Method)...)
+		if err != nil {
+			utils.Fatalf("Failed to pack the arguments of %s: %v", c.Contract.Method.Name, err)
+		}
+		contractAddress := common.HexToAddress(c.Contract.Address)
+		estimate("call "+c.Contract.Method.Name, ethClient, ethereum.CallMsg{From: fromAddress, To: &contractAddress, Data: data})
+	}
+
+	if len(gasEstimates) == 0 {
+		log.Printf("The run sends no transactions in mode '%s', estimated cost of the run: 0 ETH", c.Contract.Mode)
+		return big.NewInt(0)
+	}
+	totalGas := uint64(0)
+	for _, gas := range gasEstimates {
+		totalGas += gas
+	}
+	gasPrice := transactions.GetTransactionGasPrice(ctx, ethClient)
+	cost := new(big.Int).Mul(new(big.Int).SetUint64(totalGas), gasPrice)
+	cost.Add(cost, value)
+	log.Printf("Estimated cost of the run: %s ETH (%d transaction(s), %d gas, gas price: %s wei, value: %s ETH)",
+		utils.WeiToEther(cost).String(), len(gasEstimates), totalGas, gasPrice, utils.WeiToEther(value).String())
+	return cost
+}
+
+// estimateGas estimates the gas of the call, falling back to the configured gas limit on failure
+func estimateGas(ctx context.Context, ethClient backend.Client, call ethereum.CallMsg, gasLimit uint64) uint64 {
+	gas, err := ethClient.EstimateGas(ctx, call)
+	if err != nil {
+		transactions.ExitIfInterrupted(ctx)
+		log.Printf("Could not estimate gas, using Client.GasLimit (%d) instead: %v", gasLimit, err)
+		return gasLimit
+	}
+	return gas
+}
+
+// estimateCreate2Deployment estimates the deployment of GetterSetter through the deterministic deployment proxy,
+// deployed first when absent, with the funding of its deployer. The deployment is skipped when the contract is
+// already deployed with the salt.
+func estimateCreate2Deployment(ctx context.Context, c config.Config, fromAddress common.Address, ethClient backend.Client) ([]uint64, *big.Int) {
+	salt, err := utils.ParseSalt(c.Contract.Salt)
+	if err != nil {
+		utils.Fatalf("Contract.Salt is invalid: %v", err)
+	}
+	initCode := common.FromHex(getter_setter.GetterSetterMetaData.Bin)
+	address := client.Create2Address(salt, initCode)
+	if len(codeAt(ctx, ethClient, address)) > 0 {
+		log.Printf("Contract address: %s, already deployed with salt %s, the deployment is not estimated", address.Hex(), salt.Hex())
+		return nil, big.NewInt(0)
+	}
+	if len(codeAt(ctx, ethClient, client.DeterministicDeployerAddress)) == 0 {
+		// Its deployer is funded by the account, the presigned deployment is paid with the funding.
+		// The proxy can't be called before it is deployed, Client.GasLimit stands in for the deployment through it.
+		gasEstimates := []uint64{c.Client.GasLimit}
+		funding := client.DeterministicDeployerFunding(ctx, ethClient)
+		if funding.Sign() > 0 {
+			gasEstimates = append(gasEstimates, params.TxGas)
+		}
+		return gasEstimates, funding
+	}
+	call := ethereum.CallMsg{From: fromAddress, To: &client.DeterministicDeployerAddress, Data: append(salt.Bytes(), initCode...)}
+	return []uint64{estimateGas(ctx, ethClient, call, c.Client.GasLimit)}, big.NewInt(0)
+}
+
+// codeAt returns the code at the address
+func codeAt(ctx context.Context, ethClient backend.Client, address common.Address) []byte {
+	code, err := ethClient.CodeAt(ctx, address, nil)
+	if err != nil {
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatalf("Failed to get the code at %s: %v", address.Hex(), err)
+	}
+	return code
+}
+
+// setterCalldata returns the calldata of each setter ExecuteSetterGetterContractFunction is going to send
+func setterCalldata(getterSetterABI *abi.ABI, values config.Values) [][]byte {
+	getterSetterDto, err := dto.NewEthereumDTOBuilder().
+		SetUint256(values.Uint256).
+		SetBytes32(values.Bytes32).
+		SetBytes([]byte(values.Bytes)).
+		Build()
+	if err != nil {
+		utils.Fatalf("Failed to create DTO: %v", err)
+	}
+
+	var calls [][]byte
+	pack := func(method string, args ...interface{}) {
+		data, err := getterSetterABI.Pack(method, args...)
+		if err != nil {
+			utils.Fatalf("Failed to pack %s: %v", method, err)
+		}
+		calls = append(calls, data)
+	}
+	if values.Uint256 != nil && values.Uint256.Cmp(big.NewInt(0)) >= 0 {
+		pack("setUint256", getterSetterDto.Uint256)
+	}
+	if values.Bytes32 != "" {
+		pack("setBytes32", getterSetterDto.Bytes32)
+	}
+	if values.Bytes != "" {
+		pack("setBytes", getterSetterDto.Bytes)
+	}
+	return calls
+}
+
+// proxyDeployment returns the deployment of a TransparentUpgradeableProxy delegating to the implementation,
+// administered by the ProxyAdmin
+func proxyDeployment(from common.Address, implementation common.Address, proxyAdmin common.Address) ethereum.CallMsg {
+	proxyABI, err := transparent_proxy.TransparentUpgradeableProxyMetaData.GetAbi()
+	if err != nil {
+		utils.Fatalf("Failed to parse the TransparentUpgradeableProxy ABI: %v", err)
+	}
+	arguments, err := proxyABI.Pack("", implementation, proxyAdmin, []byte{})
+	if err != nil {
+		utils.Fatalf("Failed to pack the arguments of the TransparentUpgradeableProxy: %v", err)
+	}
+	return ethereum.CallMsg{From: from, Data: append(common.FromHex(transparent_proxy.TransparentUpgradeableProxyMetaData.Bin), arguments...)}
+}
+
+// upgradeCalldata returns the calldata of the ProxyAdmin's upgrade(proxy, newImplementation)
+func upgradeCalldata(proxy common.Address, newImplementation common.Address) []byte {
+	proxyAdminABI, err := transparent_proxy.ProxyAdminMetaData.GetAbi()
+	if err != nil {
+		utils.Fatalf("Failed to parse the ProxyAdmin ABI: %v", err)
+	}
+	data, err := proxyAdminABI.Pack("upgrade", proxy, newImplementation)
+	if err != nil {
+		utils.Fatalf("Failed to pack upgrade: %v", err)
+	}
+	return data
+}
@@ -0,0 +1,198 @@
+package journal
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Status of a journal step
+const (
+	StatusSent  = "sent"  // signed and handed to the node, not mined yet
+	StatusMined = "mined" // mined, the receipt is available
+)
+
+// Step is a transaction sent by the run, e.g. the deployment or a setter call
+type Step struct {
+	Name            string    `json:"name"`
+	Status          string    `json:"status"`
+	TransactionHash string    `json:"transactionHash"`
+	Nonce           uint64    `json:"nonce"`
+	RawTransaction  string    `json:"rawTransaction"` // signed transaction, broadcast again on resume if the node lost it
+	ContractAddress string    `json:"contractAddress,omitempty"`
+	BlockNumber     uint64    `json:"blockNumber,omitempty"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
+
+// Inputs are the configured inputs of the run's transactions. A run can only be resumed with the same ones,
+// the recorded transactions would otherwise not be the ones it is configured to send.
+type Inputs struct {
+	ContractAddress string   `json:"contractAddress,omitempty"`
+	Uint256         string   `json:"uint256,omitempty"`
+	Bytes32         string   `json:"bytes32,omitempty"`
+	Bytes           string   `json:"bytes,omitempty"`
+	Create2         bool     `json:"create2,omitempty"`
+	Salt            string   `json:"salt,omitempty"`
+	Proxy           bool     `json:"proxy,omitempty"`
+	Implementation  string   `json:"implementation,omitempty"`
+	Artifact        string   `json:"artifact,omitempty"`
+	ConstructorArgs []string `json:"constructorArgs,omitempty"`
+	Method          string   `json:"method,omitempty"`
+	MethodArgs      []string `json:"methodArgs,omitempty"`
+}
+
+// Journal records the progress of a run in a file, updated after each step,
+// so an interrupted or failed run can be resumed without sending its transactions again.
+type Journal struct {
+	Mode      string    `json:"mode"`
+	ChainId   uint64    `json:"chainId"`
+	Sender    string    `json:"sender"`
+	Inputs    Inputs    `json:"inputs"`
+	StartedAt time.Time `json:"startedAt"`
+	Completed bool      `json:"completed"`
+	Steps     []Step    `json:"steps"`
+
+	path string
+	mu   sync.Mutex
+}
+
+// New creates an empty journal, written to the path on the first update
+func New(path string, mode string, chainId uint64, sender common.Address, inputs Inputs) *Journal {
+	return &Journal{Mode: mode, ChainId: chainId, Sender: sender.Hex(), Inputs: inputs, StartedAt: time.Now().UTC(), path: path}
+}
+
+// Diff returns the names of the inputs which differ, as in the journal file
+func (i Inputs) Diff(other Inputs) []string {
+	var names []string
+	value, otherValue := reflect.ValueOf(i), reflect.ValueOf(other)
+	for field := 0; field < value.NumField(); field++ {
+		if !reflect.DeepEqual(value.Field(field).Interface(), otherValue.Field(field).Interface()) {
+			name, _, _ := strings.Cut(value.Type().Field(field).Tag.Get("json"), ",")
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Load reads the journal written by a previous run.
+//
+// Parameters:
+// - path: path to the journal file (string)
+// Returns:
+// - *Journal
+// - error if the file can't be read or parsed
+func Load(path string) (*Journal, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the journal %s: %w", path, err)
+	}
+	journal := &Journal{path: path}
+	if err := json.Unmarshal(content, journal); err != nil {
+		return nil, fmt.Errorf("could not parse the journal %s: %w", path, err)
+	}
+	return journal, nil
+}
+
+// Step returns the step with the given name, if the journal has it
+func (j *Journal) Step(name string) (Step, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	for _, step := range j.Steps {
+		if step.Name == name {
+			return step, true
+		}
+	}
+	return Step{}, false
+}
+
+// Sent records the signed transaction of the step, before it is mined
+func (j *Journal) Sent(name string, transaction *types.Transaction) error {
+	raw, err := transaction.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("could not encode the transaction of %s: %w", name, err)
+	}
+	return j.update(Step{
+		Name:            name,
+		Status:          StatusSent,
+		TransactionHash: transaction.Hash().Hex(),
+		Nonce:           transaction.Nonce(),
+		RawTransaction:  hexutil.Encode(raw),
+	})
+}
+
+// Mined records the receipt of the step's transaction
+func (j *Journal) Mined(name string, receipt *types.Receipt) error {
+	step, _ := j.Step(name)
+	step.Name = name
+	step.Status = StatusMined
+	step.TransactionHash = receipt.TxHash.Hex()
+	if receipt.ContractAddress != (common.Address{}) {
+		step.ContractAddress = receipt.ContractAddress.Hex()
+	}
+	if receipt.BlockNumber != nil {
+		step.BlockNumber = receipt.BlockNumber.Uint64()
+	}
+	return j.update(step)
+}
+
+// Complete marks the run as completed
+func (j *Journal) Complete() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.Completed = true
+	return j.save()
+}
+
+// Transaction decodes the signed transaction recorded for the step
+func (s Step) Transaction() (*types.Transaction, error) {
+	raw, err := hexutil.Decode(s.RawTransaction)
+	if err != nil {
+		return nil, fmt.Errorf("invalid raw transaction of %s: %w", s.Name, err)
+	}
+	transaction := new(types.Transaction)
+	if err := transaction.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("invalid raw transaction of %s: %w", s.Name, err)
+	}
+	return transaction, nil
+}
+
+func (j *Journal) update(step Step) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	step.UpdatedAt = time.Now().UTC()
+	for i := range j.Steps {
+		if j.Steps[i].Name == step.Name {
+			j.Steps[i] = step
+			return j.save()
+		}
+	}
+	j.Steps = append(j.Steps, step)
+	return j.save()
+}
+
+// save writes the journal to a temporary file first, so a crash never leaves a truncated journal
+func (j *Journal) save() error {
+	if err := os.MkdirAll(filepath.Dir(j.path), 0755); err != nil {
+		return fmt.Errorf("could not create the journal directory: %w", err)
+	}
+	content, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not encode the journal: %w", err)
+	}
+	temporary := j.path + ".tmp"
+	if err := os.WriteFile(temporary, content, 0644); err != nil {
+		return fmt.Errorf("could not write the journal: %w", err)
+	}
+	if err := os.Rename(temporary, j.path); err != nil {
+		return fmt.Errorf("could not write the journal: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package journal
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"math/big"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestJournal(t *testing.T) {
+	key, err := crypto.HexToECDSA("ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	transaction, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(1)), &types.LegacyTx{Nonce: 3, Gas: 21000, GasPrice: big.NewInt(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	contractAddress := common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3")
+
+	path := filepath.Join(t.TempDir(), "output", "journal.json")
+	inputs := Inputs{Uint256: "42", ConstructorArgs: []string{"1", "0xab"}}
+	journal := New(path, "demo", 1, sender, inputs)
+	if err := journal.Sent("deploy", transaction); err != nil {
+		t.Fatalf("Sent() unexpected error: %v", err)
+	}
+	if err := journal.Sent("setUint256", transaction); err != nil {
+		t.Fatalf("Sent() unexpected error: %v", err)
+	}
+	receipt := &types.Receipt{TxHash: transaction.Hash(), ContractAddress: contractAddress, BlockNumber: big.NewInt(7)}
+	if err := journal.Mined("deploy", receipt); err != nil {
+		t.Fatalf("Mined() unexpected error: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if loaded.Mode != "demo" || loaded.ChainId != 1 || loaded.Sender != sender.Hex() || len(loaded.Inputs.Diff(inputs)) != 0 || loaded.Completed || len(loaded.Steps) != 2 {
+		t.Fatalf("Load() = %+v, want the demo journal with 2 steps", loaded)
+	}
+
+	deploy, ok := loaded.Step("deploy")
+	if !ok || deploy.Status != StatusMined || deploy.ContractAddress != contractAddress.Hex() || deploy.BlockNumber != 7 || deploy.Nonce != 3 {
+		t.Errorf("Step(deploy) = %+v, want the mined deployment", deploy)
+	}
+	setter, ok := loaded.Step("setUint256")
+	if !ok || setter.Status != StatusSent {
+		t.Fatalf("Step(setUint256) = %+v, want a sent step", setter)
+	}
+	decoded, err := setter.Transaction()
+	if err != nil || decoded.Hash() != transaction.Hash() {
+		t.Errorf("Transaction() = %v, %v, want the signed transaction %s", decoded, err, transaction.Hash().Hex())
+	}
+	if _, ok := loaded.Step("setBytes"); ok {
+		t.Error("Step(setBytes) found, want no step")
+	}
+
+	if err := loaded.Complete(); err != nil {
+		t.Fatalf("Complete() unexpected error: %v", err)
+	}
+	if completed, err := Load(path); err != nil || !completed.Completed {
+		t.Errorf("Load() after Complete() = %+v, %v, want a completed journal", completed, err)
+	}
+}
+
+func TestInputsDiff(t *testing.T) {
+	inputs := Inputs{ContractAddress: "0x5FbDB2315678afecb367f032d93F642f64180aa3", Uint256: "42", ConstructorArgs: []string{"1"}}
+	tests := []struct {
+		name  string
+		other Inputs
+		want  []string
+	}{
+		{name: "same inputs", other: inputs},
+		{name: "other value", other: Inputs{ContractAddress: inputs.ContractAddress, Uint256: "43", ConstructorArgs: []string{"1"}}, want: []string{"uint256"}},
+		{name: "other constructor arguments and salt", other: Inputs{ContractAddress: inputs.ContractAddress, Uint256: "42", ConstructorArgs: []string{"2"}, Salt: "0x1"}, want: []string{"salt", "constructorArgs"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := inputs.Diff(tt.other); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadMissingJournal(t *testing.T) {
+	if _, err := Load(filepath.Join(t.TempDir(), "journal.json")); err == nil {
+		t.Error("Load() expected an error")
+	}
+}
@@ -0,0 +1,253 @@
+package geth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"log"
+	"main/src/config"
+	"main/src/contracts/getter_setter"
+	"main/src/evm/clients/geth/account"
+	"main/src/evm/clients/geth/backend"
+	"main/src/evm/clients/geth/client"
+	"main/src/evm/clients/geth/reverts"
+	"main/src/evm/clients/geth/transactions"
+	"main/src/evm/clients/geth/types"
+	"math"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+)
+
+// loadAccount is an account sending load test transactions. Its sends are serialized, so its nonces have no gaps.
+type loadAccount struct {
+	sync.Mutex
+	auth  *bind.TransactOpts
+	nonce uint64
+}
+
+// loadResult is the outcome of a load test transaction
+type loadResult struct {
+	sent     bool
+	mined    bool
+	reverted bool
+	latency  time.Duration
+	gasUsed  uint64
+	err      error
+	// revertReason is the decoded reason of a reverted transaction
+	revertReason string
+}
+
+// send sends setUint256(value) with the account's next nonce. After a failed send, the nonce is read
+// from the node again, as the transaction may have reached it anyway.
+func (a *loadAccount) send(ctx context.Context, ethClient backend.Client, contract *getter_setter.GetterSetter, value *big.Int) (*gethtypes.Transaction, time.Time, error) {
+	a.Lock()
+	defer a.Unlock()
+	auth := *a.auth
+	auth.Nonce = new(big.Int).SetUint64(a.nonce)
+	sentAt := time.Now()
+	transaction, err := contract.SetUint256(&auth, value)
+	if err != nil {
+		if pendingNonce, nonceErr := ethClient.PendingNonceAt(ctx, a.auth.From); nonceErr == nil {
+			a.nonce = pendingNonce
+		}
+		return nil, sentAt, err
+	}
+	a.nonce++
+	return transaction, sentAt, nil
+}
+
+// RunLoadTest sends load.Transactions setUint256 transactions to the GetterSetter contract, at load.Rate
+// transactions per second, from Account.Key and the load.Keys accounts in turn. load.Concurrency goroutines
+// send them, with at most load.MaxInFlight transactions sent and not mined yet. Each transaction sets the
+// uint256 to its (1-based) index. The report has the throughput, the inclusion latency percentiles,
+// the failures and the gas used. An interrupted load test stops sending and reports what was sent.
+//
+// Parameters:
+// - ctx: the context of the run (context.Context)
+// - load: the load test settings (config.Load)
+// - contractAddress: the address of the GetterSetter contract (string)
+// - privateKeys: the private keys of the sending accounts ([]string)
+// - ethClient: the Ethereum client for interaction (backend.Client)
+// - timeout: the timeout for each transaction to be mined, in seconds (int)
+// Return type:
+// - types.LoadTestReport
+func RunLoadTest(ctx context.Context, load config.Load, contractAddress string, privateKeys []string, ethClient backend.Client, timeout int) types.LoadTestReport {
+	getterSetterContract := client.AttachToContract(ctx, contractAddress, ethClient)
+	accounts := make([]*loadAccount, len(privateKeys))
+	addresses := make([]string, len(privateKeys))
+	for i, privateKey := range privateKeys {
+		address := account.GetDeployerAddressFromPrivateKey(privateKey)
+		auth := GetSigner(ctx, ethClient, address, privateKey)
+		accounts[i] = &loadAccount{auth: auth, nonce: auth.Nonce.Uint64()}
+		addresses[i] = address.Hex()
+	}
+	timeToWait := transactions.SetTimeToWait(timeout)
+	pollInterval := time.Duration(load.PollInterval) * time.Millisecond
+	log.Printf("Load test: %d transaction(s) to %s from %d account(s), rate: %v/s (0: unlimited), concurrency: %d, max in flight: %d",
+		load.Transactions, contractAddress, len(accounts), load.Rate, load.Concurrency, load.MaxInFlight)
+
+	// The pacer hands the transactions out at the configured rate
+	jobs := make(chan int)
+	go func() {
+		defer close(jobs)
+		var ticks <-chan time.Time
+		if load.Rate > 0 {
+			ticker := time.NewTicker(time.Duration(float64(time.Second) / load.Rate))
+			defer ticker.Stop()
+			ticks = ticker.C
+		}
+		for i := 0; i < load.Transactions; i++ {
+			if ticks != nil && i > 0 {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticks:
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case jobs <- i:
+			}
+		}
+	}()
+
+	results := make([]loadResult, load.Transactions)
+	inFlight := make(chan struct{}, load.MaxInFlight)
+	var senders, waiters sync.WaitGroup
+	start := time.Now()
+	for worker := 0; worker < load.Concurrency; worker++ {
+		senders.Add(1)
+		go func() {
+			defer senders.Done()
+			for i := range jobs {
+				select {
+				case <-ctx.Done():
+					continue
+				case inFlight <- struct{}{}:
+				}
+				transaction, sentAt, err := accounts[i%len(accounts)].send(ctx, ethClient, getterSetterContract, big.NewInt(int64(i+1)))
+				if err != nil {
+					<-inFlight
+					results[i] = loadResult{err: err}
+					continue
+				}
+				waiters.Add(1)
+				go func(i int, transaction *gethtypes.Transaction, sentAt time.Time) {
+					defer waiters.Done()
+					defer func() { <-inFlight }()
+					receipt, err := transactions.WaitReceipt(ctx, ethClient, transaction, timeToWait, pollInterval)
+					if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+						err = fmt.Errorf("not mined within %s", timeToWait)
+					}
+					if err != nil {
+						results[i] = loadResult{sent: true, err: err}
+						return
+					}
+					result := loadResult{
+						sent:     true,
+						mined:    receipt.Status == gethtypes.ReceiptStatusSuccessful,
+						reverted: receipt.Status != gethtypes.ReceiptStatusSuccessful,
+						latency:  time.Since(sentAt),
+						gasUsed:  receipt.GasUsed,
+					}
+					if result.reverted {
+						reason, _, err := reverts.Replay(ctx, ethClient, transaction, receipt)
+						if err != nil {
+							reason = fmt.Sprintf("unknown, %v", err)
+						}
+						result.revertReason = reason
+					}
+					results[i] = result
+				}(i, transaction, sentAt)
+			}
+		}()
+	}
+	senders.Wait()
+	waiters.Wait()
+
+	report := loadTestReport(results, time.Since(start))
+	report.ContractAddress = common.HexToAddress(contractAddress).Hex()
+	report.Accounts = addresses
+	report.TargetRate = load.Rate
+	log.Printf("Load test: %d sent, %d mined, %d reverted, %d send failure(s), %d wait failure(s) in %.1fs, throughput: %.2f tx/s",
+		report.Sent, report.Mined, report.Reverted, report.SendFailures, report.WaitFailures, report.DurationSeconds, report.Throughput)
+	log.Printf("Inclusion latency (ms): min %.0f, p50 %.0f, p90 %.0f, p95 %.0f, p99 %.0f, max %.0f",
+		report.LatencyMs.Min, report.LatencyMs.P50, report.LatencyMs.P90, report.LatencyMs.P95, report.LatencyMs.P99, report.LatencyMs.Max)
+	for reason, count := range report.RevertReasons {
+		log.Printf("%d transaction(s) reverted with: %s", count, reason)
+	}
+	if load.Rate > 0 && report.Sent == load.Transactions && report.DurationSeconds > 0 && float64(report.Sent)/report.DurationSeconds < load.Rate*0.9 {
+		log.Printf("WARNING: The target rate (%v/s) was not reached, increase Load.Concurrency or Load.MaxInFlight", load.Rate)
+	}
+	return report
+}
+
+// loadTestReport aggregates the results of the load test transactions
+func loadTestReport(results []loadResult, duration time.Duration) types.LoadTestReport {
+	report := types.LoadTestReport{Transactions: len(results), DurationSeconds: duration.Seconds()}
+	var latencies []time.Duration
+	for _, result := range results {
+		if result.sent {
+			report.Sent++
+		}
+		switch {
+		case result.mined || result.reverted:
+			if result.mined {
+				report.Mined++
+			} else {
+				report.Reverted++
+				if report.RevertReasons == nil {
+					report.RevertReasons = map[string]int{}
+				}
+				report.RevertReasons[result.revertReason]++
+			}
+			latencies = append(latencies, result.latency)
+			report.GasUsed += result.gasUsed
+		case result.err != nil && result.sent:
+			report.WaitFailures++
+		case result.err != nil:
+			report.SendFailures++
+		}
+		if result.err != nil {
+			if report.Errors == nil {
+				report.Errors = map[string]int{}
+			}
+			report.Errors[result.err.Error()]++
+		}
+	}
+	if len(latencies) > 0 {
+		report.AverageGasUsed = report.GasUsed / uint64(len(latencies))
+	}
+	if duration > 0 {
+		report.Throughput = float64(report.Mined) / duration.Seconds()
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	report.LatencyMs = types.LatencyPercentiles{
+		Min: percentile(latencies, 0),
+		P50: percentile(latencies, 50),
+		P90: percentile(latencies, 90),
+		P95: percentile(latencies, 95),
+		P99: percentile(latencies, 99),
+		Max: percentile(latencies, 100),
+	}
+	return report
+}
+
+// percentile returns the nearest-rank percentile of the sorted latencies, in milliseconds, 0 if there are none
+func percentile(sorted []time.Duration, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return float64(sorted[rank-1].Microseconds()) / 1000
+}
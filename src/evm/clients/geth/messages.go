@@ -0,0 +1,81 @@
+package geth
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"log"
+	"main/src/config"
+	"main/src/evm/clients/geth/account"
+	"main/src/evm/clients/geth/messages"
+	"main/src/evm/clients/geth/types"
+	"main/src/utils"
+	"os"
+)
+
+// hashMessage hashes the configured message: the EIP-191 hash of Message.Text, or the EIP-712 hash of the
+// typed data in the Message.TypedData file
+func hashMessage(messageConfig config.Message) (common.Hash, types.MessageSignatureInformation) {
+	if messageConfig.Text != "" {
+		return messages.TextHash(messageConfig.Text), types.MessageSignatureInformation{Standard: messages.PersonalMessage, Text: messageConfig.Text}
+	}
+	content, err := os.ReadFile(messageConfig.TypedData)
+	if err != nil {
+		utils.Fatalf("Failed to read the typed data: %v", err)
+	}
+	hash, typedData, err := messages.TypedDataHash(content)
+	if err != nil {
+		utils.Fatalf("Failed to hash %s: %v", messageConfig.TypedData, err)
+	}
+	return hash, types.MessageSignatureInformation{Standard: messages.TypedData, TypedData: messageConfig.TypedData, PrimaryType: typedData.PrimaryType}
+}
+
+// SignMessage signs the configured message with the private key, as personal_sign (EIP-191) or
+// eth_signTypedData_v4 (EIP-712) do, without any connection to a node.
+//
+// Parameters:
+// - messageConfig: the message to sign (config.Message)
+// - privateKey: the private key for signing (string)
+// Return type:
+// - types.MessageSignatureInformation
+func SignMessage(messageConfig config.Message, privateKey string) types.MessageSignatureInformation {
+	hash, output := hashMessage(messageConfig)
+	signature, err := messages.Sign(hash, account.PrivateToECDSA(privateKey))
+	if err != nil {
+		utils.Fatalf("Failed to sign the message: %v", err)
+	}
+	output.Hash = hash.Hex()
+	output.Signature = hexutil.Encode(signature)
+	output.Signer = account.GetDeployerAddressFromPrivateKey(privateKey).Hex()
+	output.Match = true
+	log.Printf("%s message signed by %s, hash: %s, signature: %s", output.Standard, output.Signer, output.Hash, output.Signature)
+	return output
+}
+
+// VerifyMessage recovers the address which signed the configured message from Message.Signature and
+// compares it with Message.Signer, if set.
+//
+// Parameters:
+// - messageConfig: the message, its signature and its expected signer (config.Message)
+// Return type:
+// - types.MessageSignatureInformation
+func VerifyMessage(messageConfig config.Message) types.MessageSignatureInformation {
+	hash, output := hashMessage(messageConfig)
+	signature, err := hexutil.Decode(messageConfig.Signature)
+	if err != nil {
+		utils.Fatalf("Message.Signature is not hex encoded: %v", err)
+	}
+	signer, err := messages.Recover(hash, signature)
+	if err != nil {
+		utils.Fatalf("Failed to verify the signature: %v", err)
+	}
+	output.Hash = hash.Hex()
+	output.Signature = hexutil.Encode(signature)
+	output.Signer = signer.Hex()
+	output.Match = true
+	if messageConfig.Signer != "" {
+		output.ExpectedSigner = common.HexToAddress(messageConfig.Signer).Hex()
+		output.Match = signer == common.HexToAddress(messageConfig.Signer)
+	}
+	log.Printf("%s message signed by %s, hash: %s", output.Standard, output.Signer, output.Hash)
+	return output
+}
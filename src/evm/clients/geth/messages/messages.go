@@ -0,0 +1,95 @@
+package messages
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"math/big"
+)
+
+// The standards a message is hashed with
+const (
+	PersonalMessage = "EIP-191" // personal_sign: "\x19Ethereum Signed Message:\n" + length + message
+	TypedData       = "EIP-712" // eth_signTypedData_v4: "\x19\x01" + domain separator + hash of the message
+)
+
+// TextHash returns the EIP-191 hash of the message, as personal_sign computes it
+func TextHash(message string) common.Hash {
+	return common.BytesToHash(accounts.TextHash([]byte(message)))
+}
+
+// TypedDataHash returns the EIP-712 hash of typed data, in the JSON format eth_signTypedData_v4 takes.
+//
+// Parameters:
+// - content: the typed data, with its types, primaryType, domain and message ([]byte)
+// Returns:
+// - common.Hash
+// - apitypes.TypedData, the parsed typed data
+// - error if the typed data can't be parsed or hashed
+func TypedDataHash(content []byte) (common.Hash, apitypes.TypedData, error) {
+	var typedData apitypes.TypedData
+	if err := json.Unmarshal(content, &typedData); err != nil {
+		return common.Hash{}, apitypes.TypedData{}, fmt.Errorf("invalid typed data: %w", err)
+	}
+	if typedData.PrimaryType == "" {
+		return common.Hash{}, apitypes.TypedData{}, errors.New("invalid typed data: primaryType is missing")
+	}
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return common.Hash{}, apitypes.TypedData{}, fmt.Errorf("failed to hash the typed data: %w", err)
+	}
+	return common.BytesToHash(hash), typedData, nil
+}
+
+// Sign signs the hash with the key. As the wallets do, the recovery id of the signature is 27 or 28.
+//
+// Parameters:
+// - hash: the EIP-191 or EIP-712 hash of the message (common.Hash)
+// - privateKey: the key of the signer (*ecdsa.PrivateKey)
+// Returns:
+// - the 65 bytes signature, r, s and v ([]byte)
+// - error if the hash can't be signed
+func Sign(hash common.Hash, privateKey *ecdsa.PrivateKey) ([]byte, error) {
+	signature, err := crypto.Sign(hash.Bytes(), privateKey)
+	if err != nil {
+		return nil, err
+	}
+	signature[crypto.RecoveryIDOffset] += 27
+	return signature, nil
+}
+
+// Recover returns the address which signed the hash. The recovery id can be 0/1 or 27/28, and the
+// malleable signatures, with a high s value, are rejected, as ecrecover in the contracts of OpenZeppelin does.
+//
+// Parameters:
+// - hash: the EIP-191 or EIP-712 hash of the message (common.Hash)
+// - signature: the 65 bytes signature, r, s and v ([]byte)
+// Returns:
+// - common.Address
+// - error if the signature is malformed or nothing can be recovered from it
+func Recover(hash common.Hash, signature []byte) (common.Address, error) {
+	if len(signature) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("the signature is %d bytes long, not %d", len(signature), crypto.SignatureLength)
+	}
+	normalized := make([]byte, crypto.SignatureLength)
+	copy(normalized, signature)
+	if normalized[crypto.RecoveryIDOffset] >= 27 {
+		normalized[crypto.RecoveryIDOffset] -= 27
+	}
+	r := new(big.Int).SetBytes(normalized[:32])
+	s := new(big.Int).SetBytes(normalized[32:64])
+	if !crypto.ValidateSignatureValues(normalized[crypto.RecoveryIDOffset], r, s, true) {
+		return common.Address{}, fmt.Errorf("invalid signature %s", hexutil.Encode(signature))
+	}
+	publicKey, err := crypto.SigToPub(hash.Bytes(), normalized)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to recover the signer: %w", err)
+	}
+	return crypto.PubkeyToAddress(*publicKey), nil
+}
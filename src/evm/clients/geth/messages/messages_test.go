@@ -0,0 +1,118 @@
+package messages
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"testing"
+)
+
+// mailTypedData is the example of the EIP-712 specification, signed with the key keccak256("cow")
+const mailTypedData = `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "version", "type": "string"},
+			{"name": "chainId", "type": "uint256"},
+			{"name": "verifyingContract", "type": "address"}
+		],
+		"Person": [{"name": "name", "type": "string"}, {"name": "wallet", "type": "address"}],
+		"Mail": [{"name": "from", "type": "Person"}, {"name": "to", "type": "Person"}, {"name": "contents", "type": "string"}]
+	},
+	"primaryType": "Mail",
+	"domain": {"name": "Ether Mail", "version": "1", "chainId": 1, "verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"},
+	"message": {
+		"from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+		"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+		"contents": "Hello, Bob!"
+	}
+}`
+
+var (
+	mailHash      = common.HexToHash("0xbe609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2")
+	mailSignature = hexutil.MustDecode("0x4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b915621c")
+	cowAddress    = common.HexToAddress("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826")
+)
+
+func TestTypedDataHash(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		wantHash common.Hash
+		wantErr  bool
+	}{
+		{name: "EIP-712 example", content: mailTypedData, wantHash: mailHash},
+		{name: "not JSON", content: "Hello, Bob!", wantErr: true},
+		{name: "without primary type", content: `{"types": {"EIP712Domain": []}, "domain": {}, "message": {}}`, wantErr: true},
+		{name: "unknown primary type", content: `{"types": {"EIP712Domain": []}, "primaryType": "Mail", "domain": {}, "message": {}}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, _, err := TypedDataHash([]byte(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TypedDataHash() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if hash != tt.wantHash {
+				t.Errorf("TypedDataHash() = %s, want %s", hash.Hex(), tt.wantHash.Hex())
+			}
+		})
+	}
+}
+
+func TestTextHash(t *testing.T) {
+	message := "I control the deployer account"
+	want := crypto.Keccak256Hash([]byte(fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(message), message)))
+	if got := TextHash(message); got != want {
+		t.Errorf("TextHash() = %s, want %s", got.Hex(), want.Hex())
+	}
+}
+
+func TestSignAndRecover(t *testing.T) {
+	cow := crypto.Keccak256Hash([]byte("cow"))
+	key, err := crypto.ToECDSA(cow.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature, err := Sign(mailHash, key)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	if !bytes.Equal(signature, mailSignature) {
+		t.Errorf("Sign() = %x, want the signature of the EIP-712 example %x", signature, mailSignature)
+	}
+
+	// The same signature with the recovery id as 0/1 and with the s value of the other, malleable, signature
+	rawRecoveryID := append(append([]byte{}, mailSignature[:64]...), mailSignature[64]-27)
+	highS := append([]byte{}, mailSignature...)
+	s := crypto.S256().Params().N
+	copy(highS[32:64], common.LeftPadBytes(s.Sub(s, common.BytesToHash(mailSignature[32:64]).Big()).Bytes(), 32))
+	tests := []struct {
+		name      string
+		hash      common.Hash
+		signature []byte
+		want      common.Address // zero when the signature is of another message, the recovered address is then another one
+		wantErr   bool
+	}{
+		{name: "recovery id 27/28", hash: mailHash, signature: mailSignature, want: cowAddress},
+		{name: "recovery id 0/1", hash: mailHash, signature: rawRecoveryID, want: cowAddress},
+		{name: "another message", hash: TextHash("Hello, Bob!"), signature: mailSignature},
+		{name: "malleable signature", hash: mailHash, signature: highS, wantErr: true},
+		{name: "truncated signature", hash: mailHash, signature: mailSignature[:64], wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Recover(tt.hash, tt.signature)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Recover() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if tt.want == (common.Address{}) && got == cowAddress {
+				t.Errorf("Recover() of another message = %s, want another address", got.Hex())
+			}
+			if tt.want != (common.Address{}) && got != tt.want {
+				t.Errorf("Recover() = %s, want %s", got.Hex(), tt.want.Hex())
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package geth
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"log"
+	"main/src/config"
+	"main/src/contracts/getter_setter"
+	"main/src/evm/clients/geth/account"
+	"main/src/evm/clients/geth/backend"
+	"main/src/evm/clients/geth/reverts"
+	"main/src/evm/clients/geth/transactions"
+	"main/src/evm/clients/geth/types"
+	"main/src/utils"
+	"math/big"
+	"os"
+	"strings"
+)
+
+// PrepareSetterTransactions builds the unsigned setter transactions of the configured values, with consecutive
+// nonces from the pending nonce of the sender, the suggested gas price and Client.GasLimit. Each one is
+// simulated with eth_call and its gas estimated, so the signers can review them. Nothing is signed.
+//
+// Parameters:
+// - ctx: the context of the run (context.Context)
+// - values: the values to set in the contract (config.Values)
+// - contractAddress: the address of the GetterSetter contract (string)
+// - fromAddress: the address of the account which is going to sign the transactions (common.Address)
+// - ethClient: the Ethereum client for interaction (backend.Client)
+// - gasLimit: the gas limit of each transaction (uint64)
+// Return type:
+// - types.UnsignedTransactions
+func PrepareSetterTransactions(ctx context.Context, values config.Values, contractAddress string, fromAddress common.Address, ethClient backend.Client, gasLimit uint64) types.UnsignedTransactions {
+	getterSetterABI, err := getter_setter.GetterSetterMetaData.GetAbi()
+	if err != nil {
+		utils.Fatalf("Failed to parse the GetterSetter ABI: %v", err)
+	}
+	chainID := transactions.GetChainId(ctx, ethClient)
+	nonce := transactions.GetNonce(ctx, ethClient, fromAddress)
+	gasPrice := transactions.GetTransactionGasPrice(ctx, ethClient)
+	to := common.HexToAddress(contractAddress)
+
+	output := types.UnsignedTransactions{ChainId: chainID, From: fromAddress.Hex()}
+	for _, data := range setterCalldata(getterSetterABI, values) {
+		method, err := getterSetterABI.MethodById(data[:4])
+		if err != nil {
+			utils.Fatalf("Failed to find the setter of %x: %v", data[:4], err)
+		}
+		transaction := gethtypes.NewTx(&gethtypes.LegacyTx{Nonce: nonce, To: &to, Value: big.NewInt(0), Gas: gasLimit, GasPrice: gasPrice, Data: data})
+		simulation := "success"
+		if err := simulate(ctx, ethClient, fromAddress, transaction); err != nil {
+			transactions.ExitIfInterrupted(ctx)
+			simulation = reverts.Explain(method.Name, err).Error()
+			log.Printf("WARNING: The eth_call simulation of %s failed: %s", method.Name, simulation)
+		}
+		call := ethereum.CallMsg{From: fromAddress, To: &to, Data: data}
+		output.Transactions = append(output.Transactions, types.UnsignedTransaction{
+			Step:         method.Name,
+			To:           to.Hex(),
+			Nonce:        nonce,
+			Value:        transaction.Value(),
+			GasLimit:     gasLimit,
+			GasPrice:     gasPrice,
+			Data:         hexutil.Encode(data),
+			EstimatedGas: estimateGas(ctx, ethClient, call, gasLimit),
+			Simulation:   simulation,
+		})
+		log.Printf("Prepared %s: from %s, nonce %d, gas limit %d, gas price %s wei", method.Name, fromAddress.Hex(), nonce, gasLimit, gasPrice)
+		nonce++
+	}
+	return output
+}
+
+// SignTransactions signs the prepared transactions with the private key, without any connection to a node.
+// The key must be the one of the account the transactions were prepared for.
+//
+// Parameters:
+// - unsigned: the transactions written by the "prepare" mode (types.UnsignedTransactions)
+// - privateKey: the private key for signing (string)
+// Return type:
+// - types.SignedTransactions
+func SignTransactions(unsigned types.UnsignedTransactions, privateKey string) types.SignedTransactions {
+	if unsigned.ChainId == nil || unsigned.ChainId.Sign() <= 0 {
+		utils.Fatal("The unsigned transactions have no chain ID, prepare them again")
+	}
+	signerAddress := account.GetDeployerAddressFromPrivateKey(privateKey)
+	if common.HexToAddress(unsigned.From) != signerAddress {
+		utils.Fatalf("The transactions were prepared for %s, but Account.Key is the key of %s", unsigned.From, signerAddress.Hex())
+	}
+	privateKeyECDSA := account.PrivateToECDSA(privateKey)
+	signer := gethtypes.LatestSignerForChainID(unsigned.ChainId)
+
+	output := types.SignedTransactions{ChainId: unsigned.ChainId, From: signerAddress.Hex()}
+	for _, prepared := range unsigned.Transactions {
+		if err := utils.ValidateAddress(prepared.To); err != nil {
+			utils.Fatalf("The %s transaction has an invalid recipient: %v", prepared.Step, err)
+		}
+		data, err := hexutil.Decode(prepared.Data)
+		if err != nil {
+			utils.Fatalf("The %s transaction has invalid calldata: %v", prepared.Step, err)
+		}
+		if prepared.Value == nil || prepared.GasPrice == nil {
+			utils.Fatalf("The %s transaction has no value or gas price, prepare it again", prepared.Step)
+		}
+		to := common.HexToAddress(prepared.To)
+		transaction, err := gethtypes.SignNewTx(privateKeyECDSA, signer, &gethtypes.LegacyTx{
+			Nonce:    prepared.Nonce,
+			To:       &to,
+			Value:    prepared.Value,
+			Gas:      prepared.GasLimit,
+			GasPrice: prepared.GasPrice,
+			Data:     data,
+		})
+		if err != nil {
+			utils.Fatalf("Failed to sign the %s transaction: %v", prepared.Step, err)
+		}
+		raw, err := transaction.MarshalBinary()
+		if err != nil {
+			utils.Fatalf("Failed to encode the %s transaction: %v", prepared.Step, err)
+		}
+		output.Transactions = append(output.Transactions, types.SignedTransaction{
+			Step:            prepared.Step,
+			Nonce:           prepared.Nonce,
+			TransactionHash: transaction.Hash().Hex(),
+			RawTransaction:  hexutil.Encode(raw),
+		})
+		log.Printf("Signed %s: nonce %d, hash %s", prepared.Step, prepared.Nonce, transaction.Hash().Hex())
+	}
+	return output
+}
+
+// BroadcastTransactions sends the transactions signed offline, in order, and waits for each one to be mined.
+// A transaction already mined, e.g. by an interrupted broadcast, is not sent again, so the broadcast can be rerun.
+//
+// Parameters:
+// - ctx: the context of the run (context.Context)
+// - signed: the transactions written by the "sign" mode (types.SignedTransactions)
+// - ethClient: the Ethereum client for interaction (backend.Client)
+// - timeout: the timeout duration for each transaction to be mined (int)
+// Return type:
+// - types.BroadcastInformation
+func BroadcastTransactions(ctx context.Context, signed types.SignedTransactions, ethClient backend.Client, timeout int) types.BroadcastInformation {
+	chainID := transactions.GetChainId(ctx, ethClient)
+	if signed.ChainId == nil || chainID.Cmp(signed.ChainId) != 0 {
+		utils.Fatalf("The transactions are signed for the chain ID %s, but the node reports %s", signed.ChainId, chainID)
+	}
+	signer := gethtypes.LatestSignerForChainID(chainID)
+
+	output := types.BroadcastInformation{ChainId: chainID, From: signed.From}
+	for _, signedTransaction := range signed.Transactions {
+		raw, err := hexutil.Decode(signedTransaction.RawTransaction)
+		if err != nil {
+			utils.Fatalf("The %s transaction is not hex encoded: %v", signedTransaction.Step, err)
+		}
+		transaction := new(gethtypes.Transaction)
+		if err := transaction.UnmarshalBinary(raw); err != nil {
+			utils.Fatalf("Failed to decode the %s transaction: %v", signedTransaction.Step, err)
+		}
+		if transaction.Hash() != common.HexToHash(signedTransaction.TransactionHash) {
+			utils.Fatalf("The %s transaction hashes to %s, not %s, sign it again", signedTransaction.Step, transaction.Hash().Hex(), signedTransaction.TransactionHash)
+		}
+		if from, err := gethtypes.Sender(signer, transaction); err != nil || from != common.HexToAddress(signed.From) {
+			utils.Fatalf("The %s transaction is not signed by %s, sign it again", signedTransaction.Step, signed.From)
+		}
+
+		broadcast := types.BroadcastTransaction{Step: signedTransaction.Step, TransactionHash: transaction.Hash().Hex()}
+		minedNonce, err := ethClient.NonceAt(ctx, common.HexToAddress(signed.From), nil)
+		if err != nil {
+			transactions.ExitIfInterrupted(ctx)
+			utils.Fatalf("Failed to get the nonce of %s: %v", signed.From, err)
+		}
+		var receipt *gethtypes.Receipt
+		if transaction.Nonce() < minedNonce {
+			// The nonce is used, by this very transaction if an earlier broadcast sent it
+			receipt, err = ethClient.TransactionReceipt(ctx, transaction.Hash())
+			if err != nil {
+				transactions.ExitIfInterrupted(ctx)
+				utils.Fatalf("The nonce %d of the %s transaction is used by another transaction (%v), prepare and sign the transactions again", transaction.Nonce(), signedTransaction.Step, err)
+			}
+			broadcast.AlreadyMined = true
+			log.Printf("Transaction for %s: %s, already mined in block %d", signedTransaction.Step, transaction.Hash().Hex(), receipt.BlockNumber)
+		} else {
+			// A transaction still pending from an interrupted broadcast is already known to the node
+			if err := ethClient.SendTransaction(ctx, transaction); err != nil && !strings.Contains(err.Error(), "already known") {
+				transactions.ExitIfInterrupted(ctx)
+				utils.Fatalf("Failed to broadcast the %s transaction: %v", signedTransaction.Step, err)
+			}
+			log.Printf("Waiting for transaction for %s: %s\n", signedTransaction.Step, transaction.Hash().Hex())
+			logExplorerLink("tx", transaction.Hash().Hex())
+			receipt = transactions.WaitMined(ctx, ethClient, transaction, timeout)
+		}
+		if err := reverts.CheckReceipt(ctx, ethClient, signedTransaction.Step, transaction, receipt); err != nil {
+			transactions.ExitIfInterrupted(ctx)
+			utils.Fatalf("Failed to broadcast the %s transaction: %v", signedTransaction.Step, err)
+		}
+		traceTransaction(ctx, signedTransaction.Step, transaction)
+		broadcast.BlockNumber = receipt.BlockNumber.Uint64()
+		broadcast.GasUsed = receipt.GasUsed
+		output.Transactions = append(output.Transactions, broadcast)
+	}
+	return output
+}
+
+// readOfflineFile reads the transactions written by the previous offline signing step
+func readOfflineFile(path string, output interface{}) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		utils.Fatalf("Failed to read %s, run the previous offline signing step first: %v", path, err)
+	}
+	if err := json.Unmarshal(content, output); err != nil {
+		utils.Fatalf("Failed to parse %s: %v", path, err)
+	}
+}
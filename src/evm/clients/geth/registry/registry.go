@@ -0,0 +1,139 @@
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Deployment is a contract deployed by the application. A contract found already deployed at its CREATE2 address
+// has no deployer and transaction hash, its block is the one it was found in.
+type Deployment struct {
+	Alias           string    `json:"alias,omitempty"`
+	Contract        string    `json:"contract"` // contract name, e.g. GetterSetter
+	Address         string    `json:"address"`
+	Proxy           bool      `json:"proxy,omitempty"` // the address is the one of a TransparentUpgradeableProxy, upgradeable in the "upgrade-contract" mode
+	Deployer        string    `json:"deployer,omitempty"`
+	TransactionHash string    `json:"transactionHash,omitempty"`
+	BlockNumber     uint64    `json:"blockNumber"`
+	BytecodeHash    string    `json:"bytecodeHash"` // keccak256 of the deployed runtime code
+	Timestamp       time.Time `json:"timestamp"`
+}
+
+// Registry holds the deployments of each chain, keyed by chain ID, in the order they were made
+type Registry struct {
+	Chains map[string][]Deployment
+
+	path string
+}
+
+// Load reads the registry file. A missing file is an empty registry, it is created by the first deployment.
+//
+// Parameters:
+// - path: path to the registry file (string)
+// Returns:
+// - *Registry
+// - error if the file can't be read or parsed
+func Load(path string) (*Registry, error) {
+	registry := &Registry{Chains: map[string][]Deployment{}, path: path}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return registry, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read the deployment registry %s: %w", path, err)
+	}
+	if err := json.Unmarshal(content, &registry.Chains); err != nil {
+		return nil, fmt.Errorf("could not parse the deployment registry %s: %w", path, err)
+	}
+	return registry, nil
+}
+
+// Add records the deployment on the chain and writes the registry.
+// A deployment already recorded at the same address is replaced, e.g. when a run is resumed.
+func (r *Registry) Add(chainID uint64, deployment Deployment) error {
+	key := strconv.FormatUint(chainID, 10)
+	deployments := r.Chains[key]
+	for i := range deployments {
+		if strings.EqualFold(deployments[i].Address, deployment.Address) {
+			deployments = append(deployments[:i], deployments[i+1:]...)
+			break
+		}
+	}
+	r.Chains[key] = append(deployments, deployment)
+	return r.save()
+}
+
+// Find returns the deployment recorded at the address on the chain, if any
+func (r *Registry) Find(chainID uint64, address string) (Deployment, bool) {
+	for _, deployment := range r.Chains[strconv.FormatUint(chainID, 10)] {
+		if strings.EqualFold(deployment.Address, address) {
+			return deployment, true
+		}
+	}
+	return Deployment{}, false
+}
+
+// Resolve returns the latest deployment on the chain with the given alias, or the latest one if no alias is given.
+//
+// Parameters:
+// - chainID: the chain ID (uint64)
+// - alias: the alias of the deployment, optional (string)
+// - contract: the contract name, optional (string)
+// - proxy: only the deployments behind a proxy are resolved if set (bool)
+// Returns:
+// - Deployment
+// - error if no deployment matches
+func (r *Registry) Resolve(chainID uint64, alias string, contract string, proxy bool) (Deployment, error) {
+	deployments := r.Chains[strconv.FormatUint(chainID, 10)]
+	for i := len(deployments) - 1; i >= 0; i-- {
+		if alias != "" && deployments[i].Alias != alias {
+			continue
+		}
+		if contract != "" && deployments[i].Contract != contract {
+			continue
+		}
+		if proxy && !deployments[i].Proxy {
+			continue
+		}
+		return deployments[i], nil
+	}
+
+	description := "deployment"
+	if contract != "" {
+		description = contract + " deployment"
+	}
+	if proxy {
+		description += " behind a proxy"
+	}
+	if alias != "" {
+		return Deployment{}, fmt.Errorf("no %s with the alias '%s' on chain %d in %s", description, alias, chainID, r.path)
+	}
+	return Deployment{}, fmt.Errorf("no %s on chain %d in %s", description, chainID, r.path)
+}
+
+// save writes the registry to a temporary file first, so a crash never leaves a truncated registry
+func (r *Registry) save() error {
+	if dir := filepath.Dir(r.path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("could not create the registry directory: %w", err)
+		}
+	}
+	content, err := json.MarshalIndent(r.Chains, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not encode the deployment registry: %w", err)
+	}
+	temporary := r.path + ".tmp"
+	if err := os.WriteFile(temporary, content, 0644); err != nil {
+		return fmt.Errorf("could not write the deployment registry: %w", err)
+	}
+	if err := os.Rename(temporary, r.path); err != nil {
+		return fmt.Errorf("could not write the deployment registry: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package registry
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegistry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deployments.json")
+	registry, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() of a missing registry error = %v", err)
+	}
+
+	deployments := []Deployment{
+		{Contract: "GetterSetter", Address: "0x0000000000000000000000000000000000000001", BlockNumber: 1},
+		{Alias: "staging", Contract: "GetterSetter", Address: "0x0000000000000000000000000000000000000002", BlockNumber: 2},
+		{Contract: "GetterSetter", Address: "0x0000000000000000000000000000000000000005", Proxy: true, BlockNumber: 3},
+		{Contract: "Counter", Address: "0x0000000000000000000000000000000000000003", BlockNumber: 4},
+	}
+	for _, deployment := range deployments {
+		deployment.Timestamp = time.Unix(1700000000, 0).UTC()
+		if err := registry.Add(11155111, deployment); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+	if err := registry.Add(1, Deployment{Contract: "GetterSetter", Address: "0x0000000000000000000000000000000000000004"}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	// Read it back, as a later run does
+	registry, err = Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	tests := []struct {
+		name     string
+		chainID  uint64
+		alias    string
+		contract string
+		proxy    bool
+		want     string
+		wantErr  bool
+	}{
+		{name: "latest deployment", chainID: 11155111, want: "0x0000000000000000000000000000000000000003"},
+		{name: "latest deployment of a contract", chainID: 11155111, contract: "GetterSetter", want: "0x0000000000000000000000000000000000000005"},
+		{name: "latest proxy", chainID: 11155111, contract: "GetterSetter", proxy: true, want: "0x0000000000000000000000000000000000000005"},
+		{name: "alias without a proxy", chainID: 11155111, alias: "staging", proxy: true, wantErr: true},
+		{name: "by alias", chainID: 11155111, alias: "staging", want: "0x0000000000000000000000000000000000000002"},
+		{name: "other chain", chainID: 1, contract: "GetterSetter", want: "0x0000000000000000000000000000000000000004"},
+		{name: "unknown alias", chainID: 11155111, alias: "production", wantErr: true},
+		{name: "alias of another contract", chainID: 11155111, alias: "staging", contract: "Counter", wantErr: true},
+		{name: "unknown chain", chainID: 5, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deployment, err := registry.Resolve(tt.chainID, tt.alias, tt.contract, tt.proxy)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && deployment.Address != tt.want {
+				t.Errorf("Resolve() = %s, want %s", deployment.Address, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddReplacesTheSameAddress(t *testing.T) {
+	registry, err := Load(filepath.Join(t.TempDir(), "deployments.json"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	address := "0x0000000000000000000000000000000000000001"
+	if err := registry.Add(1, Deployment{Contract: "GetterSetter", Address: address, BlockNumber: 1}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := registry.Add(1, Deployment{Contract: "Counter", Address: "0x0000000000000000000000000000000000000002"}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	// A resumed run records its deployment again
+	if err := registry.Add(1, Deployment{Alias: "resumed", Contract: "GetterSetter", Address: address, BlockNumber: 1}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if got := len(registry.Chains["1"]); got != 2 {
+		t.Fatalf("registry has %d deployments, want 2", got)
+	}
+	if deployment, _ := registry.Resolve(1, "", "", false); deployment.Alias != "resumed" {
+		t.Errorf("Resolve() = %+v, want the re-recorded deployment last", deployment)
+	}
+	if deployment, ok := registry.Find(1, strings.ToLower(address)); !ok || deployment.Alias != "resumed" {
+		t.Errorf("Find() = %+v, %t, want the re-recorded deployment", deployment, ok)
+	}
+	if _, ok := registry.Find(1, "0x0000000000000000000000000000000000000003"); ok {
+		t.Error("Find() of an unknown address found a deployment")
+	}
+	// The registry is written to a temporary file, then renamed
+	if _, err := os.Stat(registry.path + ".tmp"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("the temporary file is left behind: %v", err)
+	}
+}
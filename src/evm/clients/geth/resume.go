@@ -0,0 +1,293 @@
+package geth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"log"
+	"main/src/evm/clients/geth/backend"
+	"main/src/evm/clients/geth/journal"
+	"main/src/evm/clients/geth/reverts"
+	"main/src/evm/clients/geth/transactions"
+	"main/src/utils"
+	"os"
+	"strings"
+	"time"
+)
+
+// journalPath is where the progress of the run is recorded, see the -resume flag
+const journalPath = "output/journal.json"
+
+// runJournal records the transactions of the run, nil in the read-only modes
+var runJournal *journal.Journal
+
+// inFlight is the step being run, with its signed transaction once the step signed it
+var inFlight struct {
+	name        string
+	transaction *types.Transaction
+	sent        bool // recorded in the journal, the node accepted the transaction
+}
+
+// journalingClient records the transaction of the step being run as sent as soon as the node accepted it,
+// so a run stopping before the transaction is mined, e.g. on a timeout, waits for it when resumed instead
+// of sending the step again. It is the client of the run once the journal is open.
+type journalingClient struct {
+	backend.Client
+}
+
+// SendTransaction sends the transaction, then records it in the journal if it is the one of the step being run
+func (c journalingClient) SendTransaction(ctx context.Context, transaction *types.Transaction) error {
+	if err := c.Client.SendTransaction(ctx, transaction); err != nil {
+		return err
+	}
+	if runJournal != nil && inFlight.transaction != nil && inFlight.transaction.Hash() == transaction.Hash() {
+		if err := runJournal.Sent(inFlight.name, transaction); err != nil {
+			utils.Fatalf("Failed to update the journal: %v", err)
+		}
+		inFlight.sent = true
+	}
+	return nil
+}
+
+// Unwrap returns the client the transactions are sent with
+func (c journalingClient) Unwrap() backend.Client {
+	return c.Client
+}
+
+// openJournal starts the journal of the run or, when resuming, loads the one of the previous run.
+// A journal left unfinished by a previous run is kept aside instead of being overwritten.
+func openJournal(ctx context.Context, resume bool) {
+	utils.AtExit(recordInFlightStep)
+	// The steps send their transactions through it, see journaledStep
+	ethClient = journalingClient{Client: ethClient}
+	chainID := transactions.GetChainId(ctx, ethClient).Uint64()
+	inputs := runInputs()
+	if !resume {
+		if previous, err := journal.Load(journalPath); err == nil && !previous.Completed {
+			backupPath := strings.TrimSuffix(journalPath, ".json") + "." + previous.StartedAt.Format("20060102T150405Z") + ".json"
+			if err := os.Rename(journalPath, backupPath); err != nil {
+				utils.Fatalf("Failed to keep the unfinished journal aside: %v", err)
+			}
+			log.Printf("WARNING: The previous run did not complete, its journal is moved to %s. Use -resume to pick up where a run stopped.", backupPath)
+		}
+		runJournal = journal.New(journalPath, mode, chainID, deployerAddress, inputs)
+		return
+	}
+
+	if rpcURL == utils.SIMULATED_RPC_URL {
+		utils.Fatal("A run can't be resumed on the simulated chain, its state is lost when the application exits")
+	}
+	previous, err := journal.Load(journalPath)
+	if err != nil {
+		utils.Fatalf("Failed to resume: %v", err)
+	}
+	if err := checkResumable(previous, mode, chainID, deployerAddress, inputs); err != nil {
+		utils.Fatalf("Failed to resume: %v", err)
+	}
+	previous.Completed = false
+	runJournal = previous
+	log.Printf("Resuming the run started at %s (%d step(s) recorded)", previous.StartedAt.Format("2006-01-02 15:04:05"), len(previous.Steps))
+}
+
+// runInputs returns the configured inputs of the run's transactions, recorded in the journal
+func runInputs() journal.Inputs {
+	contract := tomlConfig.Contract
+	inputs := journal.Inputs{
+		ContractAddress: contractAddress,
+		Bytes32:         values.Bytes32,
+		Bytes:           values.Bytes,
+		Create2:         contract.Create2,
+		Salt:            contract.Salt,
+		Proxy:           contract.Proxy,
+		Implementation:  contract.Implementation,
+		Artifact:        contract.Artifact.Path,
+		ConstructorArgs: contract.Artifact.ConstructorArgs,
+		Method:          contract.Method.Name,
+		MethodArgs:      contract.Method.Args,
+	}
+	if values.Uint256 != nil {
+		inputs.Uint256 = values.Uint256.String()
+	}
+	if inputs.Artifact == "" {
+		inputs.Artifact = contract.Artifact.Bin
+	}
+	return inputs
+}
+
+// checkResumable checks that the journal was written by a run of the same mode, on the same chain, by the same sender
+// and with the same inputs
+func checkResumable(previous *journal.Journal, mode string, chainID uint64, sender common.Address, inputs journal.Inputs) error {
+	if previous.Mode != mode {
+		return fmt.Errorf("the journal is for mode '%s', not '%s'", previous.Mode, mode)
+	}
+	if previous.ChainId != chainID {
+		return fmt.Errorf("the journal is for chain ID %d, but the node reports %d", previous.ChainId, chainID)
+	}
+	if !strings.EqualFold(previous.Sender, sender.Hex()) {
+		return fmt.Errorf("the journal is for sender %s, not %s", previous.Sender, sender.Hex())
+	}
+	if differences := previous.Inputs.Diff(inputs); len(differences) > 0 {
+		return fmt.Errorf("the journal was recorded with other inputs (%s), configure them as in the previous run or start a new run without -resume", strings.Join(differences, ", "))
+	}
+	return nil
+}
+
+// completeJournal marks the run as completed, so a later -resume only re-reads its results
+func completeJournal() {
+	if runJournal == nil {
+		return
+	}
+	if err := runJournal.Complete(); err != nil {
+		utils.Fatalf("Failed to update the journal: %v", err)
+	}
+}
+
+// journaledStep runs a transaction step and records it in the journal: the signed transaction once the node
+// accepted it, then its receipt once mined. When the step is found in the journal, it is picked up
+// instead of being run again: a mined step is skipped, a sent one is waited for (and broadcast again
+// if the node doesn't know it anymore).
+//
+// Parameters:
+// - ctx: the context of the run (context.Context)
+// - name: the name of the step, unique within the run (string)
+// - auth: the signer options of the step's transaction (*bind.TransactOpts)
+// - run: sends the transaction with the given signer options through ethClient and waits for it to be mined
+// Returns:
+// - the receipt of the step's transaction, nil when there is no journal or the step sent no transaction
+// - true if the step was picked up from the journal, i.e. run was not called
+func journaledStep(ctx context.Context, name string, auth *bind.TransactOpts, run func(auth *bind.TransactOpts)) (*types.Receipt, bool) {
+	if runJournal == nil {
+		run(auth)
+		return nil, false
+	}
+	if step, ok := runJournal.Step(name); ok {
+		if receipt := resumeStep(ctx, step); receipt != nil {
+			return receipt, true
+		}
+	}
+
+	// The transaction is recorded by journalingClient once the node accepted it, or by recordInFlightStep if the run
+	// stops on an error sending it. A transaction rejected by the node is not recorded, so a resumed run runs the step again.
+	journaled := *auth
+	journaled.Signer = func(from common.Address, transaction *types.Transaction) (*types.Transaction, error) {
+		signedTransaction, err := auth.Signer(from, transaction)
+		if err != nil {
+			return nil, err
+		}
+		inFlight.transaction = signedTransaction
+		return signedTransaction, nil
+	}
+	inFlight.name, inFlight.transaction, inFlight.sent = name, nil, false
+	run(&journaled)
+	signed := inFlight.transaction
+	inFlight.name, inFlight.transaction, inFlight.sent = "", nil, false
+	if signed == nil {
+		// Nothing to record, e.g. the CREATE2 deployment found the contract already deployed
+		return nil, false
+	}
+
+	receipt, err := ethClient.TransactionReceipt(ctx, signed.Hash())
+	if err != nil {
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatalf("Failed to get the receipt of %s: %v", name, err)
+	}
+	if err := runJournal.Mined(name, receipt); err != nil {
+		utils.Fatalf("Failed to update the journal: %v", err)
+	}
+	return receipt, false
+}
+
+// recordInFlightStep records the step being run as sent when the run stops on an error sending its transaction,
+// e.g. an interruption or a timeout, as the node may have accepted it anyway: a resumed run then waits for it instead
+// of sending it twice. It is not recorded if the node doesn't know the transaction, i.e. rejected it: a resumed run
+// then runs the step again. The transactions the node accepted are already recorded by journalingClient.
+func recordInFlightStep() {
+	if runJournal == nil || inFlight.transaction == nil || inFlight.sent {
+		return
+	}
+	// The context of the run is cancelled on an interruption
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, _, err := ethClient.TransactionByHash(ctx, inFlight.transaction.Hash())
+	if errors.Is(err, ethereum.NotFound) {
+		log.Printf("The transaction of %s (%s) is unknown to the node, the step is not recorded in the journal", inFlight.name, inFlight.transaction.Hash().Hex())
+		return
+	}
+	// Recorded as well when the node can't tell, a resumed run broadcasts the transaction again if needed
+	if err := runJournal.Sent(inFlight.name, inFlight.transaction); err != nil {
+		log.Printf("WARNING: Failed to update the journal: %v", err)
+	}
+}
+
+// resumeStep returns the receipt of a step recorded by a previous run, waiting for it if needed.
+// It returns nil if the step has to be run again, i.e. its transaction is unknown and can't be broadcast.
+func resumeStep(ctx context.Context, step journal.Step) *types.Receipt {
+	hash := common.HexToHash(step.TransactionHash)
+	if step.Status == journal.StatusMined {
+		receipt, err := ethClient.TransactionReceipt(ctx, hash)
+		if err == nil {
+			checkResumedReceipt(ctx, step, nil, receipt)
+			log.Printf("Step %s is already mined in block %s (transaction: %s), skipping it", step.Name, receipt.BlockNumber, step.TransactionHash)
+			return receipt
+		}
+		transactions.ExitIfInterrupted(ctx)
+		if !errors.Is(err, ethereum.NotFound) {
+			utils.Fatalf("Failed to get the receipt of %s: %v", step.Name, err)
+		}
+		log.Printf("WARNING: The mined transaction of %s (%s) is not found anymore, e.g. after a reorg. Waiting for it again.", step.Name, step.TransactionHash)
+	}
+
+	transaction, _, err := ethClient.TransactionByHash(ctx, hash)
+	if errors.Is(err, ethereum.NotFound) {
+		if step.RawTransaction == "" {
+			log.Printf("Transaction of %s (%s) is unknown to the node, running the step again", step.Name, step.TransactionHash)
+			return nil
+		}
+		// Same signed transaction, same nonce: it can't be executed twice
+		transaction, err = step.Transaction()
+		if err != nil {
+			utils.Fatalf("Failed to resume %s: %v", step.Name, err)
+		}
+		log.Printf("Transaction of %s (%s) is unknown to the node, broadcasting it again", step.Name, step.TransactionHash)
+		if err := ethClient.SendTransaction(ctx, transaction); err != nil {
+			transactions.ExitIfInterrupted(ctx)
+			utils.Fatalf("Failed to broadcast the transaction of %s again: %v. Its nonce (%d) may have been used by another transaction.", step.Name, err, step.Nonce)
+		}
+	} else if err != nil {
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatalf("Failed to get the transaction of %s: %v", step.Name, err)
+	}
+
+	log.Printf("Step %s was sent by the previous run, waiting for transaction: %s", step.Name, step.TransactionHash)
+	receipt := transactions.WaitMined(ctx, ethClient, transaction, timeout)
+	if err := runJournal.Mined(step.Name, receipt); err != nil {
+		utils.Fatalf("Failed to update the journal: %v", err)
+	}
+	checkResumedReceipt(ctx, step, transaction, receipt)
+	return receipt
+}
+
+// checkResumedReceipt stops the run if the transaction of a step recorded by a previous run failed, as the step
+// would have stopped that run. The transaction is looked up when not given, to replay it for its revert reason.
+func checkResumedReceipt(ctx context.Context, step journal.Step, transaction *types.Transaction, receipt *types.Receipt) {
+	if receipt.Status == types.ReceiptStatusSuccessful {
+		return
+	}
+	if transaction == nil {
+		var err error
+		if transaction, _, err = ethClient.TransactionByHash(ctx, receipt.TxHash); err != nil {
+			transactions.ExitIfInterrupted(ctx)
+			if transaction, err = step.Transaction(); err != nil {
+				utils.Fatalf("Failed to resume %s: its transaction %s failed in block %s, and can't be replayed: %v", step.Name, step.TransactionHash, receipt.BlockNumber, err)
+			}
+		}
+	}
+	if err := reverts.CheckReceipt(ctx, ethClient, step.Name, transaction, receipt); err != nil {
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatalf("Failed to resume %s: %v. Start a new run without -resume once the cause is fixed.", step.Name, err)
+	}
+}
@@ -0,0 +1,249 @@
+package reverts
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"main/src/evm/clients/geth/backend"
+	"math/big"
+	"strings"
+	"sync"
+)
+
+// The selectors of the errors the Solidity compiler itself emits
+var (
+	errorSelector = common.FromHex("0x08c379a0") // Error(string), emitted by require and revert with a message
+	panicSelector = common.FromHex("0x4e487b71") // Panic(uint256), emitted by failing assertions and checks
+)
+
+// panicReasons describes the Panic(uint256) codes, see https://docs.soliditylang.org/en/latest/control-structures.html#panic-via-assert-and-error-via-require
+var panicReasons = map[uint64]string{
+	0x00: "generic compiler inserted panic",
+	0x01: "assertion failed",
+	0x11: "arithmetic underflow or overflow",
+	0x12: "division or modulo by zero",
+	0x21: "conversion to an invalid enum value",
+	0x22: "incorrectly encoded storage byte array",
+	0x31: "pop() on an empty array",
+	0x32: "array index out of bounds",
+	0x41: "too much memory allocated",
+	0x51: "call to a zero-initialized internal function",
+}
+
+// vmErrors are the messages of the EVM failures which return no revert data
+var vmErrors = []string{"execution reverted", "out of gas", "invalid opcode", "stack underflow", "stack overflow", "invalid jump destination", "write protection"}
+
+// Revert is a failed call or transaction and its decoded reason
+type Revert struct {
+	Call            string `json:"call"` // what was called, e.g. setUint256
+	TransactionHash string `json:"transactionHash,omitempty"`
+	BlockNumber     uint64 `json:"blockNumber,omitempty"`
+	Reason          string `json:"reason"`
+	Data            string `json:"data,omitempty"` // the raw revert data, hex encoded
+}
+
+var registry = struct {
+	sync.Mutex
+	abis    []abi.ABI
+	reverts []Revert
+}{}
+
+// RegisterABI makes the custom errors of the ABI known to the decoding
+func RegisterABI(contractABI abi.ABI) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.abis = append(registry.abis, contractABI)
+}
+
+// Reverts returns the reverts decoded during the run, in the order they happened
+func Reverts() []Revert {
+	registry.Lock()
+	defer registry.Unlock()
+	return append([]Revert(nil), registry.reverts...)
+}
+
+func record(revert Revert) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.reverts = append(registry.reverts, revert)
+}
+
+// Decode decodes the revert data: Error(string), Panic(uint256) and the custom errors of the given
+// and the registered ABIs. Unknown data is returned hex encoded.
+//
+// Parameters:
+// - data: the revert data ([]byte)
+// - abis: the ABIs to look the custom errors up in, besides the registered ones ([]abi.ABI)
+// Returns:
+// - string: the reason
+func Decode(data []byte, abis ...abi.ABI) string {
+	if len(data) == 0 {
+		return "reverted without a reason"
+	}
+	if len(data) < 4 {
+		return fmt.Sprintf("reverted with invalid data %s", hexutil.Encode(data))
+	}
+	selector := data[:4]
+	switch {
+	case bytes.Equal(selector, errorSelector):
+		if message, err := abi.UnpackRevert(data); err == nil {
+			return fmt.Sprintf("Error(%q)", message)
+		}
+	case bytes.Equal(selector, panicSelector):
+		if len(data) == 4+32 {
+			code := new(big.Int).SetBytes(data[4:])
+			description, ok := panicReasons[code.Uint64()]
+			if !ok || !code.IsUint64() {
+				description = "unknown panic code"
+			}
+			return fmt.Sprintf("Panic(0x%x): %s", code, description)
+		}
+	default:
+		registry.Lock()
+		abis = append(abis, registry.abis...)
+		registry.Unlock()
+		for _, contractABI := range abis {
+			for _, customError := range contractABI.Errors {
+				if !bytes.Equal(customError.ID[:4], selector) {
+					continue
+				}
+				if decoded, err := customError.Unpack(data); err == nil {
+					return formatCustomError(customError, decoded)
+				}
+			}
+		}
+	}
+	return fmt.Sprintf("unknown error %s", hexutil.Encode(data))
+}
+
+// formatCustomError formats the custom error like its Solidity signature, with the values of its arguments
+func formatCustomError(customError abi.Error, decoded interface{}) string {
+	values, ok := decoded.([]interface{})
+	if !ok {
+		return customError.Name + "()"
+	}
+	arguments := make([]string, len(values))
+	for i, value := range values {
+		arguments[i] = fmt.Sprintf("%v", value)
+		if customError.Inputs[i].Name != "" {
+			arguments[i] = customError.Inputs[i].Name + ": " + arguments[i]
+		}
+	}
+	return fmt.Sprintf("%s(%s)", customError.Name, strings.Join(arguments, ", "))
+}
+
+// DataFromError extracts the revert data of a failed eth_call or eth_estimateGas from the error of the node
+//
+// Parameters:
+// - err: the error returned by the client (error)
+// Returns:
+// - []byte: the revert data
+// - bool: whether the error carries revert data
+func DataFromError(err error) ([]byte, bool) {
+	var dataError interface{ ErrorData() interface{} }
+	if !errors.As(err, &dataError) {
+		return nil, false
+	}
+	encoded, ok := dataError.ErrorData().(string)
+	if !ok {
+		return nil, false
+	}
+	data, decodeErr := hexutil.Decode(encoded)
+	if decodeErr != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// Explain adds the decoded revert reason to the error of a call which the node rejected before sending,
+// e.g. when eth_estimateGas reverts, and records the revert for the run report.
+// Errors without revert data are returned unchanged.
+//
+// Parameters:
+// - call: what was called, e.g. setUint256 (string)
+// - err: the error returned by the client (error)
+// Returns:
+// - error
+func Explain(call string, err error) error {
+	data, ok := DataFromError(err)
+	if !ok {
+		return err
+	}
+	reason := Decode(data)
+	record(Revert{Call: call, Reason: reason, Data: hexutil.Encode(data)})
+	return fmt.Errorf("%w, revert reason: %s", err, reason)
+}
+
+// Replay runs the transaction again with eth_call on the state of its block's parent, to get its revert data.
+// The transactions before it in the same block are not applied, so a failure caused by them can't be replayed.
+//
+// Parameters:
+// - ctx: the context of the run (context.Context)
+// - client: the Ethereum client (backend.Client)
+// - transaction: the failed transaction (*types.Transaction)
+// - receipt: its receipt (*types.Receipt)
+// Returns:
+// - string: the reason
+// - []byte: the revert data, nil if the node returned none
+// - error if the replay can't be run, e.g. when the node pruned the state of the block
+func Replay(ctx context.Context, client backend.Client, transaction *types.Transaction, receipt *types.Receipt) (string, []byte, error) {
+	from, err := types.Sender(types.LatestSignerForChainID(transaction.ChainId()), transaction)
+	if err != nil {
+		return "", nil, fmt.Errorf("could not recover the sender of %s: %w", transaction.Hash().Hex(), err)
+	}
+	var block *big.Int
+	if receipt.BlockNumber != nil && receipt.BlockNumber.Sign() > 0 {
+		block = new(big.Int).Sub(receipt.BlockNumber, big.NewInt(1))
+	}
+	call := ethereum.CallMsg{From: from, To: transaction.To(), Gas: transaction.Gas(), Value: transaction.Value(), Data: transaction.Data()}
+	_, err = client.CallContract(ctx, call, block)
+	if err == nil {
+		return "the replay succeeded, the failure depends on the transactions before it in its block", nil, nil
+	}
+	if data, ok := DataFromError(err); ok {
+		return Decode(data), data, nil
+	}
+	// The failures of the EVM without revert data, e.g. out of gas, are only described by the error
+	for _, vmError := range vmErrors {
+		if strings.Contains(err.Error(), vmError) {
+			return err.Error(), nil, nil
+		}
+	}
+	return "", nil, fmt.Errorf("could not replay %s: %w", transaction.Hash().Hex(), err)
+}
+
+// CheckReceipt returns nil if the transaction succeeded. Otherwise, the transaction is replayed to decode
+// its revert reason, which is recorded for the run report and returned in the error.
+//
+// Parameters:
+// - ctx: the context of the run (context.Context)
+// - client: the Ethereum client (backend.Client)
+// - call: what was called, e.g. setUint256 (string)
+// - transaction: the mined transaction (*types.Transaction)
+// - receipt: its receipt (*types.Receipt)
+// Returns:
+// - error describing the failure and its reason
+func CheckReceipt(ctx context.Context, client backend.Client, call string, transaction *types.Transaction, receipt *types.Receipt) error {
+	if receipt.Status == types.ReceiptStatusSuccessful {
+		return nil
+	}
+	reason, data, err := Replay(ctx, client, transaction, receipt)
+	if err != nil {
+		reason = fmt.Sprintf("unknown, %v", err)
+	}
+	revert := Revert{Call: call, TransactionHash: receipt.TxHash.Hex(), Reason: reason}
+	if receipt.BlockNumber != nil {
+		revert.BlockNumber = receipt.BlockNumber.Uint64()
+	}
+	if data != nil {
+		revert.Data = hexutil.Encode(data)
+	}
+	record(revert)
+	return fmt.Errorf("%s transaction %s failed in block %d, revert reason: %s", call, revert.TransactionHash, revert.BlockNumber, reason)
+}
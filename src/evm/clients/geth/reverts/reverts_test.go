@@ -0,0 +1,152 @@
+package reverts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"main/src/evm/clients/geth/backend"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+const customErrorsABI = `[{"type":"error","name":"InsufficientBalance","inputs":[{"name":"available","type":"uint256"},{"name":"required","type":"uint256"}]},{"type":"error","name":"Unauthorized","inputs":[]}]`
+
+// encodeError returns the revert data of the error with the given signature and arguments
+func encodeError(t *testing.T, signature string, arguments abi.Arguments, values ...interface{}) []byte {
+	t.Helper()
+	packed, err := arguments.Pack(values...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append(crypto.Keccak256([]byte(signature))[:4], packed...)
+}
+
+func TestDecode(t *testing.T) {
+	contractABI, err := abi.JSON(strings.NewReader(customErrorsABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stringType, _ := abi.NewType("string", "", nil)
+	uint256Type, _ := abi.NewType("uint256", "", nil)
+
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{name: "no data", data: nil, want: "reverted without a reason"},
+		{name: "Error(string)", data: encodeError(t, "Error(string)", abi.Arguments{{Type: stringType}}, "value too low"), want: `Error("value too low")`},
+		{name: "Panic(uint256) overflow", data: encodeError(t, "Panic(uint256)", abi.Arguments{{Type: uint256Type}}, big.NewInt(0x11)), want: "Panic(0x11): arithmetic underflow or overflow"},
+		{name: "Panic(uint256) unknown code", data: encodeError(t, "Panic(uint256)", abi.Arguments{{Type: uint256Type}}, big.NewInt(0x99)), want: "Panic(0x99): unknown panic code"},
+		{name: "custom error", data: encodeError(t, "InsufficientBalance(uint256,uint256)", contractABI.Errors["InsufficientBalance"].Inputs, big.NewInt(1), big.NewInt(2)), want: "InsufficientBalance(available: 1, required: 2)"},
+		{name: "custom error without arguments", data: encodeError(t, "Unauthorized()", nil), want: "Unauthorized()"},
+		{name: "unknown selector", data: common.FromHex("0xdeadbeef"), want: "unknown error 0xdeadbeef"},
+		{name: "truncated Error(string)", data: common.FromHex("0x08c379a0"), want: "unknown error 0x08c379a0"},
+		{name: "shorter than a selector", data: common.FromHex("0x01"), want: "reverted with invalid data 0x01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Decode(tt.data, contractABI); got != tt.want {
+				t.Errorf("Decode() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// dataError is an error with revert data, like the ones of the RPC client
+type dataError struct{ data interface{} }
+
+func (e dataError) Error() string          { return "execution reverted" }
+func (e dataError) ErrorData() interface{} { return e.data }
+
+func TestExplain(t *testing.T) {
+	plain := errors.New("insufficient funds for gas * price + value")
+	if got := Explain("setUint256", plain); got != plain {
+		t.Errorf("Explain() of an error without revert data = %v, want it unchanged", got)
+	}
+
+	wrapped := fmt.Errorf("all RPC endpoints failed, last error: %w", dataError{data: "0x4e487b710000000000000000000000000000000000000000000000000000000000000001"})
+	explained := Explain("setUint256", wrapped)
+	if !strings.Contains(explained.Error(), "revert reason: Panic(0x1): assertion failed") {
+		t.Errorf("Explain() = %v, want the decoded reason", explained)
+	}
+	if !errors.Is(explained, wrapped) {
+		t.Error("Explain() does not wrap the original error")
+	}
+	reverts := Reverts()
+	if len(reverts) == 0 || reverts[len(reverts)-1].Call != "setUint256" || reverts[len(reverts)-1].Reason != "Panic(0x1): assertion failed" {
+		t.Errorf("Reverts() = %+v, want the explained revert recorded", reverts)
+	}
+
+	if _, ok := DataFromError(dataError{data: 3}); ok {
+		t.Error("DataFromError() accepted error data which is not hex encoded")
+	}
+}
+
+// revertingCode returns runtime code which reverts with the given data whatever the call
+func revertingCode(data []byte) []byte {
+	length := byte(len(data))
+	// CODECOPY the data after these 12 bytes to memory, then REVERT with it
+	code := []byte{0x60, length, 0x60, 0x0c, 0x60, 0x00, 0x39, 0x60, length, 0x60, 0x00, 0xfd}
+	return append(code, data...)
+}
+
+func TestCheckReceiptReplaysTheFailedTransaction(t *testing.T) {
+	ctx := context.Background()
+	key, err := crypto.HexToECDSA("ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	reverting := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	revertData := hexutil.MustDecode("0x4e487b710000000000000000000000000000000000000000000000000000000000000012")
+	simulatedClient := backend.NewSimulatedClient(types.GenesisAlloc{
+		from:      {Balance: big.NewInt(params.Ether)},
+		reverting: {Code: revertingCode(revertData)},
+	})
+	t.Cleanup(func() { simulatedClient.Close() })
+
+	// The node reports the revert data of a call
+	_, err = simulatedClient.CallContract(ctx, ethereum.CallMsg{From: from, To: &reverting}, nil)
+	if data, ok := DataFromError(err); !ok || !strings.EqualFold(hexutil.Encode(data), hexutil.Encode(revertData)) {
+		t.Fatalf("DataFromError(%v) = %x, %v, want %x", err, data, ok, revertData)
+	}
+
+	// With a gas limit, nothing is estimated, so the transaction is mined and fails
+	auth, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth.GasLimit = 100000
+	transaction, err := bind.NewBoundContract(reverting, abi.ABI{}, simulatedClient, simulatedClient, simulatedClient).RawTransact(auth, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	receipt, err := bind.WaitMined(ctx, simulatedClient, transaction)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = CheckReceipt(ctx, simulatedClient, "divide", transaction, receipt)
+	if err == nil || !strings.Contains(err.Error(), "revert reason: Panic(0x12): division or modulo by zero") {
+		t.Fatalf("CheckReceipt() error = %v, want the decoded reason", err)
+	}
+	reverts := Reverts()
+	last := reverts[len(reverts)-1]
+	if last.TransactionHash != transaction.Hash().Hex() || last.BlockNumber != receipt.BlockNumber.Uint64() || last.Data != hexutil.Encode(revertData) {
+		t.Errorf("recorded revert = %+v, want the transaction, its block and its data", last)
+	}
+
+	successful := &types.Receipt{Status: types.ReceiptStatusSuccessful}
+	if err := CheckReceipt(ctx, simulatedClient, "divide", transaction, successful); err != nil {
+		t.Errorf("CheckReceipt() of a successful receipt error = %v", err)
+	}
+}
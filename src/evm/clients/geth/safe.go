@@ -0,0 +1,116 @@
+package geth
+
+import (
+	"context"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"log"
+	"main/src/config"
+	"main/src/contracts/getter_setter"
+	"main/src/evm/clients/geth/backend"
+	"main/src/evm/clients/geth/safe"
+	"main/src/evm/clients/geth/transactions"
+	"main/src/evm/clients/geth/types"
+	"main/src/utils"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// txBuilderVersion is the version of the Transaction Builder app the batch file is written for
+const txBuilderVersion = "1.16.5"
+
+// safeTransaction converts the transaction for the output
+func safeTransaction(transaction safe.Transaction) types.SafeTransaction {
+	return types.SafeTransaction{
+		To:        transaction.To.Hex(),
+		Value:     transaction.Value.String(),
+		Data:      hexutil.Encode(transaction.Data),
+		Operation: uint8(transaction.Operation),
+	}
+}
+
+// ProposeSafeTransactions builds, instead of sending them, the setter transactions of the configured values as
+// a Transaction Builder batch file, for the owners of a Safe owning the contract to import. When the Safe
+// address is configured, the safeTxHash of the transaction the Safe is going to execute is computed too, with
+// the configured nonce or else the current nonce of the Safe, so the signers can check what they sign.
+//
+// Parameters:
+// - ctx: the context of the run (context.Context)
+// - values: the values to set in the contract (config.Values)
+// - contractAddress: the address of the GetterSetter contract (string)
+// - safeConfig: the Safe settings (config.Safe)
+// - ownerAddress: the address of the owner proposing the transactions, if known (common.Address)
+// - ethClient: the Ethereum client for interaction (backend.Client)
+// Returns:
+// - types.SafeTransactionBuilderFile
+// - types.SafeProposalInformation
+func ProposeSafeTransactions(ctx context.Context, values config.Values, contractAddress string, safeConfig config.Safe, ownerAddress common.Address, ethClient backend.Client) (types.SafeTransactionBuilderFile, types.SafeProposalInformation) {
+	getterSetterABI, err := getter_setter.GetterSetterMetaData.GetAbi()
+	if err != nil {
+		utils.Fatalf("Failed to parse the GetterSetter ABI: %v", err)
+	}
+	chainID := transactions.GetChainId(ctx, ethClient)
+	to := common.HexToAddress(contractAddress)
+
+	var setters []string
+	var safeTransactions []safe.Transaction
+	builderFile := types.SafeTransactionBuilderFile{Version: "1.0", ChainId: chainID.String(), CreatedAt: time.Now().UnixMilli()}
+	for _, data := range setterCalldata(getterSetterABI, values) {
+		method, err := getterSetterABI.MethodById(data[:4])
+		if err != nil {
+			utils.Fatalf("Failed to find the setter of %x: %v", data[:4], err)
+		}
+		transaction := safe.Transaction{To: to, Value: big.NewInt(0), Data: data, Operation: safe.Call}
+		setters = append(setters, method.Name)
+		safeTransactions = append(safeTransactions, transaction)
+		builderFile.Transactions = append(builderFile.Transactions, safeTransaction(transaction))
+	}
+	builderFile.Meta = types.SafeTransactionBuilderMeta{
+		Name:             "GetterSetter setters",
+		Description:      fmt.Sprintf("%s on %s", strings.Join(setters, ", "), to.Hex()),
+		TxBuilderVersion: txBuilderVersion,
+	}
+	if ownerAddress != (common.Address{}) {
+		builderFile.Meta.CreatedFromOwnerAddress = ownerAddress.Hex()
+	}
+
+	multiSend := safe.MultiSendCallOnlyAddress
+	if safeConfig.MultiSend != "" {
+		multiSend = common.HexToAddress(safeConfig.MultiSend)
+	}
+	executed, err := safe.Batch(safeTransactions, multiSend)
+	if err != nil {
+		utils.Fatalf("Failed to batch the setters: %v", err)
+	}
+	proposal := types.SafeProposalInformation{ChainId: chainID.String(), Setters: setters, Transaction: safeTransaction(executed)}
+	if len(safeTransactions) > 1 {
+		log.Printf("The %d setters are batched with a DELEGATECALL to the MultiSendCallOnly contract at %s", len(safeTransactions), multiSend.Hex())
+	}
+	if safeConfig.Address == "" {
+		log.Println("Safe.Address is not set, the safeTxHash is not computed")
+		return builderFile, proposal
+	}
+
+	safeAddress := common.HexToAddress(safeConfig.Address)
+	builderFile.Meta.CreatedFromSafeAddress = safeAddress.Hex()
+	nonce := safeConfig.Nonce
+	if nonce == nil {
+		current, err := safe.Nonce(ctx, ethClient, safeAddress)
+		if err != nil {
+			transactions.ExitIfInterrupted(ctx)
+			utils.Fatalf("Failed to read the nonce of the Safe, set Safe.Nonce: %v", err)
+		}
+		nonce = &current
+	}
+	safeTxHash, err := safe.TransactionHash(chainID, safeAddress, executed, *nonce)
+	if err != nil {
+		utils.Fatalf("Failed to compute the safeTxHash: %v", err)
+	}
+	proposal.SafeAddress = safeAddress.Hex()
+	proposal.Nonce = nonce
+	proposal.SafeTxHash = safeTxHash.Hex()
+	log.Printf("Safe %s, nonce %d, safeTxHash: %s", safeAddress.Hex(), *nonce, safeTxHash.Hex())
+	return builderFile, proposal
+}
@@ -0,0 +1,171 @@
+package safe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"main/src/evm/clients/geth/backend"
+	"math/big"
+	"strings"
+)
+
+// Operation is how the Safe executes a transaction
+type Operation uint8
+
+const (
+	Call         Operation = 0
+	DelegateCall Operation = 1
+)
+
+// MultiSendCallOnlyAddress is the MultiSendCallOnly contract of Safe v1.3.0, deployed at the same address on the
+// public networks, which the Transaction Builder batches several transactions with
+var MultiSendCallOnlyAddress = common.HexToAddress("0x40A2aCCbd92BCA938b02010E17A5b8929b49130D")
+
+// nonceSelector is the selector of nonce() of the Safe
+var nonceSelector = hexutil.MustDecode("0xaffed0e0")
+
+// multiSendABI is the method of the MultiSend contracts taking the packed transactions
+const multiSendABI = `[{"type":"function","name":"multiSend","stateMutability":"payable","inputs":[{"name":"transactions","type":"bytes"}],"outputs":[]}]`
+
+// Transaction is a transaction executed by the Safe
+type Transaction struct {
+	To        common.Address
+	Value     *big.Int
+	Data      []byte
+	Operation Operation
+}
+
+// safeTxTypes are the EIP-712 types of a Safe transaction, as of Safe v1.3.0
+var safeTxTypes = apitypes.Types{
+	"EIP712Domain": {
+		{Name: "chainId", Type: "uint256"},
+		{Name: "verifyingContract", Type: "address"},
+	},
+	"SafeTx": {
+		{Name: "to", Type: "address"},
+		{Name: "value", Type: "uint256"},
+		{Name: "data", Type: "bytes"},
+		{Name: "operation", Type: "uint8"},
+		{Name: "safeTxGas", Type: "uint256"},
+		{Name: "baseGas", Type: "uint256"},
+		{Name: "gasPrice", Type: "uint256"},
+		{Name: "gasToken", Type: "address"},
+		{Name: "refundReceiver", Type: "address"},
+		{Name: "nonce", Type: "uint256"},
+	},
+}
+
+// Batch returns the transaction the Safe executes for the transactions: the transaction itself if there is
+// only one, otherwise a DELEGATECALL to multiSend of the MultiSend contract, as the Transaction Builder does.
+//
+// Parameters:
+// - transactions: the transactions to batch, executed with a CALL ([]Transaction)
+// - multiSend: the address of the MultiSend contract (common.Address)
+// Returns:
+// - Transaction
+// - error if there is no transaction or one of them is not a CALL
+func Batch(transactions []Transaction, multiSend common.Address) (Transaction, error) {
+	if len(transactions) == 0 {
+		return Transaction{}, errors.New("no transaction to batch")
+	}
+	if len(transactions) == 1 {
+		return transactions[0], nil
+	}
+	var packed []byte
+	for i, transaction := range transactions {
+		if transaction.Operation != Call {
+			return Transaction{}, fmt.Errorf("transaction %d is a DELEGATECALL, MultiSendCallOnly only batches CALLs", i)
+		}
+		value := transaction.Value
+		if value == nil {
+			value = new(big.Int)
+		}
+		packed = append(packed, byte(transaction.Operation))
+		packed = append(packed, transaction.To.Bytes()...)
+		packed = append(packed, math.U256Bytes(new(big.Int).Set(value))...)
+		packed = append(packed, math.U256Bytes(big.NewInt(int64(len(transaction.Data))))...)
+		packed = append(packed, transaction.Data...)
+	}
+	parsed, err := abi.JSON(strings.NewReader(multiSendABI))
+	if err != nil {
+		return Transaction{}, err
+	}
+	data, err := parsed.Pack("multiSend", packed)
+	if err != nil {
+		return Transaction{}, err
+	}
+	return Transaction{To: multiSend, Value: new(big.Int), Data: data, Operation: DelegateCall}, nil
+}
+
+// TransactionHash returns the safeTxHash of the transaction, the EIP-712 hash the owners of the Safe sign.
+// The gas refund fields are zero, as in the transactions proposed with the Safe apps.
+//
+// Parameters:
+// - chainID: the chain ID of the Safe (*big.Int)
+// - safeAddress: the address of the Safe (common.Address)
+// - transaction: the transaction executed by the Safe (Transaction)
+// - nonce: the nonce of the Safe the transaction is proposed with (uint64)
+// Returns:
+// - common.Hash
+// - error if the transaction can't be hashed
+func TransactionHash(chainID *big.Int, safeAddress common.Address, transaction Transaction, nonce uint64) (common.Hash, error) {
+	value := transaction.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+	typedData := apitypes.TypedData{
+		Types:       safeTxTypes,
+		PrimaryType: "SafeTx",
+		Domain: apitypes.TypedDataDomain{
+			ChainId:           (*math.HexOrDecimal256)(chainID),
+			VerifyingContract: safeAddress.Hex(),
+		},
+		Message: apitypes.TypedDataMessage{
+			"to":             transaction.To.Hex(),
+			"value":          value.String(),
+			"data":           hexutil.Encode(transaction.Data),
+			"operation":      fmt.Sprint(uint8(transaction.Operation)),
+			"safeTxGas":      "0",
+			"baseGas":        "0",
+			"gasPrice":       "0",
+			"gasToken":       common.Address{}.Hex(),
+			"refundReceiver": common.Address{}.Hex(),
+			"nonce":          new(big.Int).SetUint64(nonce).String(),
+		},
+	}
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to hash the Safe transaction: %w", err)
+	}
+	return common.BytesToHash(hash), nil
+}
+
+// Nonce reads the current nonce of the Safe, i.e. the nonce of the next transaction it executes
+//
+// Parameters:
+// - ctx: the context of the run (context.Context)
+// - client: the Ethereum client (backend.Client)
+// - safeAddress: the address of the Safe (common.Address)
+// Returns:
+// - uint64
+// - error if the call fails or the address doesn't answer nonce() like a Safe
+func Nonce(ctx context.Context, client backend.Client, safeAddress common.Address) (uint64, error) {
+	output, err := client.CallContract(ctx, ethereum.CallMsg{To: &safeAddress, Data: nonceSelector}, nil)
+	if err != nil {
+		return 0, fmt.Errorf("nonce() of the Safe %s failed: %w", safeAddress.Hex(), err)
+	}
+	if len(output) != 32 {
+		return 0, fmt.Errorf("%s is not a Safe, nonce() returned %d bytes", safeAddress.Hex(), len(output))
+	}
+	nonce := new(big.Int).SetBytes(output)
+	if !nonce.IsUint64() {
+		return 0, fmt.Errorf("the nonce of the Safe %s is too large: %s", safeAddress.Hex(), nonce)
+	}
+	return nonce.Uint64(), nil
+}
@@ -0,0 +1,144 @@
+package safe
+
+import (
+	"bytes"
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"main/src/evm/clients/geth/backend"
+	"math/big"
+	"testing"
+)
+
+// The type hashes of the Safe v1.3.0 contracts
+var (
+	domainSeparatorTypehash = common.HexToHash("0x47e79534a245952e8b16893a336b85a3d9ea9fa8c573f3d803afb92a79469218")
+	safeTxTypehash          = common.HexToHash("0xbb8310d486368db6bd6f849402fdd73ad53d316b5a4b2644ad6efe0f941286d8")
+)
+
+// expectedTransactionHash computes the safeTxHash as getTransactionHash of the Safe contract does
+func expectedTransactionHash(chainID *big.Int, safeAddress common.Address, transaction Transaction, nonce uint64) common.Hash {
+	word := func(value *big.Int) []byte { return math.U256Bytes(new(big.Int).Set(value)) }
+	domainSeparator := crypto.Keccak256(domainSeparatorTypehash.Bytes(), word(chainID), common.LeftPadBytes(safeAddress.Bytes(), 32))
+	zero := make([]byte, 32)
+	safeTx := crypto.Keccak256(
+		safeTxTypehash.Bytes(),
+		common.LeftPadBytes(transaction.To.Bytes(), 32),
+		word(transaction.Value),
+		crypto.Keccak256(transaction.Data),
+		word(big.NewInt(int64(transaction.Operation))),
+		zero, zero, zero, zero, zero,
+		word(new(big.Int).SetUint64(nonce)),
+	)
+	return crypto.Keccak256Hash([]byte{0x19, 0x01}, domainSeparator, safeTx)
+}
+
+func TestTransactionHash(t *testing.T) {
+	safeAddress := common.HexToAddress("0x2Cc1D4F1BE4a6bf1d4FE3b2c8A7a8dA3e3e0Cf4A")
+	getterSetter := common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3")
+	tests := []struct {
+		name        string
+		chainID     *big.Int
+		transaction Transaction
+		nonce       uint64
+	}{
+		{
+			name:        "setUint256",
+			chainID:     big.NewInt(11155111),
+			transaction: Transaction{To: getterSetter, Value: big.NewInt(0), Data: hexutil.MustDecode("0xd2282dc5000000000000000000000000000000000000000000000000000000000000002a")},
+			nonce:       7,
+		},
+		{
+			name:        "value without data",
+			chainID:     big.NewInt(1),
+			transaction: Transaction{To: getterSetter, Value: big.NewInt(1e18)},
+		},
+		{
+			name:        "delegatecall",
+			chainID:     big.NewInt(17000),
+			transaction: Transaction{To: MultiSendCallOnlyAddress, Value: big.NewInt(0), Data: []byte{0x8d, 0x80, 0xff, 0x0a}, Operation: DelegateCall},
+			nonce:       1 << 40,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := TransactionHash(tt.chainID, safeAddress, tt.transaction, tt.nonce)
+			if err != nil {
+				t.Fatalf("TransactionHash() error = %v", err)
+			}
+			if want := expectedTransactionHash(tt.chainID, safeAddress, tt.transaction, tt.nonce); got != want {
+				t.Errorf("TransactionHash() = %s, want %s", got.Hex(), want.Hex())
+			}
+		})
+	}
+}
+
+func TestBatch(t *testing.T) {
+	first := Transaction{To: common.HexToAddress("0x01"), Value: big.NewInt(0), Data: []byte{1, 2, 3}}
+	second := Transaction{To: common.HexToAddress("0x02"), Value: big.NewInt(5)}
+
+	single, err := Batch([]Transaction{first}, MultiSendCallOnlyAddress)
+	if err != nil || single.To != first.To || single.Operation != Call || !bytes.Equal(single.Data, first.Data) {
+		t.Errorf("Batch() of a single transaction = %+v, %v, want the transaction itself", single, err)
+	}
+
+	batch, err := Batch([]Transaction{first, second}, MultiSendCallOnlyAddress)
+	if err != nil {
+		t.Fatalf("Batch() error = %v", err)
+	}
+	if batch.To != MultiSendCallOnlyAddress || batch.Operation != DelegateCall {
+		t.Errorf("Batch() = to %s, operation %d, want a DELEGATECALL to %s", batch.To.Hex(), batch.Operation, MultiSendCallOnlyAddress.Hex())
+	}
+	// multiSend(bytes): the selector, the offset, the length, then operation, to, value, data length and data of each transaction
+	packed := append([]byte{0}, first.To.Bytes()...)
+	packed = append(packed, make([]byte, 32)...)
+	packed = append(packed, common.LeftPadBytes([]byte{3}, 32)...)
+	packed = append(packed, first.Data...)
+	packed = append(packed, 0)
+	packed = append(packed, second.To.Bytes()...)
+	packed = append(packed, common.LeftPadBytes([]byte{5}, 32)...)
+	packed = append(packed, make([]byte, 32)...)
+	if !bytes.Equal(batch.Data[:4], []byte{0x8d, 0x80, 0xff, 0x0a}) || !bytes.Equal(batch.Data[4+64:4+64+len(packed)], packed) {
+		t.Errorf("Batch() data = %x, want the packed transactions %x", batch.Data, packed)
+	}
+
+	if _, err := Batch(nil, MultiSendCallOnlyAddress); err == nil {
+		t.Error("Batch() of no transaction error = nil")
+	}
+	if _, err := Batch([]Transaction{first, {To: first.To, Operation: DelegateCall}}, MultiSendCallOnlyAddress); err == nil {
+		t.Error("Batch() of a DELEGATECALL error = nil")
+	}
+}
+
+func TestNonce(t *testing.T) {
+	safeAddress := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	client := backend.NewSimulatedClient(types.GenesisAlloc{
+		// returns 5 to any call
+		safeAddress: {Code: hexutil.MustDecode("0x600560005260206000f3")},
+	})
+	t.Cleanup(func() { client.Close() })
+
+	tests := []struct {
+		name    string
+		address common.Address
+		want    uint64
+		wantErr bool
+	}{
+		{name: "safe", address: safeAddress, want: 5},
+		{name: "account without code", address: common.HexToAddress("0x00000000000000000000000000000000000000bb"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Nonce(context.Background(), client, tt.address)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Nonce() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Nonce() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
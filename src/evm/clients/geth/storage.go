@@ -0,0 +1,181 @@
+package geth
+
+import (
+	"context"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"log"
+	"main/src/contracts/getter_setter"
+	"main/src/evm/clients/geth/backend"
+	"main/src/evm/clients/geth/client"
+	"main/src/evm/clients/geth/transactions"
+	"main/src/evm/clients/geth/types"
+	"main/src/utils"
+	"math/big"
+)
+
+// decodeBytesSlot decodes the slot of a bytes state variable, as solc encodes it: a value of up to 31 bytes
+// is stored left-aligned in the slot, with length*2 in its lowest byte; a longer value stores length*2+1
+// in the slot and its data from keccak256(slot), 32 bytes per slot.
+//
+// Parameters:
+// - raw: the content of the slot (common.Hash)
+// Returns:
+// - uint64: the length of the value
+// - bool: true if the value is short, i.e. stored in the slot itself
+// - error if the slot is not a valid encoding
+func decodeBytesSlot(raw common.Hash) (uint64, bool, error) {
+	value := raw.Big()
+	if value.Bit(0) == 0 {
+		length := uint64(raw[31]) / 2
+		if length > 31 {
+			return 0, false, fmt.Errorf("short encoding with a length of %d, more than 31 bytes", length)
+		}
+		for _, b := range raw[length:31] {
+			if b != 0 {
+				return 0, false, fmt.Errorf("short encoding of %d bytes with non-zero bytes after the value", length)
+			}
+		}
+		return length, true, nil
+	}
+	length := new(big.Int).Rsh(value, 1)
+	if length.Cmp(big.NewInt(32)) < 0 {
+		return 0, false, fmt.Errorf("long encoding with a length of %s, less than 32 bytes", length)
+	}
+	if length.Cmp(big.NewInt(getter_setter.MaxBytesDataSlots*32)) > 0 {
+		return 0, false, fmt.Errorf("long encoding with a length of %s, more than the %d bytes inspected", length, getter_setter.MaxBytesDataSlots*32)
+	}
+	return length.Uint64(), false, nil
+}
+
+// storageSlot compares the value decoded from the slot with the value returned by the getter
+func storageSlot(slot common.Hash, variable string, raw common.Hash, decoded string, getter string, getterErr error) types.StorageSlot {
+	storageSlot := types.StorageSlot{Slot: slot.Hex(), Variable: variable, Raw: raw.Hex(), Decoded: decoded, Getter: getter}
+	if getterErr != nil {
+		storageSlot.Error = fmt.Sprintf("%s() failed: %v", variable, getterErr)
+		return storageSlot
+	}
+	storageSlot.Match = decoded == getter
+	return storageSlot
+}
+
+// InspectGetterSetterStorage reads the GetterSetter state variables straight from their storage slots with
+// eth_getStorageAt, decodes them with the storage layout and compares them with the values returned by the getters,
+// all at the same block. A mismatch means that the code at the address doesn't store its values the way the
+// GetterSetter source does, e.g. another contract answering the same ABI.
+//
+// Parameters:
+// - ctx: the context of the run (context.Context)
+// - contractAddress: the address of the contract, or of its proxy (string)
+// - ethClient: the Ethereum client for interaction (backend.Client)
+// Return type:
+// - types.StorageInspectionInformation
+func InspectGetterSetterStorage(ctx context.Context, contractAddress string, ethClient backend.Client) types.StorageInspectionInformation {
+	if err := client.CheckContractCode(ctx, contractAddress, ethClient); err != nil {
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatalf("Failed to inspect the storage: %v", err)
+	}
+	address := common.HexToAddress(contractAddress)
+	// The getters are called even if they don't match the ABI, their errors are part of the report
+	getterSetter, err := getter_setter.NewGetterSetter(address, ethClient)
+	if err != nil {
+		utils.Fatalf("Failed to attach to the contract: %v", err)
+	}
+	blockNumber, err := ethClient.BlockNumber(ctx)
+	if err != nil {
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatalf("Failed to get the block number: %v", err)
+	}
+	block := new(big.Int).SetUint64(blockNumber)
+	callOpts := &bind.CallOpts{Context: ctx, BlockNumber: block}
+	readSlot := func(slot common.Hash) common.Hash {
+		value, err := ethClient.StorageAt(ctx, address, slot, block)
+		if err != nil {
+			transactions.ExitIfInterrupted(ctx)
+			utils.Fatalf("Failed to read the slot %s of %s: %v", slot.Hex(), contractAddress, err)
+		}
+		return common.BytesToHash(value)
+	}
+	log.Printf("Inspecting the storage of %s at block %d", contractAddress, blockNumber)
+
+	var slots []types.StorageSlot
+	raw := readSlot(getter_setter.Bytes32Slot)
+	bytes32Value, err := getterSetter.GetBytes32(callOpts)
+	getter := ""
+	if err == nil {
+		getter = hexutil.Encode(bytes32Value[:])
+	}
+	slots = append(slots, storageSlot(getter_setter.Bytes32Slot, "getBytes32", raw, raw.Hex(), getter, err))
+
+	raw = readSlot(getter_setter.Uint256Slot)
+	uint256Value, err := getterSetter.GetUint256(callOpts)
+	getter = ""
+	if err == nil {
+		getter = uint256Value.String()
+	}
+	slots = append(slots, storageSlot(getter_setter.Uint256Slot, "getUint256", raw, raw.Big().String(), getter, err))
+
+	raw = readSlot(getter_setter.RequestIdSlot)
+	requestId, err := getterSetter.RequestId(callOpts)
+	getter = ""
+	if err == nil {
+		getter = hexutil.Encode(requestId[:])
+	}
+	slots = append(slots, storageSlot(getter_setter.RequestIdSlot, "requestId", raw, raw.Hex(), getter, err))
+
+	slots = append(slots, inspectBytesSlot(callOpts, getterSetter, readSlot))
+
+	output := types.StorageInspectionInformation{ContractAddress: address.Hex(), BlockNumber: blockNumber, Slots: slots, Match: true}
+	for _, slot := range slots {
+		log.Printf("Slot %s (%s): storage %s, getter %s, match: %t", slot.Slot, slot.Variable, slot.Decoded, slot.Getter, slot.Match)
+		if slot.Error != "" {
+			log.Printf("Slot %s (%s): %s", slot.Slot, slot.Variable, slot.Error)
+		}
+		output.Match = output.Match && slot.Match
+	}
+	return output
+}
+
+// inspectBytesSlot decodes the getBytes value from its slot and, for a long value, from its data slots
+func inspectBytesSlot(callOpts *bind.CallOpts, getterSetter *getter_setter.GetterSetter, readSlot func(common.Hash) common.Hash) types.StorageSlot {
+	raw := readSlot(getter_setter.BytesSlot)
+	bytesValue, getterErr := getterSetter.GetBytes(callOpts)
+	getter := ""
+	if getterErr == nil {
+		getter = hexutil.Encode(bytesValue)
+	}
+
+	length, short, err := decodeBytesSlot(raw)
+	if err != nil {
+		slot := storageSlot(getter_setter.BytesSlot, "getBytes", raw, "", getter, getterErr)
+		slot.Match = false
+		if slot.Error != "" {
+			slot.Error = fmt.Sprintf("invalid bytes encoding: %v, %s", err, slot.Error)
+		} else {
+			slot.Error = fmt.Sprintf("invalid bytes encoding: %v", err)
+		}
+		return slot
+	}
+	var value []byte
+	var dataSlots []string
+	encoding := "short"
+	if short {
+		value = raw[:length]
+	} else {
+		encoding = "long"
+		start := getter_setter.BytesDataSlot.Big()
+		for i := uint64(0); i*32 < length; i++ {
+			data := readSlot(common.BigToHash(new(big.Int).Add(start, new(big.Int).SetUint64(i))))
+			dataSlots = append(dataSlots, data.Hex())
+			value = append(value, data.Bytes()...)
+		}
+		value = value[:length]
+	}
+	slot := storageSlot(getter_setter.BytesSlot, "getBytes", raw, hexutil.Encode(value), getter, getterErr)
+	slot.Encoding = encoding
+	slot.Length = &length
+	slot.DataSlots = dataSlots
+	return slot
+}
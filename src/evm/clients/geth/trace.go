@@ -0,0 +1,85 @@
+package geth
+
+import (
+	"context"
+	"errors"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"log"
+	"main/src/contracts/getter_setter"
+	"main/src/evm/clients/geth/artifacts"
+	"main/src/evm/clients/geth/tracing"
+	"main/src/evm/clients/geth/transactions"
+	"main/src/evm/clients/geth/types"
+	"main/src/utils"
+)
+
+var (
+	// traces are the setter transactions traced during the run, written to output/traces.json
+	traces []types.TransactionTrace
+	// tracingUnavailable is set once the node rejected debug_traceTransaction, the next transactions are not traced
+	tracingUnavailable bool
+)
+
+// traceTransaction traces the mined setter transaction when Client.Trace is set, and logs the storage slots
+// it changed and the events it emitted. Tracing is optional: when the node doesn't expose the debug namespace,
+// it is disabled for the rest of the run, and any other tracing failure is only logged.
+//
+// Parameters:
+// - ctx: the context of the run (context.Context)
+// - step: the setter, e.g. setUint256 (string)
+// - transaction: the mined transaction, sent to the GetterSetter contract (*gethtypes.Transaction)
+func traceTransaction(ctx context.Context, step string, transaction *gethtypes.Transaction) {
+	if !tomlConfig.Client.Trace || tracingUnavailable {
+		return
+	}
+	contract := *transaction.To()
+	slotName := func(address common.Address, slot common.Hash) string {
+		if address != contract {
+			return ""
+		}
+		return getter_setter.SlotName(slot)
+	}
+	trace, err := tracing.TraceTransaction(ctx, ethClient, transaction.Hash(), slotName)
+	if err != nil {
+		transactions.ExitIfInterrupted(ctx)
+		if errors.Is(err, tracing.ErrUnavailable) {
+			tracingUnavailable = true
+			log.Printf("WARNING: Client.Trace is set, but %v. The transactions are not traced.", err)
+			return
+		}
+		log.Printf("WARNING: Could not trace %s (%s): %v", step, transaction.Hash().Hex(), err)
+		return
+	}
+
+	getterSetterABI, err := getter_setter.GetterSetterMetaData.GetAbi()
+	if err != nil {
+		utils.Fatalf("Failed to parse the GetterSetter ABI: %v", err)
+	}
+	logs := make([]*gethtypes.Log, len(trace.Logs))
+	for i, traced := range trace.Logs {
+		logs[i] = traced.ToLog()
+	}
+	events := artifacts.DecodeLogs(*getterSetterABI, contract, logs)
+
+	for _, change := range trace.StorageChanges {
+		name := change.Name
+		if name == "" {
+			name = "unknown variable"
+		}
+		log.Printf("Trace of %s: storage of %s, slot %s (%s): %s -> %s", step, change.Address.Hex(), change.Slot.Hex(), name, change.Before.Hex(), change.After.Hex())
+	}
+	for _, event := range events {
+		log.Printf("Trace of %s: event %s %v", step, event.Name, event.Args)
+	}
+	traces = append(traces, types.TransactionTrace{Step: step, Trace: trace, Events: events})
+}
+
+// writeTraces writes the traced transactions to output/traces.json
+func writeTraces() {
+	if len(traces) == 0 {
+		log.Println("Client.Trace is set, but no transaction was traced")
+		return
+	}
+	utils.JsonWriter(traces, "output/traces.json")
+}
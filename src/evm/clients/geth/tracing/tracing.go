@@ -0,0 +1,175 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"main/src/evm/clients/geth/backend"
+	"sort"
+	"strings"
+)
+
+// ErrUnavailable is returned when the node doesn't expose debug_traceTransaction,
+// e.g. the public RPC providers and the simulated chain
+var ErrUnavailable = errors.New("the node does not expose debug_traceTransaction")
+
+// methodNotFoundCode is the JSON-RPC error code of an unknown or disabled method
+const methodNotFoundCode = -32601
+
+// CallFrame is a call of the callTracer, with its sub-calls and the logs it emitted
+type CallFrame struct {
+	Type         string          `json:"type"` // CALL, DELEGATECALL, CREATE2, ...
+	From         common.Address  `json:"from"`
+	To           *common.Address `json:"to,omitempty"`
+	Value        *hexutil.Big    `json:"value,omitempty"`
+	Gas          hexutil.Uint64  `json:"gas"`
+	GasUsed      hexutil.Uint64  `json:"gasUsed"`
+	Input        hexutil.Bytes   `json:"input"`
+	Output       hexutil.Bytes   `json:"output,omitempty"`
+	Error        string          `json:"error,omitempty"`
+	RevertReason string          `json:"revertReason,omitempty"`
+	Calls        []CallFrame     `json:"calls,omitempty"`
+	Logs         []CallLog       `json:"logs,omitempty"`
+}
+
+// CallLog is a log emitted by a call frame. Its position is the number of sub-calls the frame made before emitting it.
+type CallLog struct {
+	Address  common.Address `json:"address"`
+	Topics   []common.Hash  `json:"topics"`
+	Data     hexutil.Bytes  `json:"data"`
+	Position hexutil.Uint   `json:"position"`
+}
+
+// ToLog converts the log for the decoding with a contract ABI
+func (l CallLog) ToLog() *types.Log {
+	return &types.Log{Address: l.Address, Topics: l.Topics, Data: l.Data}
+}
+
+// StorageChange is a storage slot written by the transaction
+type StorageChange struct {
+	Address common.Address `json:"address"`
+	Slot    common.Hash    `json:"slot"`
+	Name    string         `json:"name,omitempty"` // the variable stored in the slot, when known
+	Before  common.Hash    `json:"before"`
+	After   common.Hash    `json:"after"`
+}
+
+// Trace is what a transaction did: its calls, the storage slots it changed and the logs it emitted
+type Trace struct {
+	TransactionHash string          `json:"transactionHash"`
+	StorageChanges  []StorageChange `json:"storageChanges"`
+	Logs            []CallLog       `json:"logs"` // in the order they were emitted
+	Calls           CallFrame       `json:"calls"`
+}
+
+// accountState is an account in the diff of the prestateTracer
+type accountState struct {
+	Storage map[common.Hash]common.Hash `json:"storage,omitempty"`
+}
+
+// prestateDiff is the result of the prestateTracer in diff mode: the changed fields of the accounts, before and after
+type prestateDiff struct {
+	Pre  map[common.Address]accountState `json:"pre"`
+	Post map[common.Address]accountState `json:"post"`
+}
+
+// TraceTransaction traces the mined transaction with debug_traceTransaction, once with the callTracer
+// and once with the prestateTracer in diff mode.
+//
+// Parameters:
+// - ctx: the context of the run (context.Context)
+// - client: the Ethereum client (backend.Client)
+// - hash: the hash of the mined transaction (common.Hash)
+// - slotName: names the variable stored in a slot of a contract, "" if unknown, optional (func(common.Address, common.Hash) string)
+// Returns:
+// - Trace
+// - error, wrapping ErrUnavailable if the node doesn't expose the tracers
+func TraceTransaction(ctx context.Context, client backend.Client, hash common.Hash, slotName func(common.Address, common.Hash) string) (Trace, error) {
+	caller, ok := backend.Unwrap(client).(backend.RawCaller)
+	if !ok {
+		return Trace{}, ErrUnavailable
+	}
+	trace := Trace{TransactionHash: hash.Hex()}
+	callTracer := map[string]interface{}{"tracer": "callTracer", "tracerConfig": map[string]interface{}{"withLog": true}}
+	if err := caller.CallContext(ctx, &trace.Calls, "debug_traceTransaction", hash, callTracer); err != nil {
+		return Trace{}, traceError("callTracer", err)
+	}
+	var diff prestateDiff
+	prestateTracer := map[string]interface{}{"tracer": "prestateTracer", "tracerConfig": map[string]interface{}{"diffMode": true}}
+	if err := caller.CallContext(ctx, &diff, "debug_traceTransaction", hash, prestateTracer); err != nil {
+		return Trace{}, traceError("prestateTracer", err)
+	}
+	trace.StorageChanges = storageChanges(diff, slotName)
+	trace.Logs = trace.Calls.OrderedLogs()
+	return trace, nil
+}
+
+// traceError wraps ErrUnavailable when the node rejects the method itself, rather than the tracing
+func traceError(tracer string, err error) error {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == methodNotFoundCode {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	message := strings.ToLower(err.Error())
+	for _, unavailable := range []string{"does not exist", "not available", "not supported", "method not found", "not allowed"} {
+		if strings.Contains(message, unavailable) {
+			return fmt.Errorf("%w: %v", ErrUnavailable, err)
+		}
+	}
+	return fmt.Errorf("debug_traceTransaction with the %s failed: %w", tracer, err)
+}
+
+// storageChanges lists the slots changed in the diff, by address and slot. A slot missing in the post state was cleared.
+func storageChanges(diff prestateDiff, slotName func(common.Address, common.Hash) string) []StorageChange {
+	changes := []StorageChange{}
+	for address, pre := range diff.Pre {
+		post := diff.Post[address]
+		for slot, before := range pre.Storage {
+			after := post.Storage[slot] // zero if cleared
+			if before != after {
+				changes = append(changes, StorageChange{Address: address, Slot: slot, Before: before, After: after})
+			}
+		}
+	}
+	for address, post := range diff.Post {
+		for slot, after := range post.Storage {
+			if _, ok := diff.Pre[address].Storage[slot]; !ok {
+				changes = append(changes, StorageChange{Address: address, Slot: slot, After: after})
+			}
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Address != changes[j].Address {
+			return bytes.Compare(changes[i].Address.Bytes(), changes[j].Address.Bytes()) < 0
+		}
+		return bytes.Compare(changes[i].Slot.Bytes(), changes[j].Slot.Bytes()) < 0
+	})
+	if slotName != nil {
+		for i := range changes {
+			changes[i].Name = slotName(changes[i].Address, changes[i].Slot)
+		}
+	}
+	return changes
+}
+
+// OrderedLogs returns the logs of the frame and its sub-calls in the order they were emitted.
+// The callTracer drops the logs of the reverted frames.
+func (f CallFrame) OrderedLogs() []CallLog {
+	logs := []CallLog{}
+	next := 0
+	for i := 0; i <= len(f.Calls); i++ {
+		for next < len(f.Logs) && int(f.Logs[next].Position) <= i {
+			logs = append(logs, f.Logs[next])
+			next++
+		}
+		if i < len(f.Calls) {
+			logs = append(logs, f.Calls[i].OrderedLogs()...)
+		}
+	}
+	return logs
+}
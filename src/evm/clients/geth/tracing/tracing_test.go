@@ -0,0 +1,129 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"main/src/contracts/getter_setter"
+	"main/src/evm/clients/geth/backend"
+	"testing"
+)
+
+// The traces of a setUint256(7) sent to a proxy: the proxy DELEGATECALLs the implementation, which emits SetUint256
+const (
+	callTrace = `{
+		"type": "CALL", "from": "0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266", "to": "0x00000000000000000000000000000000000000aa",
+		"value": "0x0", "gas": "0x2dc6c0", "gasUsed": "0xab12", "input": "0xd2282dc50000000000000000000000000000000000000000000000000000000000000007",
+		"logs": [{"address": "0x00000000000000000000000000000000000000aa", "topics": ["0x0000000000000000000000000000000000000000000000000000000000000001"], "data": "0x", "position": "0x1"}],
+		"calls": [{
+			"type": "DELEGATECALL", "from": "0x00000000000000000000000000000000000000aa", "to": "0x00000000000000000000000000000000000000bb",
+			"gas": "0x2d0000", "gasUsed": "0x9000", "input": "0xd2282dc50000000000000000000000000000000000000000000000000000000000000007",
+			"logs": [{"address": "0x00000000000000000000000000000000000000aa", "topics": ["0xb5f2c4d1d2ba4ea8af69e7bd1b0d36b1c6b4e0bc5e1b0f2e3e7ce0a5fa25ed3c"], "data": "0x", "position": "0x0"}]
+		}]
+	}`
+	prestateTrace = `{
+		"pre": {
+			"0x00000000000000000000000000000000000000aa": {"balance": "0x0", "storage": {
+				"0x0000000000000000000000000000000000000000000000000000000000000001": "0x0000000000000000000000000000000000000000000000000000000000000005",
+				"0x0000000000000000000000000000000000000000000000000000000000000002": "0x0000000000000000000000000000000000000000000000000000000000000009"
+			}},
+			"0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266": {"balance": "0x3635c9adc5dea00000", "nonce": 1}
+		},
+		"post": {
+			"0x00000000000000000000000000000000000000aa": {"storage": {
+				"0x0000000000000000000000000000000000000000000000000000000000000001": "0x0000000000000000000000000000000000000000000000000000000000000007",
+				"0xc2575a0e9e593c00f959f8c92f12db2869c3395a3b0502d05e2516446f71f85b": "0x4869000000000000000000000000000000000000000000000000000000000000"
+			}},
+			"0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266": {"balance": "0x3635c9adc5de9f0000", "nonce": 2}
+		}
+	}`
+)
+
+// tracingClient answers debug_traceTransaction with canned traces, or with the given error
+type tracingClient struct {
+	backend.Client
+	err error
+}
+
+func (c tracingClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if c.err != nil {
+		return c.err
+	}
+	config := args[1].(map[string]interface{})
+	trace := callTrace
+	if config["tracer"] == "prestateTracer" {
+		trace = prestateTrace
+	}
+	return json.Unmarshal([]byte(trace), result)
+}
+
+// rpcError is a JSON-RPC error, like the ones of the RPC client
+type rpcError struct {
+	code    int
+	message string
+}
+
+func (e rpcError) Error() string  { return e.message }
+func (e rpcError) ErrorCode() int { return e.code }
+
+func TestTraceTransaction(t *testing.T) {
+	proxy := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	slotName := func(address common.Address, slot common.Hash) string {
+		if address != proxy {
+			return ""
+		}
+		return getter_setter.SlotName(slot)
+	}
+	trace, err := TraceTransaction(context.Background(), tracingClient{}, common.HexToHash("0x01"), slotName)
+	if err != nil {
+		t.Fatalf("TraceTransaction() error = %v", err)
+	}
+
+	want := []StorageChange{
+		{Address: proxy, Slot: getter_setter.Uint256Slot, Name: "getUint256", Before: common.HexToHash("0x05"), After: common.HexToHash("0x07")},
+		{Address: proxy, Slot: getter_setter.RequestIdSlot, Name: "requestId", Before: common.HexToHash("0x09")},
+		{Address: proxy, Slot: getter_setter.BytesDataSlot, Name: "getBytes data[0]", After: common.HexToHash("0x4869000000000000000000000000000000000000000000000000000000000000")},
+	}
+	if len(trace.StorageChanges) != len(want) {
+		t.Fatalf("StorageChanges = %+v, want %+v", trace.StorageChanges, want)
+	}
+	for i := range want {
+		if trace.StorageChanges[i] != want[i] {
+			t.Errorf("StorageChanges[%d] = %+v, want %+v", i, trace.StorageChanges[i], want[i])
+		}
+	}
+
+	// The log of the DELEGATECALL was emitted before the one the proxy emitted after it
+	if len(trace.Logs) != 2 || trace.Logs[0].Position != 0 || trace.Logs[1].Topics[0] != common.HexToHash("0x01") {
+		t.Errorf("Logs = %+v, want the DELEGATECALL log first", trace.Logs)
+	}
+	if trace.Calls.Type != "CALL" || len(trace.Calls.Calls) != 1 || trace.Calls.Calls[0].Type != "DELEGATECALL" {
+		t.Errorf("Calls = %+v, want a CALL with a DELEGATECALL", trace.Calls)
+	}
+}
+
+func TestTraceTransactionUnavailable(t *testing.T) {
+	tests := []struct {
+		name            string
+		client          backend.Client
+		wantUnavailable bool
+	}{
+		{name: "method not found", client: tracingClient{err: rpcError{code: -32601, message: "the method debug_traceTransaction does not exist/is not available"}}, wantUnavailable: true},
+		{name: "provider message", client: tracingClient{err: errors.New("debug_traceTransaction is not supported on this plan")}, wantUnavailable: true},
+		{name: "tracing failure", client: tracingClient{err: rpcError{code: -32000, message: "required historical state unavailable"}}},
+		{name: "simulated chain", client: backend.NewSimulatedClient(types.GenesisAlloc{}), wantUnavailable: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := TraceTransaction(context.Background(), tt.client, common.HexToHash("0x01"), nil)
+			if err == nil {
+				t.Fatal("TraceTransaction() error = nil")
+			}
+			if got := errors.Is(err, ErrUnavailable); got != tt.wantUnavailable {
+				t.Errorf("TraceTransaction() error = %v, unavailable = %v, want %v", err, got, tt.wantUnavailable)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package transactions
+
+import (
+	"context"
+	"errors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"log"
+	"main/src/utils"
+	"sort"
+	"sync"
+)
+
+// InterruptedExitCode is the exit code of a run interrupted by SIGINT/SIGTERM (128 + SIGINT)
+const InterruptedExitCode = 130
+
+// PendingTransaction is a transaction sent by the run but not mined yet
+type PendingTransaction struct {
+	Hash  common.Hash
+	From  common.Address
+	Nonce uint64
+}
+
+var pending = struct {
+	sync.Mutex
+	transactions map[common.Hash]PendingTransaction
+}{transactions: map[common.Hash]PendingTransaction{}}
+
+func trackPending(transaction *types.Transaction) {
+	from, err := types.Sender(types.LatestSignerForChainID(transaction.ChainId()), transaction)
+	if err != nil {
+		log.Printf("Could not recover the sender of transaction %s: %v", transaction.Hash().Hex(), err)
+	}
+	pending.Lock()
+	defer pending.Unlock()
+	pending.transactions[transaction.Hash()] = PendingTransaction{Hash: transaction.Hash(), From: from, Nonce: transaction.Nonce()}
+}
+
+func untrackPending(transaction *types.Transaction) {
+	pending.Lock()
+	defer pending.Unlock()
+	delete(pending.transactions, transaction.Hash())
+}
+
+// Pending returns the transactions sent by the run and not mined yet, ordered by nonce
+func Pending() []PendingTransaction {
+	pending.Lock()
+	defer pending.Unlock()
+	transactions := make([]PendingTransaction, 0, len(pending.transactions))
+	for _, transaction := range pending.transactions {
+		transactions = append(transactions, transaction)
+	}
+	sort.Slice(transactions, func(i, j int) bool { return transactions[i].Nonce < transactions[j].Nonce })
+	return transactions
+}
+
+// ExitIfInterrupted stops the run if its context was cancelled (SIGINT/SIGTERM), listing the transactions
+// which were sent but not mined yet, so they can be checked or replaced before running again.
+// It does nothing if the run was not interrupted.
+func ExitIfInterrupted(ctx context.Context) {
+	if !errors.Is(ctx.Err(), context.Canceled) {
+		return
+	}
+	log.Println("Run interrupted")
+	transactions := Pending()
+	if len(transactions) == 0 {
+		log.Println("No transaction is pending")
+	}
+	for _, transaction := range transactions {
+		log.Printf("Pending transaction: %s, from: %s, nonce: %d", transaction.Hash.Hex(), transaction.From.Hex(), transaction.Nonce)
+	}
+	utils.Exit(InterruptedExitCode)
+}
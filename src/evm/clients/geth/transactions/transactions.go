@@ -3,37 +3,52 @@ package transactions
 import (
 	"context"
 	"errors"
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"log"
+	"main/src/evm/clients/geth/backend"
+	"main/src/evm/clients/geth/reverts"
+	"main/src/utils"
 	"math/big"
 	"time"
 )
 
-func GetChainId(client *ethclient.Client) *big.Int {
-	chainID, err := client.ChainID(context.Background())
+func GetChainId(ctx context.Context, client backend.Client) *big.Int {
+	chainID, err := client.ChainID(ctx)
 	if err != nil {
-		log.Fatalf("Failed to get chain ID: %v", err)
+		ExitIfInterrupted(ctx)
+		utils.Fatalf("Failed to get chain ID: %v", err)
 	}
 	log.Println("Chain ID:", chainID)
 	return chainID
 }
 
-func GetNonce(client *ethclient.Client, deployerAddress common.Address) uint64 {
-	nonce, err := client.PendingNonceAt(context.Background(), deployerAddress)
+// ValidateChainId aborts the run if the node is connected to another chain than the expected one,
+// e.g. when the RPC URL of a network profile points to the wrong network.
+func ValidateChainId(ctx context.Context, client backend.Client, expectedChainId uint64) {
+	chainID := GetChainId(ctx, client)
+	if !chainID.IsUint64() || chainID.Uint64() != expectedChainId {
+		utils.Fatalf("Chain ID mismatch: the node reports %s, but %d is expected. Check the RPC URL and the network profile.", chainID, expectedChainId)
+	}
+}
+
+func GetNonce(ctx context.Context, client backend.Client, deployerAddress common.Address) uint64 {
+	nonce, err := client.PendingNonceAt(ctx, deployerAddress)
 	if err != nil {
-		log.Fatal(err)
+		ExitIfInterrupted(ctx)
+		utils.Fatal(err)
 	}
 	log.Println("Nonce:", nonce)
 	return nonce
 }
 
-func GetTransactionGasPrice(client *ethclient.Client) *big.Int {
-	gasPrice, err := client.SuggestGasPrice(context.Background())
+func GetTransactionGasPrice(ctx context.Context, client backend.Client) *big.Int {
+	gasPrice, err := client.SuggestGasPrice(ctx)
 	if err != nil {
-		log.Fatal(err)
+		ExitIfInterrupted(ctx)
+		utils.Fatal(err)
 	}
 	log.Println("Gas price:", gasPrice)
 	return gasPrice
@@ -42,54 +57,109 @@ func GetTransactionGasPrice(client *ethclient.Client) *big.Int {
 // WaitMined waits for a transaction to be mined.
 // If the transaction is not mined within the timeout, it will throw an error,
 // however, it does not revert or cancel the transaction.
+// The transaction is listed as pending if the run is interrupted meanwhile.
 //
 // Parameters:
+// - ctx: the context of the run, cancelled on interruption.
 // - client: Ethereum client instance.
 // - transaction: Transaction to be mined.
 // - timeout: Timeout duration, in seconds. Defines how long the function waits for the transaction to be mined.
 // Return:
 // - common.Address: Address of the deployed transaction.
-func WaitMined(client *ethclient.Client, transaction *types.Transaction, timeout int) *types.Receipt {
+func WaitMined(ctx context.Context, client backend.Client, transaction *types.Transaction, timeout int) *types.Receipt {
 	timeToWait := SetTimeToWait(timeout)
-	backgroundContext, cancel := context.WithTimeout(context.Background(), timeToWait)
+	backgroundContext, cancel := context.WithTimeout(ctx, timeToWait)
 	defer cancel()
 
+	trackPending(transaction)
 	receipt, err := bind.WaitMined(backgroundContext, client, transaction)
 	if err != nil {
+		ExitIfInterrupted(ctx)
 		if errors.Is(backgroundContext.Err(), context.DeadlineExceeded) {
-			log.Fatalf("Cancelled waiting for transaction status by timeout: %v. Verify your transaction manually or increase the timeout.", timeToWait)
+			utils.Fatalf("Cancelled waiting for transaction status by timeout: %v. Verify your transaction manually or increase the timeout.", timeToWait)
 		} else {
-			log.Fatalf("Failed to wait and get receipt from transaction: %v", err)
+			utils.Fatalf("Failed to wait and get receipt from transaction: %v", err)
 		}
 	}
 
+	untrackPending(transaction)
 	log.Println("Transaction mined in block", receipt.BlockNumber)
 	return receipt
 }
 
+// WaitReceipt polls the receipt of a transaction until it is mined, the time to wait elapses or the run
+// is interrupted. Unlike WaitMined, it returns the error instead of stopping the run and logs nothing,
+// so it suits many transactions waited for concurrently. The transaction is listed as pending until mined.
+//
+// Parameters:
+// - ctx: the context of the run, cancelled on interruption.
+// - client: Ethereum client instance.
+// - transaction: Transaction to be mined.
+// - timeToWait: how long to wait for the transaction to be mined.
+// - interval: delay between two polls, the precision of the inclusion time.
+// Return:
+// - *types.Receipt: the receipt of the mined transaction.
+// - error if the transaction is not mined in time or the receipt can't be fetched.
+func WaitReceipt(ctx context.Context, client backend.Client, transaction *types.Transaction, timeToWait time.Duration, interval time.Duration) (*types.Receipt, error) {
+	waitContext, cancel := context.WithTimeout(ctx, timeToWait)
+	defer cancel()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	trackPending(transaction)
+	for {
+		receipt, err := client.TransactionReceipt(waitContext, transaction.Hash())
+		if err == nil {
+			untrackPending(transaction)
+			return receipt, nil
+		}
+		if !errors.Is(err, ethereum.NotFound) && waitContext.Err() == nil {
+			return nil, err
+		}
+		select {
+		case <-waitContext.Done():
+			return nil, waitContext.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
 // WaitDeployed waits for a transaction to be mined.
 // If the transaction is not mined within the timeout, it will throw an error,
 // however, it does not revert or cancel the transaction.
+// The transaction is listed as pending if the run is interrupted meanwhile.
 //
 // Parameters:
+// - ctx: the context of the run, cancelled on interruption.
 // - client: Ethereum client instance.
 // - transaction: Transaction to be mined.
 // - timeout: Timeout duration, in seconds. Defines how long the function waits for the transaction to be mined.
 // Return:
 // - common.Address: Address of the deployed transaction.
-func WaitDeployed(client *ethclient.Client, transaction *types.Transaction, timeout int) common.Address {
+func WaitDeployed(ctx context.Context, client backend.Client, transaction *types.Transaction, timeout int) common.Address {
 	timeToWait := SetTimeToWait(timeout)
-	backgroundContext, cancel := context.WithTimeout(context.Background(), timeToWait)
+	backgroundContext, cancel := context.WithTimeout(ctx, timeToWait)
 	defer cancel()
 
+	trackPending(transaction)
 	deployed, err := bind.WaitDeployed(backgroundContext, client, transaction)
 	if err != nil {
+		ExitIfInterrupted(ctx)
+		// A deployment leaves no code when its constructor reverts
+		if errors.Is(err, bind.ErrNoCodeAfterDeploy) {
+			if receipt, receiptErr := client.TransactionReceipt(ctx, transaction.Hash()); receiptErr == nil {
+				if revertErr := reverts.CheckReceipt(ctx, client, "deployment", transaction, receipt); revertErr != nil {
+					utils.Fatal(revertErr)
+				}
+			}
+		}
 		if errors.Is(backgroundContext.Err(), context.DeadlineExceeded) {
-			log.Fatalf("Cancelled waiting for transaction status by timeout: %s. Verify your transaction manually or increase the timeout.", timeToWait)
+			utils.Fatalf("Cancelled waiting for transaction status by timeout: %s. Verify your transaction manually or increase the timeout.", timeToWait)
 		} else {
-			log.Fatal("Failed to wait and get receipt from transaction:", err)
+			utils.Fatal("Failed to wait and get receipt from transaction:", err)
 		}
 	}
+	untrackPending(transaction)
 	return deployed
 }
 
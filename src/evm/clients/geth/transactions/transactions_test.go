@@ -0,0 +1,66 @@
+package transactions
+
+import (
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestSetTimeToWait(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout int
+		want    time.Duration
+	}{
+		{name: "not provided", timeout: 0, want: 300 * time.Second},
+		{name: "negative", timeout: -10, want: 300 * time.Second},
+		{name: "less than default", timeout: 60, want: 300 * time.Second},
+		{name: "equal to default", timeout: 300, want: 300 * time.Second},
+		{name: "greater than default", timeout: 600, want: 600 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SetTimeToWait(tt.timeout); got != tt.want {
+				t.Errorf("SetTimeToWait(%d) = %v, want %v", tt.timeout, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPending(t *testing.T) {
+	key, err := crypto.HexToECDSA("ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := types.LatestSignerForChainID(big.NewInt(1337))
+	var sent []*types.Transaction
+	for _, nonce := range []uint64{8, 7} {
+		transaction, err := types.SignNewTx(key, signer, &types.LegacyTx{Nonce: nonce, Gas: 21000, GasPrice: big.NewInt(1)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		trackPending(transaction)
+		sent = append(sent, transaction)
+	}
+	t.Cleanup(func() {
+		for _, transaction := range sent {
+			untrackPending(transaction)
+		}
+	})
+
+	got := Pending()
+	if len(got) != 2 || got[0].Nonce != 7 || got[1].Nonce != 8 {
+		t.Fatalf("Pending() = %+v, want nonces 7 and 8", got)
+	}
+	if want := crypto.PubkeyToAddress(key.PublicKey); got[0].From != want || got[0].Hash != sent[1].Hash() {
+		t.Errorf("Pending()[0] = %+v, want hash %s from %s", got[0], sent[1].Hash().Hex(), want.Hex())
+	}
+
+	untrackPending(sent[0])
+	if got := Pending(); len(got) != 1 || got[0].Nonce != 7 {
+		t.Errorf("Pending() after mining nonce 8 = %+v, want nonce 7 only", got)
+	}
+}
@@ -0,0 +1,41 @@
+package types
+
+import (
+	"main/src/evm/clients/geth/artifacts"
+	"math/big"
+)
+
+// ArtifactDeploymentInformation represents the scheme of the output for an artifact deployment
+type ArtifactDeploymentInformation struct {
+	ContractAddress string   `json:"contractAddress"`
+	DeployerAddress string   `json:"deployerAddress"`
+	TransactionHash string   `json:"transactionHash"`
+	ConstructorArgs []string `json:"constructorArgs,omitempty"`
+}
+
+// MethodCallInformation represents the scheme of the output for a method call (transaction) or read (eth_call)
+type MethodCallInformation struct {
+	ContractAddress string                 `json:"contractAddress"`
+	SenderAddress   string                 `json:"senderAddress"`
+	Method          string                 `json:"method"`
+	Args            []string               `json:"args,omitempty"`
+	TransactionHash string                 `json:"transactionHash,omitempty"`
+	BlockNumber     *big.Int               `json:"blockNumber,omitempty"`
+	GasUsed         uint64                 `json:"gasUsed,omitempty"`
+	ReturnValues    map[string]interface{} `json:"returnValues,omitempty"`
+	Events          []artifacts.Event      `json:"events,omitempty"`
+}
+
+// BytecodeVerificationInformation represents the scheme of the output for the bytecode verification
+type BytecodeVerificationInformation struct {
+	ContractAddress  string `json:"contractAddress"`
+	LocalArtifact    string `json:"localArtifact"`
+	Match            bool   `json:"match"`      // runtime code matches, excluding the metadata
+	ExactMatch       bool   `json:"exactMatch"` // runtime code matches, including the metadata
+	OnchainCodeHash  string `json:"onchainCodeHash"`
+	LocalCodeHash    string `json:"localCodeHash"`
+	OnchainMetadata  string `json:"onchainMetadata,omitempty"`
+	LocalMetadata    string `json:"localMetadata,omitempty"`
+	OnchainCodeBytes int    `json:"onchainCodeBytes"`
+	LocalCodeBytes   int    `json:"localCodeBytes"`
+}
@@ -0,0 +1,28 @@
+package types
+
+import "math/big"
+
+// DryRunInformation represents the scheme of the output for the -dry-run flag
+type DryRunInformation struct {
+	Mode               string              `json:"mode"`
+	Transactions       []DryRunTransaction `json:"transactions"`
+	TotalEstimatedCost *big.Int            `json:"totalEstimatedCost"` // wei
+}
+
+// DryRunTransaction is a transaction of the run, signed but not broadcast
+type DryRunTransaction struct {
+	Step            string   `json:"step"`
+	From            string   `json:"from"`
+	To              string   `json:"to,omitempty"`              // empty for a deployment
+	ContractAddress string   `json:"contractAddress,omitempty"` // address of the deployed contract
+	Nonce           uint64   `json:"nonce"`
+	Value           *big.Int `json:"value"`
+	GasLimit        uint64   `json:"gasLimit"`
+	GasPrice        *big.Int `json:"gasPrice"`
+	EstimatedGas    uint64   `json:"estimatedGas"`
+	EstimatedCost   *big.Int `json:"estimatedCost"` // estimatedGas * gasPrice + value, in wei
+	MaxCost         *big.Int `json:"maxCost"`       // gasLimit * gasPrice + value, in wei
+	Simulation      string   `json:"simulation"`    // "success", or why the eth_call simulation failed
+	TransactionHash string   `json:"transactionHash"`
+	RawTransaction  string   `json:"rawTransaction"` // signed and RLP encoded, as eth_sendRawTransaction takes it
+}
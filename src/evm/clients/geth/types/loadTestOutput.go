@@ -0,0 +1,31 @@
+package types
+
+// LoadTestReport represents the scheme of the output for a load test
+type LoadTestReport struct {
+	ContractAddress string             `json:"contractAddress"`
+	Accounts        []string           `json:"accounts"`
+	Transactions    int                `json:"transactions"` // configured number of transactions
+	Sent            int                `json:"sent"`
+	Mined           int                `json:"mined"` // mined and successful
+	Reverted        int                `json:"reverted"`
+	SendFailures    int                `json:"sendFailures"` // rejected by the node
+	WaitFailures    int                `json:"waitFailures"` // not mined in time or receipt unavailable
+	TargetRate      float64            `json:"targetRate,omitempty"`
+	DurationSeconds float64            `json:"durationSeconds"`
+	Throughput      float64            `json:"throughput"` // mined transactions per second
+	LatencyMs       LatencyPercentiles `json:"latencyMs"`  // inclusion latency, from sending to the receipt
+	GasUsed         uint64             `json:"gasUsed"`
+	AverageGasUsed  uint64             `json:"averageGasUsed"`
+	Errors          map[string]int     `json:"errors,omitempty"`        // number of failures by error message
+	RevertReasons   map[string]int     `json:"revertReasons,omitempty"` // number of reverted transactions by decoded revert reason
+}
+
+// LatencyPercentiles holds latency percentiles, in milliseconds
+type LatencyPercentiles struct {
+	Min float64 `json:"min"`
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P95 float64 `json:"p95"`
+	P99 float64 `json:"p99"`
+	Max float64 `json:"max"`
+}
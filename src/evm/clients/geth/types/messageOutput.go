@@ -0,0 +1,14 @@
+package types
+
+// MessageSignatureInformation represents the scheme of the output for the "sign-message" and "verify-message" modes
+type MessageSignatureInformation struct {
+	Standard       string `json:"standard"`              // EIP-191 or EIP-712
+	Text           string `json:"text,omitempty"`        // the EIP-191 personal message
+	TypedData      string `json:"typedData,omitempty"`   // the file of the EIP-712 typed data
+	PrimaryType    string `json:"primaryType,omitempty"` // the EIP-712 type of the message
+	Hash           string `json:"hash"`                  // what is signed
+	Signature      string `json:"signature"`
+	Signer         string `json:"signer"`                   // the address recovered from the signature
+	ExpectedSigner string `json:"expectedSigner,omitempty"` // verify-message only, Message.Signer
+	Match          bool   `json:"match"`                    // the signer is the expected one, true when none is expected
+}
@@ -0,0 +1,54 @@
+package types
+
+import "math/big"
+
+// UnsignedTransactions is the file written by the "prepare" mode and signed by the "sign" mode
+type UnsignedTransactions struct {
+	ChainId      *big.Int              `json:"chainId"`
+	From         string                `json:"from"` // the account expected to sign the transactions
+	Transactions []UnsignedTransaction `json:"transactions"`
+}
+
+// UnsignedTransaction is a legacy (EIP-155) transaction, built with the nonce and the gas price of the chain
+type UnsignedTransaction struct {
+	Step         string   `json:"step"`
+	To           string   `json:"to"`
+	Nonce        uint64   `json:"nonce"`
+	Value        *big.Int `json:"value"`
+	GasLimit     uint64   `json:"gasLimit"`
+	GasPrice     *big.Int `json:"gasPrice"`
+	Data         string   `json:"data"`
+	EstimatedGas uint64   `json:"estimatedGas"`
+	Simulation   string   `json:"simulation"` // "success", or why the eth_call simulation failed when it was prepared
+}
+
+// SignedTransactions is the file written by the "sign" mode and sent by the "broadcast" mode
+type SignedTransactions struct {
+	ChainId      *big.Int            `json:"chainId"`
+	From         string              `json:"from"`
+	Transactions []SignedTransaction `json:"transactions"`
+}
+
+// SignedTransaction is a transaction signed offline
+type SignedTransaction struct {
+	Step            string `json:"step"`
+	Nonce           uint64 `json:"nonce"`
+	TransactionHash string `json:"transactionHash"`
+	RawTransaction  string `json:"rawTransaction"` // signed and RLP encoded, as eth_sendRawTransaction takes it
+}
+
+// BroadcastInformation represents the scheme of the output for the "broadcast" mode
+type BroadcastInformation struct {
+	ChainId      *big.Int               `json:"chainId"`
+	From         string                 `json:"from"`
+	Transactions []BroadcastTransaction `json:"transactions"`
+}
+
+// BroadcastTransaction is a signed transaction, once mined
+type BroadcastTransaction struct {
+	Step            string `json:"step"`
+	TransactionHash string `json:"transactionHash"`
+	BlockNumber     uint64 `json:"blockNumber"`
+	GasUsed         uint64 `json:"gasUsed"`
+	AlreadyMined    bool   `json:"alreadyMined"` // mined before this run, e.g. by an interrupted broadcast
+}
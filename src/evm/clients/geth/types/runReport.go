@@ -0,0 +1,15 @@
+package types
+
+import (
+	"main/src/evm/clients/geth/backend"
+	"main/src/evm/clients/geth/reverts"
+)
+
+// RunReport represents the scheme of the output describing how the run went
+type RunReport struct {
+	Mode         string                  `json:"mode"`
+	RpcEndpoints map[string]int          `json:"rpcEndpoints,omitempty"` // number of requests served by each endpoint
+	RpcRequests  []backend.RequestRecord `json:"rpcRequests,omitempty"`  // the last requests, the oldest are dropped past 1000
+	RpcDropped   int                     `json:"rpcRequestsDropped,omitempty"`
+	Reverts      []reverts.Revert        `json:"reverts,omitempty"` // the failed calls and transactions, with their decoded revert reason
+}
@@ -0,0 +1,37 @@
+package types
+
+// SafeTransactionBuilderFile is the batch file the Transaction Builder app of the Safe imports
+type SafeTransactionBuilderFile struct {
+	Version      string                     `json:"version"`
+	ChainId      string                     `json:"chainId"`
+	CreatedAt    int64                      `json:"createdAt"` // milliseconds since the epoch
+	Meta         SafeTransactionBuilderMeta `json:"meta"`
+	Transactions []SafeTransaction          `json:"transactions"`
+}
+
+// SafeTransactionBuilderMeta describes the batch in the Transaction Builder
+type SafeTransactionBuilderMeta struct {
+	Name                    string `json:"name"`
+	Description             string `json:"description"`
+	TxBuilderVersion        string `json:"txBuilderVersion"`
+	CreatedFromSafeAddress  string `json:"createdFromSafeAddress"`
+	CreatedFromOwnerAddress string `json:"createdFromOwnerAddress"`
+}
+
+// SafeTransaction is a transaction executed by the Safe
+type SafeTransaction struct {
+	To        string `json:"to"`
+	Value     string `json:"value"` // wei, as a decimal string
+	Data      string `json:"data"`
+	Operation uint8  `json:"operation"` // 0 for a CALL, 1 for a DELEGATECALL
+}
+
+// SafeProposalInformation represents the scheme of the output for the "safe-proposal" mode
+type SafeProposalInformation struct {
+	ChainId     string          `json:"chainId"`
+	Setters     []string        `json:"setters"`
+	Transaction SafeTransaction `json:"transaction"` // executed by the Safe: the setter, or a MultiSend batch of the setters
+	SafeAddress string          `json:"safeAddress,omitempty"`
+	Nonce       *uint64         `json:"nonce,omitempty"`
+	SafeTxHash  string          `json:"safeTxHash,omitempty"` // the EIP-712 hash the owners sign, when Safe.Address is set
+}
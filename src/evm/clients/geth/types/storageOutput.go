@@ -0,0 +1,23 @@
+package types
+
+// StorageInspectionInformation represents the scheme of the output for the storage inspection of a GetterSetter
+type StorageInspectionInformation struct {
+	ContractAddress string        `json:"contractAddress"`
+	BlockNumber     uint64        `json:"blockNumber"` // the slots and the getters are read at this block
+	Slots           []StorageSlot `json:"slots"`
+	Match           bool          `json:"match"` // every value decoded from the storage matches its getter
+}
+
+// StorageSlot is a state variable read from its storage slot and compared with its getter
+type StorageSlot struct {
+	Slot      string   `json:"slot"`
+	Variable  string   `json:"variable"` // the getter of the variable
+	Raw       string   `json:"raw"`
+	Encoding  string   `json:"encoding,omitempty"`  // getBytes only: short (stored in the slot) or long (stored in the data slots)
+	Length    *uint64  `json:"length,omitempty"`    // getBytes only
+	DataSlots []string `json:"dataSlots,omitempty"` // getBytes only, the raw data slots of a long value
+	Decoded   string   `json:"decoded"`
+	Getter    string   `json:"getter"` // the value returned by the getter, encoded like the decoded one
+	Match     bool     `json:"match"`
+	Error     string   `json:"error,omitempty"` // why the slot could not be decoded or the getter could not be called
+}
@@ -0,0 +1,13 @@
+package types
+
+import (
+	"main/src/evm/clients/geth/artifacts"
+	"main/src/evm/clients/geth/tracing"
+)
+
+// TransactionTrace represents the scheme of the output for a traced transaction
+type TransactionTrace struct {
+	Step string `json:"step"` // the setter, e.g. setUint256
+	tracing.Trace
+	Events []artifacts.Event `json:"events"` // the logs decoded with the GetterSetter ABI
+}
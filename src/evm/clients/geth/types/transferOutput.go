@@ -0,0 +1,21 @@
+package types
+
+import "math/big"
+
+// TransfersInformation represents the scheme of the output for the "fund" and "sweep" modes
+type TransfersInformation struct {
+	Mode      string                `json:"mode"`
+	Transfers []TransferInformation `json:"transfers"`
+	Total     *big.Int              `json:"total"` // wei transferred
+}
+
+// TransferInformation is an ETH transfer between the main account and a derived account
+type TransferInformation struct {
+	Path            string   `json:"path"` // derivation path of the derived account
+	From            string   `json:"from"`
+	To              string   `json:"to"`
+	Value           *big.Int `json:"value"` // wei, 0 when the transfer is skipped
+	TransactionHash string   `json:"transactionHash,omitempty"`
+	Skipped         string   `json:"skipped,omitempty"` // why no transfer was sent
+	BalanceAfter    *big.Int `json:"balanceAfter"`      // balance of the derived account after the transfer, in wei
+}
@@ -0,0 +1,14 @@
+package types
+
+// UpgradeInformation represents the scheme of the output for a proxy upgrade
+type UpgradeInformation struct {
+	ProxyAddress           string                          `json:"proxyAddress"`
+	AdminAddress           string                          `json:"adminAddress"` // the ProxyAdmin of the proxy
+	AdminOwner             string                          `json:"adminOwner"`   // the owner of the ProxyAdmin, the sender of the upgrade
+	PreviousImplementation string                          `json:"previousImplementation"`
+	NewImplementation      string                          `json:"newImplementation"`
+	TransactionHash        string                          `json:"transactionHash"`
+	ValuesBefore           ContractGetterSetterInformation `json:"valuesBefore"`
+	ValuesAfter            ContractGetterSetterInformation `json:"valuesAfter"`
+	StoragePreserved       bool                            `json:"storagePreserved"` // getUint256, getBytes32 and getBytes return the same values after the upgrade
+}
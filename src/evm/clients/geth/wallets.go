@@ -0,0 +1,194 @@
+package geth
+
+import (
+	"context"
+	"encoding/hex"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"log"
+	"main/src/config"
+	"main/src/evm/clients/geth/account"
+	"main/src/evm/clients/geth/backend"
+	"main/src/evm/clients/geth/reverts"
+	"main/src/evm/clients/geth/transactions"
+	"main/src/evm/clients/geth/types"
+	"main/src/utils"
+	"math/big"
+)
+
+// transferBatchSize is the number of transfers sent before waiting for their receipts
+const transferBatchSize = 20
+
+// sender is an account sending transactions
+type sender struct {
+	address    common.Address
+	privateKey string
+	path       string // derivation path, empty for Account.Key
+}
+
+// deriveAccounts derives the test accounts of Account.Mnemonic, none if it is not set
+func deriveAccounts(accountConfig config.Account) []sender {
+	if accountConfig.Mnemonic == "" {
+		return nil
+	}
+	privateKeys, err := account.DerivePrivateKeys(accountConfig.Mnemonic, accountConfig.Passphrase, accountConfig.Derived)
+	if err != nil {
+		utils.Fatalf("Failed to derive the accounts of Account.Mnemonic: %v", err)
+	}
+	accounts := make([]sender, len(privateKeys))
+	for i, privateKey := range privateKeys {
+		accounts[i] = sender{
+			address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+			privateKey: hex.EncodeToString(crypto.FromECDSA(privateKey)),
+			path:       account.DerivationPath(i),
+		}
+	}
+	log.Printf("Derived %d account(s) from Account.Mnemonic, %s: %s ... %s: %s", len(accounts),
+		accounts[0].path, accounts[0].address.Hex(), accounts[len(accounts)-1].path, accounts[len(accounts)-1].address.Hex())
+	return accounts
+}
+
+// setterSenders returns the accounts the setters are sent from: the derived ones with Account.DerivedSenders, Account.Key otherwise
+func setterSenders() []sender {
+	if tomlConfig.Account.DerivedSenders {
+		return derivedAccounts
+	}
+	return []sender{{address: deployerAddress, privateKey: privateKey}}
+}
+
+// transfer is an ETH transfer to send
+type transfer struct {
+	auth *bind.TransactOpts
+	to   common.Address
+	info *types.TransferInformation
+}
+
+// FundDerivedAccounts tops the balance of each derived account up to the target, from Account.Key.
+// The accounts already holding the target, and Account.Key itself if it is derived, are skipped.
+//
+// Parameters:
+// - ctx: the context of the run (context.Context)
+// - deployerAddress: the address of the funding account (common.Address)
+// - privateKey: the private key of the funding account (string)
+// - accounts: the derived accounts ([]sender)
+// - target: the balance each account is topped up to, in wei (*big.Int)
+// - ethClient: the Ethereum client for interaction (backend.Client)
+// - timeout: the timeout for each batch of transfers to be mined, in seconds (int)
+// Return type:
+// - types.TransfersInformation
+func FundDerivedAccounts(ctx context.Context, deployerAddress common.Address, privateKey string, accounts []sender, target *big.Int, ethClient backend.Client, timeout int) types.TransfersInformation {
+	output := types.TransfersInformation{Mode: utils.FUND_MODE, Transfers: make([]types.TransferInformation, len(accounts)), Total: big.NewInt(0)}
+	auth := GetSigner(ctx, ethClient, deployerAddress, privateKey)
+	nonce := auth.Nonce.Uint64()
+	var transfers []transfer
+	for i, derived := range accounts {
+		info := &output.Transfers[i]
+		*info = types.TransferInformation{Path: derived.path, From: deployerAddress.Hex(), To: derived.address.Hex(), Value: big.NewInt(0)}
+		if derived.address == deployerAddress {
+			info.Skipped = "funding account"
+			continue
+		}
+		balance := balanceOf(ctx, ethClient, derived.address)
+		if balance.Cmp(target) >= 0 {
+			info.Skipped = "funded"
+			continue
+		}
+		funding := *auth
+		funding.Nonce = new(big.Int).SetUint64(nonce)
+		funding.Value = new(big.Int).Sub(target, balance)
+		nonce++
+		info.Value = funding.Value
+		transfers = append(transfers, transfer{auth: &funding, to: derived.address, info: info})
+	}
+
+	sendTransfers(ctx, transfers, ethClient, timeout)
+	for i := range output.Transfers {
+		output.Total.Add(output.Total, output.Transfers[i].Value)
+		output.Transfers[i].BalanceAfter = balanceOf(ctx, ethClient, accounts[i].address)
+	}
+	log.Printf("Funded %d account(s) up to %s ETH, total: %s ETH", len(transfers), utils.WeiToEther(target).String(), utils.WeiToEther(output.Total).String())
+	return output
+}
+
+// SweepDerivedAccounts sends the balance of each derived account, minus the transfer fee, back to Account.Key.
+// The accounts whose balance doesn't cover the fee, and Account.Key itself if it is derived, are skipped.
+//
+// Parameters:
+// - ctx: the context of the run (context.Context)
+// - deployerAddress: the address receiving the balances (common.Address)
+// - accounts: the derived accounts ([]sender)
+// - ethClient: the Ethereum client for interaction (backend.Client)
+// - timeout: the timeout for each batch of transfers to be mined, in seconds (int)
+// Return type:
+// - types.TransfersInformation
+func SweepDerivedAccounts(ctx context.Context, deployerAddress common.Address, accounts []sender, ethClient backend.Client, timeout int) types.TransfersInformation {
+	output := types.TransfersInformation{Mode: utils.SWEEP_MODE, Transfers: make([]types.TransferInformation, len(accounts)), Total: big.NewInt(0)}
+	var transfers []transfer
+	for i, derived := range accounts {
+		info := &output.Transfers[i]
+		*info = types.TransferInformation{Path: derived.path, From: derived.address.Hex(), To: deployerAddress.Hex(), Value: big.NewInt(0)}
+		if derived.address == deployerAddress {
+			info.Skipped = "receiving account"
+			continue
+		}
+		balance := balanceOf(ctx, ethClient, derived.address)
+		auth := GetSigner(ctx, ethClient, derived.address, derived.privateKey)
+		// A plain transfer uses exactly params.TxGas, so the account is left empty
+		fee := new(big.Int).Mul(auth.GasPrice, big.NewInt(int64(params.TxGas)))
+		if balance.Cmp(fee) <= 0 {
+			info.Skipped = "balance below the transfer fee"
+			continue
+		}
+		auth.Value = new(big.Int).Sub(balance, fee)
+		info.Value = auth.Value
+		transfers = append(transfers, transfer{auth: auth, to: deployerAddress, info: info})
+	}
+
+	sendTransfers(ctx, transfers, ethClient, timeout)
+	for i := range output.Transfers {
+		output.Total.Add(output.Total, output.Transfers[i].Value)
+		output.Transfers[i].BalanceAfter = balanceOf(ctx, ethClient, accounts[i].address)
+	}
+	log.Printf("Swept %d account(s) to %s, total: %s ETH", len(transfers), deployerAddress.Hex(), utils.WeiToEther(output.Total).String())
+	return output
+}
+
+// sendTransfers sends the transfers by batches of transferBatchSize, waiting for the receipts of a batch
+// before sending the next one, and records their transaction hashes
+func sendTransfers(ctx context.Context, transfers []transfer, ethClient backend.Client, timeout int) {
+	for start := 0; start < len(transfers); start += transferBatchSize {
+		batch := transfers[start:min(start+transferBatchSize, len(transfers))]
+		sent := make([]*gethtypes.Transaction, len(batch))
+		for i, t := range batch {
+			t.auth.GasLimit = params.TxGas
+			transaction, err := bind.NewBoundContract(t.to, abi.ABI{}, ethClient, ethClient, ethClient).Transfer(t.auth)
+			if err != nil {
+				transactions.ExitIfInterrupted(ctx)
+				utils.Fatalf("Failed to transfer %s ETH from %s to %s: %v", utils.WeiToEther(t.auth.Value).String(), t.auth.From.Hex(), t.to.Hex(), err)
+			}
+			t.info.TransactionHash = transaction.Hash().Hex()
+			sent[i] = transaction
+		}
+		log.Printf("Waiting for %d transfer(s) (%d/%d)", len(batch), start+len(batch), len(transfers))
+		for i, transaction := range sent {
+			receipt := transactions.WaitMined(ctx, ethClient, transaction, timeout)
+			if err := reverts.CheckReceipt(ctx, ethClient, "transfer to "+batch[i].to.Hex(), transaction, receipt); err != nil {
+				transactions.ExitIfInterrupted(ctx)
+				utils.Fatal(err)
+			}
+		}
+	}
+}
+
+func balanceOf(ctx context.Context, ethClient backend.Client, address common.Address) *big.Int {
+	balance, err := ethClient.BalanceAt(ctx, address, nil)
+	if err != nil {
+		transactions.ExitIfInterrupted(ctx)
+		utils.Fatalf("Could not get the balance of %s: %v", address.Hex(), err)
+	}
+	return balance
+}
@@ -1,9 +1,20 @@
 package utils
 
 import (
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"github.com/BurntSushi/toml"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39"
 	"log"
 	"main/src/config"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
 )
 
 const (
@@ -11,32 +22,89 @@ const (
 	DEPLOY_MODE    = "deploy-contract"
 	CALL_MODE      = "call-contract"
 	READ_ONLY_MODE = "read-only-contract"
+	ARTIFACT_MODE  = "deploy-artifact"
+	METHOD_MODE    = "call-method"
+	VIEW_MODE      = "read-method"
+	VERIFY_MODE    = "verify-bytecode"
+	UPGRADE_MODE   = "upgrade-contract"
+	LOAD_MODE      = "load-test"
+	FUND_MODE      = "fund"
+	SWEEP_MODE     = "sweep"
+	STORAGE_MODE   = "storage"
+	PREPARE_MODE   = "prepare"
+	SIGN_MODE      = "sign"
+	BROADCAST_MODE = "broadcast"
+	SAFE_MODE      = "safe-proposal"
+
+	SIGN_MESSAGE_MODE   = "sign-message"
+	VERIFY_MESSAGE_MODE = "verify-message"
 )
 
+// SIMULATED_RPC_URL is the RPC.Url value which runs the application against an in-process simulated chain
+const SIMULATED_RPC_URL = "simulated://"
+
 // GetConfig retrieves the configuration settings from the "config.toml" file and validates the configuration.
 //
-// No parameters are needed.
+// Parameters:
+// - overrides: optional function applying command line overrides before validation (func(*config.Config))
 // Returns:
 // - config.Config struct containing the fetched configurations.
-func GetConfig() config.Config {
+func GetConfig(overrides func(*config.Config)) config.Config {
 	var fetchedConfig config.Config
 
 	if _, err := toml.DecodeFile("config.toml", &fetchedConfig); err != nil {
-		log.Fatalf("Failed to parse fetchedConfig.toml: %v", err)
+		Fatalf("Failed to parse fetchedConfig.toml: %v", err)
+	}
+	if overrides != nil {
+		overrides(&fetchedConfig)
 	}
 
-	ValidateConfig(fetchedConfig)
+	if err := ValidateConfig(&fetchedConfig); err != nil {
+		Fatal(err)
+	}
 
 	return fetchedConfig
 }
 
-// ValidateConfig validates each "config.toml" field.
-func ValidateConfig(config config.Config) {
-	if config.RPC.Url == "" {
-		log.Fatal("config.toml: RPC.URL is required")
+// ValidateConfig validates each "config.toml" field and applies the defaults of the optional ones.
+//
+// Parameter:
+// - config: the configuration to validate, defaults are written into it (*config.Config)
+// Returns:
+// - error describing the first invalid field, nil if the configuration is valid
+func ValidateConfig(config *config.Config) error {
+	// The transactions and the messages are signed offline, without any connection to a node
+	if config.RPC.Url == "" && RequiresNode(config.Contract.Mode) {
+		return errors.New("config.toml: RPC.URL is required")
+	}
+	for _, rpcURL := range config.RPC.Urls {
+		if rpcURL == "" || rpcURL == SIMULATED_RPC_URL {
+			return errors.New("config.toml: RPC.Urls must only contain RPC endpoint URLs")
+		}
+	}
+	if config.RPC.Retries < 0 || config.RPC.RetryBackoff < 0 {
+		return errors.New("config.toml: RPC.Retries and RPC.RetryBackoff must not be negative")
+	}
+	if err := hasValidRPCAuth(config); err != nil {
+		return err
 	}
-	if config.Account.Key == "" {
-		log.Fatal("config.toml: Account.key is required")
+	if config.RPC.Retries == 0 {
+		config.RPC.Retries = 3
+	}
+	if config.RPC.RetryBackoff == 0 {
+		config.RPC.RetryBackoff = 500
+	}
+	// The prepared transactions are signed by the "sign" mode, the broadcast ones are already signed
+	if config.Account.Key == "" && !IsReadOnlyMode(config.Contract.Mode) && config.Contract.Mode != PREPARE_MODE && config.Contract.Mode != BROADCAST_MODE {
+		return fmt.Errorf("config.toml: Account.key is required, except for the read-only modes: %s, and the modes: %s, %s", strings.Join(readOnlyModes, ", "), PREPARE_MODE, BROADCAST_MODE)
+	}
+	if config.Account.From != "" {
+		if err := ValidateAddress(config.Account.From); err != nil {
+			return fmt.Errorf("config.toml: Account.from is invalid: %w", err)
+		}
+	}
+	if err := hasValidDerivedAccounts(config); err != nil {
+		return err
 	}
 	// Check if the gas limit is valid, defaulting to 3000000 if not provided
 	if config.Client.GasLimit <= 0 {
@@ -44,28 +112,438 @@ func ValidateConfig(config config.Config) {
 		log.Printf("config.toml: Client.gasLimit is 0 or less, defaulting to '%v'", defaultGasLimit)
 		config.Client.GasLimit = uint64(defaultGasLimit)
 	}
+	if config.Client.SafetyMargin < 0 {
+		return errors.New("config.toml: Client.SafetyMargin must not be negative")
+	}
 	if !isValidMode(config.Contract.Mode) {
-		log.Fatal("config.toml: Contract.mode is required, acceptable values: ", DEMO_MODE, DEPLOY_MODE, CALL_MODE, READ_ONLY_MODE)
+		return fmt.Errorf("config.toml: Contract.mode is required, acceptable values: %s", strings.Join(allowedModes, ", "))
 	}
 	log.Printf("config.toml: Application is running in mode (Contract.Mode): '%s'", config.Contract.Mode)
-	if config.Contract.Mode == "read-only-contract" {
-		hasContractAddress(config)
+	if config.Contract.Mode == READ_ONLY_MODE || config.Contract.Mode == VERIFY_MODE || config.Contract.Mode == UPGRADE_MODE || config.Contract.Mode == STORAGE_MODE {
+		if err := hasContractAddress(config); err != nil {
+			return err
+		}
 	}
-	if config.Contract.Mode == "call-contract" || config.Contract.Mode == "demo" {
-		hasContractAddress(config)
-		hasValuesToSet(config)
+	if config.Contract.Mode == LOAD_MODE {
+		if err := hasContractAddress(config); err != nil {
+			return err
+		}
+		if err := hasValidLoad(config); err != nil {
+			return err
+		}
 	}
-	if config.Contract.Address == "" { // optional
+	if config.Contract.Mode == PREPARE_MODE {
+		if config.Account.Key == "" && config.Account.From == "" {
+			return fmt.Errorf("config.toml: Account.From (or Account.Key) is required for the Contract.Mode: %s, it signs the prepared transactions", PREPARE_MODE)
+		}
+		if err := hasContractAddress(config); err != nil {
+			return err
+		}
+		if err := hasValuesToSet(config); err != nil {
+			return err
+		}
+	}
+	if IsOfflineMode(config.Contract.Mode) {
+		if err := hasValidOfflineFiles(config); err != nil {
+			return err
+		}
+	}
+	if config.Contract.Mode == SAFE_MODE {
+		if err := hasContractAddress(config); err != nil {
+			return err
+		}
+		if err := hasValuesToSet(config); err != nil {
+			return err
+		}
+		if err := hasValidSafe(config); err != nil {
+			return err
+		}
+	}
+	if config.Contract.Mode == SIGN_MESSAGE_MODE || config.Contract.Mode == VERIFY_MESSAGE_MODE {
+		if err := hasValidMessage(config); err != nil {
+			return err
+		}
+	}
+	if config.Contract.Mode == CALL_MODE || config.Contract.Mode == DEMO_MODE {
+		if err := hasContractAddress(config); err != nil {
+			return err
+		}
+		if err := hasValuesToSet(config); err != nil {
+			return err
+		}
+	}
+	if config.Contract.Mode == ARTIFACT_MODE {
+		if err := hasArtifact(config); err != nil {
+			return err
+		}
+	}
+	if config.Contract.Mode == METHOD_MODE || config.Contract.Mode == VIEW_MODE {
+		if err := hasContractAddress(config); err != nil {
+			return err
+		}
+		if err := hasMethod(config); err != nil {
+			return err
+		}
+	}
+	// optional, the fund and sweep modes only transfer ETH, the sign and broadcast modes take their transactions from a file
+	// and the message modes don't involve any contract
+	if config.Contract.Address == "" && !UsesExistingContract(config.Contract.Mode) && config.Contract.Mode != FUND_MODE && config.Contract.Mode != SWEEP_MODE &&
+		config.Contract.Mode != SIGN_MODE && config.Contract.Mode != BROADCAST_MODE && !isMessageMode(config.Contract.Mode) {
 		log.Print("config.toml: Contract.address is not provided. Either deploy a new or use an existing contract.")
 	}
+	if config.Contract.Create2 && config.Contract.Proxy {
+		return errors.New("config.toml: Contract.Create2 and Contract.Proxy can't be combined")
+	}
+	if config.Contract.Implementation != "" {
+		if err := ValidateAddress(config.Contract.Implementation); err != nil {
+			return fmt.Errorf("config.toml: Contract.Implementation is invalid: %w", err)
+		}
+	}
+	if _, err := ParseSalt(config.Contract.Salt); err != nil {
+		return fmt.Errorf("config.toml: Contract.Salt is invalid: %w", err)
+	}
+	if config.Contract.Registry == "" {
+		config.Contract.Registry = "deployments.json"
+	}
+	// The modes working with an existing contract must not silently map a malformed address to another one
+	if UsesExistingContract(config.Contract.Mode) && config.Contract.Address != "" {
+		if err := ValidateAddress(config.Contract.Address); err != nil {
+			return fmt.Errorf("config.toml: Contract.address is invalid: %w", err)
+		}
+	}
+	return nil
+}
+
+// readOnlyModes contains the modes which send no transactions, so they need neither a key nor funds
+var readOnlyModes = []string{READ_ONLY_MODE, VIEW_MODE, VERIFY_MODE, STORAGE_MODE, SAFE_MODE, VERIFY_MESSAGE_MODE}
+
+// IsReadOnlyMode checks if the mode only reads from the chain
+func IsReadOnlyMode(mode string) bool {
+	return hasMode(readOnlyModes, mode)
+}
+
+// offlineModes contains the modes splitting the sending of the setters in three steps, so the key never meets the network
+var offlineModes = []string{PREPARE_MODE, SIGN_MODE, BROADCAST_MODE}
+
+// IsOfflineMode checks if the mode is a step of the offline signing
+func IsOfflineMode(mode string) bool {
+	return hasMode(offlineModes, mode)
+}
+
+// transactionModes contains the modes sending transactions paid by Account.Key, so its balance must cover the run.
+// The sweep mode is paid by the derived accounts, the broadcast mode sends transactions signed beforehand.
+var transactionModes = []string{DEMO_MODE, DEPLOY_MODE, CALL_MODE, ARTIFACT_MODE, METHOD_MODE, UPGRADE_MODE, LOAD_MODE, FUND_MODE}
+
+// SendsTransactions checks if the mode sends transactions paid by Account.Key
+func SendsTransactions(mode string) bool {
+	return hasMode(transactionModes, mode)
+}
+
+// journaledModes contains the modes recording their transactions in the journal, so a run can be resumed.
+// A load test is run again rather than resumed, funding and sweeping again only sends what is left to send,
+// and a broadcast skips the transactions already mined.
+var journaledModes = []string{DEMO_MODE, DEPLOY_MODE, CALL_MODE, ARTIFACT_MODE, METHOD_MODE, UPGRADE_MODE}
+
+// RecordsJournal checks if the mode records its transactions in the journal
+func RecordsJournal(mode string) bool {
+	return hasMode(journaledModes, mode)
+}
+
+// getterSetterModes contains the modes working with the GetterSetter at Contract.Address, seeded into the simulated
+// chain and looked up in the registry as GetterSetter. The upgrade mode works with a GetterSetter behind a proxy.
+var getterSetterModes = []string{CALL_MODE, READ_ONLY_MODE, LOAD_MODE, STORAGE_MODE, PREPARE_MODE, SAFE_MODE}
+
+// UsesExistingGetterSetter checks if the mode works with the GetterSetter at Contract.Address
+func UsesExistingGetterSetter(mode string) bool {
+	return hasMode(getterSetterModes, mode)
+}
+
+func hasMode(modes []string, mode string) bool {
+	for _, m := range modes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// RequiresNode checks if the mode connects to a node. The offline signing of the transactions and the
+// signing and verifying of the messages only need the configuration.
+func RequiresNode(mode string) bool {
+	return mode != SIGN_MODE && !isMessageMode(mode)
+}
+
+// isMessageMode checks if the mode signs or verifies a message
+func isMessageMode(mode string) bool {
+	return mode == SIGN_MESSAGE_MODE || mode == VERIFY_MESSAGE_MODE
+}
+
+// UsesExistingContract checks if the mode interacts with the contract at Contract.Address
+func UsesExistingContract(mode string) bool {
+	switch mode {
+	case CALL_MODE, READ_ONLY_MODE, METHOD_MODE, VIEW_MODE, VERIFY_MODE, UPGRADE_MODE, LOAD_MODE, STORAGE_MODE, PREPARE_MODE, SAFE_MODE:
+		return true
+	}
+	return false
+}
+
+// ValidateAddress checks that the address is a 0x-prefixed, 20 bytes hex string and, when it is
+// mixed-case, that it has a valid EIP-55 checksum. All-lowercase and all-uppercase addresses
+// carry no checksum, they are accepted with a warning.
+//
+// Parameter:
+// - address: the address to validate (string)
+// Returns:
+// - error describing why the address is invalid, nil otherwise
+func ValidateAddress(address string) error {
+	if !strings.HasPrefix(address, "0x") || !common.IsHexAddress(address) {
+		return fmt.Errorf("'%s' is not a 0x-prefixed, 40 characters hex address", address)
+	}
+	hexPart := address[2:]
+	if hexPart == strings.ToLower(hexPart) || hexPart == strings.ToUpper(hexPart) {
+		log.Printf("Address '%s' has no EIP-55 checksum, double check it. Checksummed: '%s'", address, common.HexToAddress(address).Hex())
+		return nil
+	}
+	if checksummed := common.HexToAddress(address).Hex(); checksummed != address {
+		return fmt.Errorf("'%s' has an invalid EIP-55 checksum, expected '%s'", address, checksummed)
+	}
+	return nil
 }
 
-func hasContractAddress(config config.Config) {
+// ParseSalt parses a CREATE2 salt: a 0x-prefixed hex value of up to 32 bytes, left-padded with zeros.
+// An empty salt is the zero salt.
+//
+// Parameter:
+// - salt: the salt to parse (string)
+// Returns:
+// - common.Hash
+// - error if the salt is not a 0x-prefixed hex value of up to 32 bytes
+func ParseSalt(salt string) (common.Hash, error) {
+	if salt == "" {
+		return common.Hash{}, nil
+	}
+	digits := strings.TrimPrefix(salt, "0x")
+	if len(digits)%2 == 1 {
+		digits = "0" + digits
+	}
+	decoded, err := hex.DecodeString(digits)
+	if err != nil || !strings.HasPrefix(salt, "0x") {
+		return common.Hash{}, fmt.Errorf("'%s' is not a 0x-prefixed hex value", salt)
+	}
+	if len(decoded) > common.HashLength {
+		return common.Hash{}, fmt.Errorf("'%s' is longer than 32 bytes", salt)
+	}
+	return common.BytesToHash(decoded), nil
+}
+
+// ApplyNetworkProfile writes the values of the selected network profile (Config.Network) over the
+// [RPC], [Client] and [Contract] ones. Values not set in the profile are left as they are.
+//
+// Parameter:
+// - config: the configuration to update (*config.Config)
+// Returns:
+// - error if the selected profile does not exist or has no URL
+func ApplyNetworkProfile(config *config.Config) error {
+	if config.Network == "" {
+		return nil
+	}
+	network, ok := config.Networks[config.Network]
+	if !ok {
+		available := make([]string, 0, len(config.Networks))
+		for name := range config.Networks {
+			available = append(available, name)
+		}
+		sort.Strings(available)
+		return fmt.Errorf("config.toml: network profile '%s' is not defined, available profiles: [%s]", config.Network, strings.Join(available, ", "))
+	}
+	if network.Url == "" {
+		return fmt.Errorf("config.toml: Networks.%s.Url is required", config.Network)
+	}
+
+	// The fallback endpoints of the [RPC] section belong to another network
+	config.RPC.Url = network.Url
+	config.RPC.Urls = network.Urls
+	if network.ChainId != 0 {
+		config.RPC.ChainId = network.ChainId
+	}
+	if network.ExplorerUrl != "" {
+		config.RPC.ExplorerUrl = network.ExplorerUrl
+	}
+	if network.ContractAddress != "" {
+		config.Contract.Address = network.ContractAddress
+	}
+	if network.GasLimit != 0 {
+		config.Client.GasLimit = network.GasLimit
+	}
+	if network.WaitingTimeout != 0 {
+		config.Client.WaitingTimeout = network.WaitingTimeout
+	}
+	log.Printf("config.toml: Using the network profile: '%s'", config.Network)
+	return nil
+}
+
+// hasValidRPCAuth checks that a single Authorization scheme is configured and the TLS files come in pairs
+func hasValidRPCAuth(config *config.Config) error {
+	schemes := 0
+	for _, set := range []bool{config.RPC.BearerToken != "", config.RPC.Username != "" || config.RPC.Password != "", config.RPC.JwtSecret != ""} {
+		if set {
+			schemes++
+		}
+	}
+	if schemes > 1 {
+		return errors.New("config.toml: only one of RPC.BearerToken, RPC.Username/Password and RPC.JwtSecret can be set")
+	}
+	if (config.RPC.TlsCert == "") != (config.RPC.TlsKey == "") {
+		return errors.New("config.toml: RPC.TlsCert and RPC.TlsKey must be set together")
+	}
+	if config.RPC.Proxy != "" {
+		if proxy, err := url.Parse(config.RPC.Proxy); err != nil || proxy.Host == "" {
+			return errors.New("config.toml: RPC.Proxy must be a proxy URL, e.g. http://proxy.example.org:3128")
+		}
+	}
+	return nil
+}
+
+// hasValidDerivedAccounts checks the settings of the accounts derived from Account.Mnemonic and applies their defaults
+func hasValidDerivedAccounts(config *config.Config) error {
+	account := &config.Account
+	if account.Mnemonic == "" {
+		if account.Derived != 0 || account.DerivedSenders || config.Contract.Mode == FUND_MODE || config.Contract.Mode == SWEEP_MODE {
+			return fmt.Errorf("config.toml: Account.Mnemonic is required for the derived accounts and the modes: %s, %s", FUND_MODE, SWEEP_MODE)
+		}
+		return nil
+	}
+	if !bip39.IsMnemonicValid(account.Mnemonic) {
+		// The mnemonic itself must not end up in the logs
+		return errors.New("config.toml: Account.Mnemonic is not a valid BIP-39 mnemonic")
+	}
+	if account.Derived < 0 {
+		return errors.New("config.toml: Account.Derived must not be negative")
+	}
+	if account.Derived == 0 {
+		account.Derived = 10
+	}
+	if account.Funding == "" {
+		account.Funding = "0.1"
+	}
+	if _, err := EtherToWei(account.Funding); err != nil {
+		return fmt.Errorf("config.toml: Account.Funding is invalid: %w", err)
+	}
+	return nil
+}
+
+// hasValidLoad checks the [Load] settings and applies their defaults
+func hasValidLoad(config *config.Config) error {
+	load := &config.Load
+	if load.Transactions < 0 || load.Rate < 0 || load.Concurrency < 0 || load.MaxInFlight < 0 || load.PollInterval < 0 {
+		return errors.New("config.toml: the Load settings must not be negative")
+	}
+	if load.Transactions == 0 {
+		load.Transactions = 100
+	}
+	if load.Concurrency == 0 {
+		load.Concurrency = 4
+	}
+	if load.MaxInFlight == 0 {
+		load.MaxInFlight = 50
+	}
+	if load.PollInterval == 0 {
+		load.PollInterval = 200
+	}
+	for _, key := range load.Keys {
+		if _, err := crypto.HexToECDSA(key); err != nil {
+			// The key itself must not end up in the logs
+			return errors.New("config.toml: Load.Keys must only contain hex encoded private keys")
+		}
+	}
+	return nil
+}
+
+// hasValidOfflineFiles checks the files passed between the offline signing steps and applies their defaults
+func hasValidOfflineFiles(config *config.Config) error {
+	offline := &config.Offline
+	if offline.Unsigned == "" {
+		offline.Unsigned = "output/unsignedTransactions.json"
+	}
+	if offline.Signed == "" {
+		offline.Signed = "output/signedTransactions.json"
+	}
+	if filepath.Clean(offline.Unsigned) == filepath.Clean(offline.Signed) {
+		return errors.New("config.toml: Offline.Unsigned and Offline.Signed must be different files")
+	}
+	return nil
+}
+
+// hasValidSafe checks the addresses of the [Safe] section
+func hasValidSafe(config *config.Config) error {
+	if config.Safe.Address != "" {
+		if err := ValidateAddress(config.Safe.Address); err != nil {
+			return fmt.Errorf("config.toml: Safe.Address is invalid: %w", err)
+		}
+	} else if config.Safe.Nonce != nil {
+		return errors.New("config.toml: Safe.Nonce requires Safe.Address, the safeTxHash is computed for a Safe")
+	}
+	if config.Safe.MultiSend != "" {
+		if err := ValidateAddress(config.Safe.MultiSend); err != nil {
+			return fmt.Errorf("config.toml: Safe.MultiSend is invalid: %w", err)
+		}
+	}
+	return nil
+}
+
+// hasValidMessage checks that a single message is configured and, to verify it, a signature
+func hasValidMessage(config *config.Config) error {
+	message := config.Message
+	if (message.Text == "") == (message.TypedData == "") {
+		return fmt.Errorf("config.toml: exactly one of Message.Text and Message.TypedData is required for the Contract.Mode: %s", config.Contract.Mode)
+	}
+	if config.Contract.Mode == VERIFY_MESSAGE_MODE {
+		if signature, err := hexutil.Decode(message.Signature); err != nil || len(signature) != crypto.SignatureLength {
+			return fmt.Errorf("config.toml: Message.Signature must be a 0x-prefixed 65 bytes hex signature for the Contract.Mode: %s", VERIFY_MESSAGE_MODE)
+		}
+	}
+	if message.Signer != "" {
+		if err := ValidateAddress(message.Signer); err != nil {
+			return fmt.Errorf("config.toml: Message.Signer is invalid: %w", err)
+		}
+	}
+	return nil
+}
+
+// hasArtifact checks if either a JSON artifact or a pair of .abi and .bin files is configured
+func hasArtifact(config *config.Config) error {
+	artifact := config.Contract.Artifact
+	if artifact.Path == "" && (artifact.Abi == "" || artifact.Bin == "") {
+		return fmt.Errorf("config.toml: Contract.Artifact.Path, or both Contract.Artifact.Abi and Contract.Artifact.Bin, are required for the Contract.Mode: %s", config.Contract.Mode)
+	}
+	return nil
+}
+
+// hasMethod checks if the method name and an ABI (JSON artifact or .abi file) are configured
+func hasMethod(config *config.Config) error {
+	if config.Contract.Method.Name == "" {
+		return fmt.Errorf("config.toml: Contract.Method.Name is required for the Contract.Mode: %s", config.Contract.Mode)
+	}
+	if config.Contract.Artifact.Path == "" && config.Contract.Artifact.Abi == "" {
+		return fmt.Errorf("config.toml: Contract.Artifact.Path or Contract.Artifact.Abi is required for the Contract.Mode: %s", config.Contract.Mode)
+	}
+	return nil
+}
+
+func hasContractAddress(config *config.Config) error {
 	if config.Contract.Address == "" {
-		log.Fatal("config.toml: Contract.address is required to be set for the Contract.Mode: ", config.Contract.Mode)
+		// Resolved from the deployment registry once connected, by Contract.Alias or as the latest deployment on the chain
+		if UsesExistingContract(config.Contract.Mode) && config.RPC.Url != SIMULATED_RPC_URL {
+			return nil
+		}
+		return fmt.Errorf("config.toml: Contract.address is required to be set for the Contract.Mode: %s", config.Contract.Mode)
 	}
+	return nil
 }
 
+// allowedModes contains the list of valid Contract.Mode values
+var allowedModes = []string{DEMO_MODE, DEPLOY_MODE, CALL_MODE, READ_ONLY_MODE, ARTIFACT_MODE, METHOD_MODE, VIEW_MODE, VERIFY_MODE, UPGRADE_MODE, LOAD_MODE, FUND_MODE, SWEEP_MODE, STORAGE_MODE, PREPARE_MODE, SIGN_MODE, BROADCAST_MODE, SAFE_MODE, SIGN_MESSAGE_MODE, VERIFY_MESSAGE_MODE}
+
 // isValidMode checks if the Contract.Mode is valid.
 //
 // Parameter:
@@ -76,9 +554,6 @@ func hasContractAddress(config config.Config) {
 //
 //	bool - true if the mode is valid, false otherwise
 func isValidMode(mode string) bool {
-	// allowedModes contains the list of valid values'
-	var allowedModes = []string{DEMO_MODE, DEPLOY_MODE, CALL_MODE, READ_ONLY_MODE}
-
 	// Check if mode is in allowedModes list
 	for _, allowedMode := range allowedModes {
 		if allowedMode == mode {
@@ -90,13 +565,13 @@ func isValidMode(mode string) bool {
 
 // hasValuesToSet checks if at least one of the values to set is present
 //
-// Parameter: config of type config.Config
-// Returns: bool
-func hasValuesToSet(config config.Config) bool {
+// Parameter: config of type *config.Config
+// Returns: error if none of the values is present
+func hasValuesToSet(config *config.Config) error {
 	// Check if at least one of the values to set in the contracts
 	// otherwise, it is pointless to call the existing contract without setting any of the values
 	if config.Contract.Values.Uint256 == nil && config.Contract.Values.Bytes32 == "" && config.Contract.Values.Bytes == "" {
-		log.Fatal("config.toml: Ensure you have all or one of the possible values to set in the contract as follows: Contract.Uint256, Contract.Bytes32, Contract.Bytes")
+		return errors.New("config.toml: Ensure you have all or one of the possible values to set in the contract as follows: Contract.Uint256, Contract.Bytes32, Contract.Bytes")
 	}
-	return true
+	return nil
 }
@@ -0,0 +1,469 @@
+package utils
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"main/src/config"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func validConfig(mode string) config.Config {
+	return config.Config{
+		RPC:     config.RPC{Url: SIMULATED_RPC_URL},
+		Account: config.Account{Key: "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"},
+		Contract: config.Contract{
+			Mode:    mode,
+			Address: "0x5FbDB2315678afecb367f032d93F642f64180aa3",
+			Values:  config.Values{Uint256: big.NewInt(1), Bytes32: "Test", Bytes: "Test"},
+		},
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(c *config.Config)
+		wantErr bool
+	}{
+		{name: "demo mode", mutate: func(c *config.Config) {}},
+		{name: "deploy mode without address", mutate: func(c *config.Config) {
+			c.Contract.Mode = DEPLOY_MODE
+			c.Contract.Address = ""
+		}},
+		{name: "read-only mode", mutate: func(c *config.Config) { c.Contract.Mode = READ_ONLY_MODE }},
+		{name: "call mode with a single value", mutate: func(c *config.Config) {
+			c.Contract.Mode = CALL_MODE
+			c.Contract.Values = config.Values{Bytes: "Test"}
+		}},
+		{name: "missing RPC url", mutate: func(c *config.Config) { c.RPC.Url = "" }, wantErr: true},
+		{name: "fallback RPC urls", mutate: func(c *config.Config) {
+			c.RPC.Urls = []string{"https://fallback.example.org"}
+		}},
+		{name: "empty fallback RPC url", mutate: func(c *config.Config) { c.RPC.Urls = []string{""} }, wantErr: true},
+		{name: "negative retries", mutate: func(c *config.Config) { c.RPC.Retries = -1 }, wantErr: true},
+		{name: "RPC bearer token and headers", mutate: func(c *config.Config) {
+			c.RPC.Headers = map[string]string{"X-Api-Key": "key"}
+			c.RPC.BearerToken = "token"
+		}},
+		{name: "RPC bearer token and basic auth", mutate: func(c *config.Config) {
+			c.RPC.BearerToken = "token"
+			c.RPC.Username = "user"
+		}, wantErr: true},
+		{name: "RPC TLS certificate without key", mutate: func(c *config.Config) { c.RPC.TlsCert = "client.pem" }, wantErr: true},
+		{name: "RPC proxy without host", mutate: func(c *config.Config) { c.RPC.Proxy = "proxy" }, wantErr: true},
+		{name: "missing account key", mutate: func(c *config.Config) { c.Account.Key = "" }, wantErr: true},
+		{name: "read-only mode without account key", mutate: func(c *config.Config) {
+			c.Contract.Mode = READ_ONLY_MODE
+			c.Account.Key = ""
+		}},
+		{name: "storage mode without account key", mutate: func(c *config.Config) {
+			c.Contract.Mode = STORAGE_MODE
+			c.Account.Key = ""
+		}},
+		{name: "read-method mode with a from address", mutate: func(c *config.Config) {
+			c.Contract.Mode = VIEW_MODE
+			c.Contract.Artifact.Abi = "GetterSetter.abi"
+			c.Contract.Method.Name = "getUint256"
+			c.Account = config.Account{From: "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"}
+		}},
+		{name: "invalid from address", mutate: func(c *config.Config) {
+			c.Contract.Mode = READ_ONLY_MODE
+			c.Account = config.Account{From: "0x1234"}
+		}, wantErr: true},
+		{name: "unknown mode", mutate: func(c *config.Config) { c.Contract.Mode = "unknown" }, wantErr: true},
+		{name: "empty mode", mutate: func(c *config.Config) { c.Contract.Mode = "" }, wantErr: true},
+		{name: "read-only mode without address", mutate: func(c *config.Config) {
+			c.Contract.Mode = READ_ONLY_MODE
+			c.Contract.Address = ""
+		}, wantErr: true},
+		{name: "call mode without address", mutate: func(c *config.Config) {
+			c.Contract.Mode = CALL_MODE
+			c.Contract.Address = ""
+		}, wantErr: true},
+		{name: "read-only mode without address resolved from the registry", mutate: func(c *config.Config) {
+			c.RPC.Url = "https://rpc.example.org"
+			c.Contract.Mode = READ_ONLY_MODE
+			c.Contract.Address = ""
+		}},
+		{name: "call mode without address resolved by alias", mutate: func(c *config.Config) {
+			c.RPC.Url = "https://rpc.example.org"
+			c.Contract.Mode = CALL_MODE
+			c.Contract.Address = ""
+			c.Contract.Alias = "staging"
+		}},
+		{name: "create2 with a salt", mutate: func(c *config.Config) {
+			c.Contract.Create2 = true
+			c.Contract.Salt = "0x2a"
+		}},
+		{name: "invalid salt", mutate: func(c *config.Config) { c.Contract.Salt = "my salt" }, wantErr: true},
+		{name: "upgrade mode", mutate: func(c *config.Config) {
+			c.Contract.Mode = UPGRADE_MODE
+			c.Contract.Implementation = "0xe7f1725E7734CE288F8367e1Bb143E90bb3F0512"
+		}},
+		{name: "upgrade mode without address", mutate: func(c *config.Config) {
+			c.Contract.Mode = UPGRADE_MODE
+			c.Contract.Address = ""
+		}, wantErr: true},
+		{name: "invalid implementation", mutate: func(c *config.Config) {
+			c.Contract.Mode = UPGRADE_MODE
+			c.Contract.Implementation = "0x1234"
+		}, wantErr: true},
+		{name: "load mode", mutate: func(c *config.Config) {
+			c.Contract.Mode = LOAD_MODE
+			c.Load = config.Load{Transactions: 1000, Rate: 50, Keys: []string{"59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d"}}
+		}},
+		{name: "load mode without address", mutate: func(c *config.Config) {
+			c.Contract.Mode = LOAD_MODE
+			c.Contract.Address = ""
+		}, wantErr: true},
+		{name: "negative load rate", mutate: func(c *config.Config) {
+			c.Contract.Mode = LOAD_MODE
+			c.Load.Rate = -1
+		}, wantErr: true},
+		{name: "invalid load key", mutate: func(c *config.Config) {
+			c.Contract.Mode = LOAD_MODE
+			c.Load.Keys = []string{"0x1234"}
+		}, wantErr: true},
+		{name: "fund mode", mutate: func(c *config.Config) {
+			c.Contract.Mode = FUND_MODE
+			c.Contract.Address = ""
+			c.Account.Mnemonic = "test test test test test test test test test test test junk"
+			c.Account.Funding = "0.5"
+		}},
+		{name: "sweep mode without mnemonic", mutate: func(c *config.Config) { c.Contract.Mode = SWEEP_MODE }, wantErr: true},
+		{name: "derived senders without mnemonic", mutate: func(c *config.Config) { c.Account.DerivedSenders = true }, wantErr: true},
+		{name: "invalid mnemonic", mutate: func(c *config.Config) {
+			c.Account.Mnemonic = "test test test test test test test test test test test test"
+		}, wantErr: true},
+		{name: "invalid funding", mutate: func(c *config.Config) {
+			c.Account.Mnemonic = "test test test test test test test test test test test junk"
+			c.Account.Funding = "0.1 ETH"
+		}, wantErr: true},
+		{name: "create2 behind a proxy", mutate: func(c *config.Config) {
+			c.Contract.Create2 = true
+			c.Contract.Proxy = true
+		}, wantErr: true},
+		{name: "demo mode without address", mutate: func(c *config.Config) {
+			c.RPC.Url = "https://rpc.example.org"
+			c.Contract.Address = ""
+		}, wantErr: true},
+		{name: "read-only mode with a malformed address", mutate: func(c *config.Config) {
+			c.Contract.Mode = READ_ONLY_MODE
+			c.Contract.Address = "paste your GetterSetter deployed address"
+		}, wantErr: true},
+		{name: "call mode with an invalid checksum", mutate: func(c *config.Config) {
+			c.Contract.Mode = CALL_MODE
+			c.Contract.Address = "0x5FbDB2315678afecb367f032d93F642f64180Aa3"
+		}, wantErr: true},
+		{name: "deploy mode ignores the address", mutate: func(c *config.Config) {
+			c.Contract.Mode = DEPLOY_MODE
+			c.Contract.Address = "paste your GetterSetter deployed address"
+		}},
+		{name: "call mode without values", mutate: func(c *config.Config) {
+			c.Contract.Mode = CALL_MODE
+			c.Contract.Values = config.Values{}
+		}, wantErr: true},
+		{name: "prepare mode with a from address", mutate: func(c *config.Config) {
+			c.Contract.Mode = PREPARE_MODE
+			c.Account = config.Account{From: "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"}
+		}},
+		{name: "prepare mode without sender", mutate: func(c *config.Config) {
+			c.Contract.Mode = PREPARE_MODE
+			c.Account.Key = ""
+		}, wantErr: true},
+		{name: "prepare mode without values", mutate: func(c *config.Config) {
+			c.Contract.Mode = PREPARE_MODE
+			c.Contract.Values = config.Values{}
+		}, wantErr: true},
+		{name: "sign mode without RPC url", mutate: func(c *config.Config) {
+			c.Contract.Mode = SIGN_MODE
+			c.RPC.Url = ""
+		}},
+		{name: "sign mode without account key", mutate: func(c *config.Config) {
+			c.Contract.Mode = SIGN_MODE
+			c.Account.Key = ""
+		}, wantErr: true},
+		{name: "broadcast mode without account key", mutate: func(c *config.Config) {
+			c.Contract.Mode = BROADCAST_MODE
+			c.Account.Key = ""
+		}},
+		{name: "safe-proposal mode without account key", mutate: func(c *config.Config) {
+			c.Contract.Mode = SAFE_MODE
+			c.Account.Key = ""
+			c.Safe = config.Safe{Address: "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"}
+		}},
+		{name: "invalid Safe address", mutate: func(c *config.Config) {
+			c.Contract.Mode = SAFE_MODE
+			c.Safe = config.Safe{Address: "0x1234"}
+		}, wantErr: true},
+		{name: "Safe nonce without Safe address", mutate: func(c *config.Config) {
+			c.Contract.Mode = SAFE_MODE
+			nonce := uint64(3)
+			c.Safe = config.Safe{Nonce: &nonce}
+		}, wantErr: true},
+		{name: "same unsigned and signed files", mutate: func(c *config.Config) {
+			c.Contract.Mode = SIGN_MODE
+			c.Offline = config.Offline{Unsigned: "transactions.json", Signed: "./transactions.json"}
+		}, wantErr: true},
+		{name: "sign-message mode without RPC url", mutate: func(c *config.Config) {
+			c.Contract.Mode = SIGN_MESSAGE_MODE
+			c.RPC.Url = ""
+			c.Message = config.Message{Text: "Hello"}
+		}},
+		{name: "sign-message mode without message", mutate: func(c *config.Config) {
+			c.Contract.Mode = SIGN_MESSAGE_MODE
+		}, wantErr: true},
+		{name: "text and typed data message", mutate: func(c *config.Config) {
+			c.Contract.Mode = SIGN_MESSAGE_MODE
+			c.Message = config.Message{Text: "Hello", TypedData: "typedData.json"}
+		}, wantErr: true},
+		{name: "verify-message mode without account key", mutate: func(c *config.Config) {
+			c.Contract.Mode = VERIFY_MESSAGE_MODE
+			c.Account.Key = ""
+			c.Message = config.Message{Text: "Hello", Signature: "0x" + strings.Repeat("11", 65)}
+		}},
+		{name: "verify-message mode without signature", mutate: func(c *config.Config) {
+			c.Contract.Mode = VERIFY_MESSAGE_MODE
+			c.Message = config.Message{Text: "Hello"}
+		}, wantErr: true},
+		{name: "invalid expected signer", mutate: func(c *config.Config) {
+			c.Contract.Mode = VERIFY_MESSAGE_MODE
+			c.Message = config.Message{Text: "Hello", Signature: "0x" + strings.Repeat("11", 65), Signer: "0x1234"}
+		}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := validConfig(DEMO_MODE)
+			tt.mutate(&c)
+			err := ValidateConfig(&c)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateConfigDefaultsGasLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		gasLimit uint64
+		want     uint64
+	}{
+		{name: "not provided", gasLimit: 0, want: 3000000},
+		{name: "provided", gasLimit: 6000000, want: 6000000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := validConfig(DEMO_MODE)
+			c.Client.GasLimit = tt.gasLimit
+			if err := ValidateConfig(&c); err != nil {
+				t.Fatalf("ValidateConfig() unexpected error: %v", err)
+			}
+			if c.Client.GasLimit != tt.want {
+				t.Errorf("Client.GasLimit = %d, want %d", c.Client.GasLimit, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateConfigDefaultsLoad(t *testing.T) {
+	c := validConfig(LOAD_MODE)
+	c.Load.Concurrency = 8
+	if err := ValidateConfig(&c); err != nil {
+		t.Fatalf("ValidateConfig() unexpected error: %v", err)
+	}
+	want := config.Load{Transactions: 100, Concurrency: 8, MaxInFlight: 50, PollInterval: 200}
+	if !reflect.DeepEqual(c.Load, want) {
+		t.Errorf("Load = %+v, want %+v", c.Load, want)
+	}
+}
+
+func TestValidateAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		wantErr bool
+	}{
+		{name: "checksummed", address: "0x5FbDB2315678afecb367f032d93F642f64180aa3"},
+		{name: "lowercase", address: "0x5fbdb2315678afecb367f032d93f642f64180aa3"},
+		{name: "uppercase", address: "0x5FBDB2315678AFECB367F032D93F642F64180AA3"},
+		{name: "invalid checksum", address: "0x5FbDB2315678afecb367f032d93F642f64180Aa3", wantErr: true},
+		{name: "missing prefix", address: "5FbDB2315678afecb367f032d93F642f64180aa3", wantErr: true},
+		{name: "too short", address: "0x5FbDB2315678afecb367f032d93F642f64180a", wantErr: true},
+		{name: "too long", address: "0x5FbDB2315678afecb367f032d93F642f64180aa300", wantErr: true},
+		{name: "not hex", address: "0xpaste your GetterSetter deployed address", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateAddress(tt.address); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateAddress(%s) error = %v, wantErr %v", tt.address, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestApplyNetworkProfile(t *testing.T) {
+	networks := map[string]config.Network{
+		"sepolia": {
+			Url:             "https://sepolia.example.org",
+			Urls:            []string{"https://sepolia-fallback.example.org"},
+			ChainId:         11155111,
+			ContractAddress: "0x5FbDB2315678afecb367f032d93F642f64180aa3",
+			GasLimit:        5000000,
+			WaitingTimeout:  600,
+			ExplorerUrl:     "https://sepolia.etherscan.io",
+		},
+		"anvil":      {Url: "http://127.0.0.1:8545", ChainId: 31337},
+		"incomplete": {ChainId: 1},
+	}
+
+	tests := []struct {
+		name    string
+		network string
+		want    config.Config
+		wantErr bool
+	}{
+		{
+			name:    "no profile selected",
+			network: "",
+			want: config.Config{
+				RPC:      config.RPC{Url: "https://default.example.org", Urls: []string{"https://fallback.example.org"}},
+				Client:   config.Client{GasLimit: 3000000, WaitingTimeout: 300},
+				Contract: config.Contract{Address: "0xe7f1725E7734CE288F8367e1Bb143E90bb3F0512"},
+			},
+		},
+		{
+			name:    "full profile",
+			network: "sepolia",
+			want: config.Config{
+				RPC:      config.RPC{Url: "https://sepolia.example.org", Urls: []string{"https://sepolia-fallback.example.org"}, ChainId: 11155111, ExplorerUrl: "https://sepolia.etherscan.io"},
+				Client:   config.Client{GasLimit: 5000000, WaitingTimeout: 600},
+				Contract: config.Contract{Address: "0x5FbDB2315678afecb367f032d93F642f64180aa3"},
+			},
+		},
+		{
+			name:    "partial profile keeps the other values",
+			network: "anvil",
+			want: config.Config{
+				RPC:      config.RPC{Url: "http://127.0.0.1:8545", ChainId: 31337},
+				Client:   config.Client{GasLimit: 3000000, WaitingTimeout: 300},
+				Contract: config.Contract{Address: "0xe7f1725E7734CE288F8367e1Bb143E90bb3F0512"},
+			},
+		},
+		{name: "unknown profile", network: "mainnet", wantErr: true},
+		{name: "profile without url", network: "incomplete", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := config.Config{
+				Network:  tt.network,
+				Networks: networks,
+				RPC:      config.RPC{Url: "https://default.example.org", Urls: []string{"https://fallback.example.org"}},
+				Client:   config.Client{GasLimit: 3000000, WaitingTimeout: 300},
+				Contract: config.Contract{Address: "0xe7f1725E7734CE288F8367e1Bb143E90bb3F0512"},
+			}
+			err := ApplyNetworkProfile(&c)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ApplyNetworkProfile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(c.RPC, tt.want.RPC) || c.Client != tt.want.Client || c.Contract.Address != tt.want.Contract.Address {
+				t.Errorf("ApplyNetworkProfile() = %+v %+v %s, want %+v %+v %s", c.RPC, c.Client, c.Contract.Address, tt.want.RPC, tt.want.Client, tt.want.Contract.Address)
+			}
+		})
+	}
+}
+
+func TestParseSalt(t *testing.T) {
+	tests := []struct {
+		name    string
+		salt    string
+		want    common.Hash
+		wantErr bool
+	}{
+		{name: "empty", salt: "", want: common.Hash{}},
+		{name: "short", salt: "0x2a", want: common.HexToHash("0x2a")},
+		{name: "odd length", salt: "0x1", want: common.HexToHash("0x01")},
+		{name: "32 bytes", salt: "0x" + strings.Repeat("ab", 32), want: common.HexToHash("0x" + strings.Repeat("ab", 32))},
+		{name: "longer than 32 bytes", salt: "0x" + strings.Repeat("ab", 33), wantErr: true},
+		{name: "without 0x prefix", salt: "2a", wantErr: true},
+		{name: "not hex", salt: "0xsalt", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSalt(tt.salt)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSalt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSalt() = %s, want %s", got.Hex(), tt.want.Hex())
+			}
+		})
+	}
+}
+
+func TestModeProperties(t *testing.T) {
+	tests := []struct {
+		mode                     string
+		sendsTransactions        bool
+		recordsJournal           bool
+		usesExistingGetterSetter bool
+	}{
+		{mode: DEMO_MODE, sendsTransactions: true, recordsJournal: true},
+		{mode: DEPLOY_MODE, sendsTransactions: true, recordsJournal: true},
+		{mode: CALL_MODE, sendsTransactions: true, recordsJournal: true, usesExistingGetterSetter: true},
+		{mode: READ_ONLY_MODE, usesExistingGetterSetter: true},
+		{mode: ARTIFACT_MODE, sendsTransactions: true, recordsJournal: true},
+		{mode: METHOD_MODE, sendsTransactions: true, recordsJournal: true},
+		{mode: VIEW_MODE},
+		{mode: VERIFY_MODE},
+		{mode: UPGRADE_MODE, sendsTransactions: true, recordsJournal: true},
+		{mode: LOAD_MODE, sendsTransactions: true, usesExistingGetterSetter: true},
+		{mode: FUND_MODE, sendsTransactions: true},
+		{mode: SWEEP_MODE},
+		{mode: STORAGE_MODE, usesExistingGetterSetter: true},
+		{mode: PREPARE_MODE, usesExistingGetterSetter: true},
+		{mode: SIGN_MODE},
+		{mode: BROADCAST_MODE},
+		{mode: SAFE_MODE, usesExistingGetterSetter: true},
+		{mode: SIGN_MESSAGE_MODE},
+		{mode: VERIFY_MESSAGE_MODE},
+	}
+	if len(tests) != len(allowedModes) {
+		t.Fatalf("%d modes tested, want the %d allowed modes", len(tests), len(allowedModes))
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			if !isValidMode(tt.mode) {
+				t.Fatalf("%s is not an allowed mode", tt.mode)
+			}
+			if got := SendsTransactions(tt.mode); got != tt.sendsTransactions {
+				t.Errorf("SendsTransactions() = %t, want %t", got, tt.sendsTransactions)
+			}
+			if got := RecordsJournal(tt.mode); got != tt.recordsJournal {
+				t.Errorf("RecordsJournal() = %t, want %t", got, tt.recordsJournal)
+			}
+			if got := UsesExistingGetterSetter(tt.mode); got != tt.usesExistingGetterSetter {
+				t.Errorf("UsesExistingGetterSetter() = %t, want %t", got, tt.usesExistingGetterSetter)
+			}
+			// The journaled transactions are paid by Account.Key and the GetterSetter modes need the contract
+			if RecordsJournal(tt.mode) && !SendsTransactions(tt.mode) {
+				t.Error("the mode records a journal but sends no transactions")
+			}
+			if SendsTransactions(tt.mode) && (IsReadOnlyMode(tt.mode) || IsOfflineMode(tt.mode) || !RequiresNode(tt.mode)) {
+				t.Error("the mode sends transactions but is read-only, offline or without a node")
+			}
+			if UsesExistingGetterSetter(tt.mode) && !UsesExistingContract(tt.mode) {
+				t.Error("the mode works with the GetterSetter at Contract.Address but doesn't use an existing contract")
+			}
+		})
+	}
+}
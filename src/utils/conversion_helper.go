@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"fmt"
 	"github.com/ethereum/go-ethereum/params"
 	"math/big"
 )
@@ -8,3 +9,22 @@ import (
 func WeiToEther(wei *big.Int) *big.Float {
 	return new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(params.Ether))
 }
+
+// EtherToWei converts an ETH amount, e.g. "0.1", to wei
+//
+// Parameter:
+// - ether: the decimal amount of ETH (string)
+// Returns:
+// - *big.Int: the amount in wei
+// - error if the amount is not a non-negative decimal number or has more than 18 decimals
+func EtherToWei(ether string) (*big.Int, error) {
+	amount, ok := new(big.Rat).SetString(ether)
+	if !ok || amount.Sign() < 0 {
+		return nil, fmt.Errorf("'%s' is not a non-negative ETH amount", ether)
+	}
+	wei := amount.Mul(amount, new(big.Rat).SetInt64(params.Ether))
+	if !wei.IsInt() {
+		return nil, fmt.Errorf("'%s' has more than 18 decimals", ether)
+	}
+	return wei.Num(), nil
+}
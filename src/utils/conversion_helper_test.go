@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestWeiToEther(t *testing.T) {
+	tests := []struct {
+		name string
+		wei  string
+		want string
+	}{
+		{name: "zero", wei: "0", want: "0"},
+		{name: "one wei", wei: "1", want: "0.000000000000000001"},
+		{name: "one gwei", wei: "1000000000", want: "0.000000001"},
+		{name: "one ether", wei: "1000000000000000000", want: "1"},
+		{name: "fractional ether", wei: "1500000000000000000", want: "1.5"},
+		{name: "large balance", wei: "123456000000000000000000", want: "123456"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wei, ok := new(big.Int).SetString(tt.wei, 10)
+			if !ok {
+				t.Fatalf("invalid test input: %s", tt.wei)
+			}
+			want, _, err := big.ParseFloat(tt.want, 10, 256, big.ToNearestEven)
+			if err != nil {
+				t.Fatalf("invalid test expectation: %s", tt.want)
+			}
+			got := WeiToEther(wei)
+			// WeiToEther works with the default big.Float precision, so compare with a relative tolerance
+			diff := new(big.Float).Sub(got, want)
+			tolerance := new(big.Float).Mul(want, big.NewFloat(1e-12))
+			if diff.Abs(diff).Cmp(tolerance) > 0 {
+				t.Errorf("WeiToEther(%s) = %s, want %s", tt.wei, got.Text('f', 18), tt.want)
+			}
+		})
+	}
+}
+
+func TestEtherToWei(t *testing.T) {
+	tests := []struct {
+		name    string
+		ether   string
+		want    string
+		wantErr bool
+	}{
+		{name: "zero", ether: "0", want: "0"},
+		{name: "fractional ether", ether: "0.1", want: "100000000000000000"},
+		{name: "one wei", ether: "0.000000000000000001", want: "1"},
+		{name: "large amount", ether: "123456", want: "123456000000000000000000"},
+		{name: "too many decimals", ether: "0.0000000000000000001", wantErr: true},
+		{name: "negative", ether: "-1", wantErr: true},
+		{name: "not a number", ether: "one", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EtherToWei(tt.ether)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EtherToWei(%s) error = %v, wantErr %v", tt.ether, err, tt.wantErr)
+			}
+			if !tt.wantErr && got.String() != tt.want {
+				t.Errorf("EtherToWei(%s) = %s, want %s", tt.ether, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+)
+
+// exitHooks are run before the application exits on a fatal error, in reverse order of registration as deferred
+// calls would be. os.Exit skips the deferred calls, so the reports written at the end of the run are registered here.
+var exitHooks = struct {
+	sync.Mutex
+	hooks []func()
+}{}
+
+// osExit ends the process, exit is replaced by the tests
+var (
+	osExit = os.Exit
+	exit   = osExit
+)
+
+// AtExit registers a function run by Exit, Fatal and Fatalf, and by RunExitHooks at the end of a successful run
+func AtExit(hook func()) {
+	exitHooks.Lock()
+	defer exitHooks.Unlock()
+	exitHooks.hooks = append(exitHooks.hooks, hook)
+}
+
+// RunExitHooks runs the registered hooks once, the last registered first. A hook failing with Fatal doesn't
+// run them again.
+func RunExitHooks() {
+	exitHooks.Lock()
+	hooks := exitHooks.hooks
+	exitHooks.hooks = nil
+	exitHooks.Unlock()
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hooks[i]()
+	}
+}
+
+// Exit runs the registered hooks, then exits with the given code
+func Exit(code int) {
+	RunExitHooks()
+	exit(code)
+}
+
+// Fatal logs like log.Fatal, runs the registered hooks, then exits with the code 1
+func Fatal(v ...interface{}) {
+	log.Output(2, fmt.Sprint(v...))
+	Exit(1)
+}
+
+// Fatalf logs like log.Fatalf, runs the registered hooks, then exits with the code 1
+func Fatalf(format string, v ...interface{}) {
+	log.Output(2, fmt.Sprintf(format, v...))
+	Exit(1)
+}
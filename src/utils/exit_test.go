@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExitRunsTheHooksOnce(t *testing.T) {
+	var code int
+	exit = func(c int) { code = c }
+	t.Cleanup(func() { exit = osExit })
+
+	var calls []string
+	AtExit(func() { calls = append(calls, "report") })
+	AtExit(func() {
+		calls = append(calls, "traces")
+		// A hook failing doesn't run the hooks again
+		Fatal("failed to write the traces")
+	})
+	Exit(130)
+
+	if want := []string{"traces", "report"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("hooks called = %v, want %v", calls, want)
+	}
+	if code != 130 {
+		t.Errorf("exit code = %d, want 130", code)
+	}
+	RunExitHooks()
+	if len(calls) != 2 {
+		t.Errorf("hooks called again: %v", calls)
+	}
+}
@@ -21,7 +21,7 @@ func createDirectoryIfNotExists(dir string) error {
 func JsonWriter(contractInformation interface{}, filePath string) {
 	err := createDirectoryIfNotExists(filepath.Dir(filePath))
 	if err != nil {
-		log.Fatal("Error creating directory:", err)
+		Fatal("Error creating directory:", err)
 		return
 	}
 
@@ -33,7 +33,7 @@ func JsonWriter(contractInformation interface{}, filePath string) {
 
 	err = os.WriteFile(filePath, jsonData, 0644)
 	if err != nil {
-		log.Fatal("Error writing JSON file:", err)
+		Fatal("Error writing JSON file:", err)
 		return
 	}
 	log.Println("Contract data is written to JSON file successfully!")